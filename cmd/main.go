@@ -9,6 +9,8 @@ import (
 	handler "main-server/pkg/handler"
 	repository "main-server/pkg/repository"
 	"main-server/pkg/service"
+	authService "main-server/pkg/service/auth"
+	passwordService "main-server/pkg/service/password"
 	"os"
 	"os/signal"
 	"syscall"
@@ -102,9 +104,57 @@ func main() {
 		logrus.Fatalf("failed to initialize db: %s", err.Error())
 	}
 
-	// Инициализация OAuth2 сервисов
-	config.InitOAuth2Config()
-	config.InitVKAuthConfig()
+	// Инициализация внешних провайдеров OAuth2
+	if err := authService.InitProviders(); err != nil {
+		logrus.Fatalf("failed to initialize oauth2 providers: %s", err.Error())
+	}
+
+	// Инициализация ключей подписи токенов доступа
+	if err := config.InitJWTKeys(); err != nil {
+		logrus.Fatalf("failed to initialize jwt keys: %s", err.Error())
+	}
+
+	// Инициализация политики двухфакторной аутентификации
+	config.InitMfaPolicy()
+
+	// Инициализация политики подтверждения аккаунта
+	if err := config.InitActivationPolicy(); err != nil {
+		logrus.Fatalf("failed to initialize activation policy: %s", err.Error())
+	}
+
+	// Инициализация списка доверенных прокси-серверов
+	if err := config.InitHTTPPolicy(); err != nil {
+		logrus.Fatalf("failed to initialize http policy: %s", err.Error())
+	}
+
+	// Инициализация политики защиты от перебора паролей
+	if err := config.InitLockoutPolicy(); err != nil {
+		logrus.Fatalf("failed to initialize lockout policy: %s", err.Error())
+	}
+
+	// Инициализация списка отозванных токенов доступа
+	if err := config.InitDenylistPolicy(); err != nil {
+		logrus.Fatalf("failed to initialize token denylist: %s", err.Error())
+	}
+
+	// Инициализация политики паролей и списка скомпрометированных паролей
+	if err := passwordService.InitPolicy(); err != nil {
+		logrus.Fatalf("failed to initialize password policy: %s", err.Error())
+	}
+
+	if err := passwordService.InitHasher(); err != nil {
+		logrus.Fatalf("failed to initialize password hasher: %s", err.Error())
+	}
+
+	// Инициализация политик ограничения частоты запросов
+	if err := config.InitRateLimitPolicies(); err != nil {
+		logrus.Fatalf("failed to initialize rate limit policies: %s", err.Error())
+	}
+
+	// Инициализация параметров входа по ключам доступа (WebAuthn)
+	if err := config.InitWebAuthn(); err != nil {
+		logrus.Fatalf("failed to initialize webauthn: %s", err.Error())
+	}
 
 	// Dependency Injection
 	repos := repository.NewRepository(db, enforcer)
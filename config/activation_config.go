@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	authConstants "main-server/pkg/constant/auth"
+
+	"github.com/spf13/viper"
+)
+
+/*
+* Политика подтверждения аккаунта в файле конфигурации:
+*
+*	activation:
+*	  policy: "limited" # none | limited | block
+*	  link_ttl: "24h"
+*
+* none    - неподтверждённый аккаунт работает без ограничений;
+* limited - до подтверждения доступны только профиль, сессии и повторная отправка письма;
+* block   - вход в систему невозможен до подтверждения аккаунта.
+* Если параметры не заданы, используется политика limited и время жизни ссылки TOKEN_TLL_ACTIVATION
+ */
+type ActivationPolicy struct {
+	Mode    string
+	LinkTTL time.Duration
+}
+
+var AppActivationPolicy ActivationPolicy
+
+/* Инициализация политики подтверждения аккаунта */
+func InitActivationPolicy() error {
+	mode := authConstants.ACTIVATION_POLICY_LIMITED
+	if viper.IsSet("activation.policy") {
+		mode = viper.GetString("activation.policy")
+	}
+
+	switch mode {
+	case authConstants.ACTIVATION_POLICY_NONE, authConstants.ACTIVATION_POLICY_LIMITED, authConstants.ACTIVATION_POLICY_BLOCK:
+	default:
+		return fmt.Errorf("unknown activation policy: %s", mode)
+	}
+
+	ttl := authConstants.TOKEN_TLL_ACTIVATION
+	if viper.IsSet("activation.link_ttl") {
+		ttl = viper.GetDuration("activation.link_ttl")
+	}
+
+	if ttl <= 0 {
+		return fmt.Errorf("activation link ttl must be positive")
+	}
+
+	AppActivationPolicy = ActivationPolicy{
+		Mode:    mode,
+		LinkTTL: ttl,
+	}
+
+	return nil
+}
+
+/* Проверка, должен ли неподтверждённый аккаунт ограничиваться в доступе */
+func (p ActivationPolicy) IsEnforced() bool {
+	return p.Mode != authConstants.ACTIVATION_POLICY_NONE
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	authConstants "main-server/pkg/constant/auth"
+
+	"github.com/spf13/viper"
+)
+
+/*
+* Параметры списка отозванных токенов доступа в файле конфигурации:
+*
+*	token_denylist:
+*	  store: "memory"          # memory | postgres (postgres - для нескольких экземпляров сервера)
+*	  sweep_interval: "1m"     # интервал удаления истёкших записей (только для memory)
+ */
+type DenylistPolicy struct {
+	Store         string
+	SweepInterval time.Duration
+}
+
+var AppDenylistPolicy DenylistPolicy
+
+/* Инициализация параметров списка отозванных токенов доступа */
+func InitDenylistPolicy() error {
+	viper.SetDefault("token_denylist.store", authConstants.DENYLIST_STORE_MEMORY)
+	viper.SetDefault("token_denylist.sweep_interval", time.Minute)
+
+	policy := DenylistPolicy{
+		Store:         viper.GetString("token_denylist.store"),
+		SweepInterval: viper.GetDuration("token_denylist.sweep_interval"),
+	}
+
+	if policy.Store != authConstants.DENYLIST_STORE_MEMORY && policy.Store != authConstants.DENYLIST_STORE_POSTGRES {
+		return fmt.Errorf("unknown token denylist store: %s", policy.Store)
+	}
+
+	if policy.SweepInterval <= 0 {
+		return fmt.Errorf("token denylist sweep interval must be positive")
+	}
+
+	AppDenylistPolicy = policy
+
+	return nil
+}
@@ -1,7 +1,51 @@
 package config
 
-import "net/http"
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/viper"
+)
 
 const (
 	HTTPSameSite = http.SameSiteStrictMode
 )
+
+/*
+* Доверенные прокси-серверы в файле конфигурации:
+*
+*	http:
+*	  trusted_proxies: ["10.0.0.0/8", "192.168.1.10"]
+*
+* Адрес клиента берётся из X-Forwarded-For только для запросов от этих адресов.
+* Без списка заголовки прокси игнорируются и используется адрес соединения
+ */
+var HTTPTrustedProxies []string
+
+/* Инициализация списка доверенных прокси-серверов */
+func InitHTTPPolicy() error {
+	proxies := make([]string, 0)
+
+	for _, item := range viper.GetStringSlice("http.trusted_proxies") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		if _, _, err := net.ParseCIDR(item); err != nil && net.ParseIP(item) == nil {
+			return fmt.Errorf("invalid trusted proxy: %s", item)
+		}
+
+		proxies = append(proxies, item)
+	}
+
+	if len(proxies) <= 0 {
+		proxies = nil
+	}
+
+	HTTPTrustedProxies = proxies
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package config
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	authConstants "main-server/pkg/constant/auth"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/spf13/viper"
+)
+
+/*
+* Описание ключа подписи в файле конфигурации:
+*
+*	token:
+*	  keys_grace: 2h
+*	  secret_accept_until: "2026-10-20T00:00:00Z"
+*	  keys:
+*	    - kid: "2026-10"
+*	      path: "keys/2026-10.pem"
+*	    - kid: "2026-04"
+*	      path: "keys/2026-04.pem"
+*	      retired_at: "2026-10-01T00:00:00Z"
+*
+* Подписывает токены первый ключ без retired_at, выведенные из оборота ключи
+* принимаются при проверке подписи ещё keys_grace после даты retired_at.
+* Токены доступа без kid, подписанные общим секретом, при наличии действующего ключа
+* подписи принимаются только до даты secret_accept_until (на время перехода на ключи)
+ */
+type JWTKeyConfig struct {
+	Kid       string `mapstructure:"kid"`
+	Path      string `mapstructure:"path"`
+	RetiredAt string `mapstructure:"retired_at"`
+}
+
+/* Загруженный ключ подписи JWT-токенов */
+type JWTKey struct {
+	Kid       string
+	Method    jwt.SigningMethod
+	Private   crypto.PrivateKey // nil, если в файле только открытый ключ
+	Public    crypto.PublicKey
+	RetiredAt *time.Time
+}
+
+/* Набор ключей подписи JWT-токенов доступа */
+type JWTKeySet struct {
+	Keys         []*JWTKey
+	Grace        time.Duration
+	SecretBefore *time.Time // Граница приёма токенов доступа, подписанных общим секретом
+}
+
+var AppJWTKeys JWTKeySet
+
+/* Инициализация набора ключей подписи из файла конфигурации */
+func InitJWTKeys() error {
+	var items []JWTKeyConfig
+	if err := viper.UnmarshalKey("token.keys", &items); err != nil {
+		return err
+	}
+
+	keys := make([]*JWTKey, 0, len(items))
+	for _, item := range items {
+		key, err := loadJWTKey(item)
+		if err != nil {
+			return fmt.Errorf("jwt key %s: %s", item.Kid, err.Error())
+		}
+
+		keys = append(keys, key)
+	}
+
+	grace := viper.GetDuration("token.keys_grace")
+	if grace <= 0 {
+		grace = authConstants.TOKEN_TLL_ACCESS
+	}
+
+	var secretBefore *time.Time
+	if value := viper.GetString("token.secret_accept_until"); value != "" {
+		date, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("token.secret_accept_until: %s", err.Error())
+		}
+
+		secretBefore = &date
+	}
+
+	AppJWTKeys = JWTKeySet{
+		Keys:         keys,
+		Grace:        grace,
+		SecretBefore: secretBefore,
+	}
+
+	if len(keys) > 0 {
+		if _, err := AppJWTKeys.SigningKey(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/* Загрузка ключа из PEM-файла (PKCS#8, PKCS#1 или открытый ключ PKIX) */
+func loadJWTKey(item JWTKeyConfig) (*JWTKey, error) {
+	if item.Kid == "" {
+		return nil, errors.New("kid is empty")
+	}
+
+	data, err := os.ReadFile(item.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("pem block not found")
+	}
+
+	key := &JWTKey{Kid: item.Kid}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key.Private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key.Private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		key.Public, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported pem block %s", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch private := key.Private.(type) {
+	case *rsa.PrivateKey:
+		key.Public = &private.PublicKey
+	case ed25519.PrivateKey:
+		key.Public = private.Public()
+	case nil:
+	default:
+		return nil, errors.New("unsupported private key type")
+	}
+
+	switch key.Public.(type) {
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.Method = SigningMethodEdDSA
+	default:
+		return nil, errors.New("unsupported public key type")
+	}
+
+	if item.RetiredAt != "" {
+		retiredAt, err := time.Parse(time.RFC3339, item.RetiredAt)
+		if err != nil {
+			return nil, err
+		}
+
+		key.RetiredAt = &retiredAt
+	}
+
+	return key, nil
+}
+
+/* Проверка наличия асимметричных ключей подписи */
+func (s *JWTKeySet) Enabled() bool {
+	return len(s.Keys) > 0
+}
+
+/*
+* Проверка, принимаются ли токены доступа без kid, подписанные общим секретом. Пока действующего
+* ключа подписи нет, токены подписываются секретом, после перехода - только до даты SecretBefore
+ */
+func (s *JWTKeySet) AcceptsSecret() bool {
+	if _, err := s.SigningKey(); err != nil {
+		return true
+	}
+
+	return s.SecretBefore != nil && time.Now().Before(*s.SecretBefore)
+}
+
+/* Получение текущего ключа подписи */
+func (s *JWTKeySet) SigningKey() (*JWTKey, error) {
+	now := time.Now()
+
+	for _, key := range s.Keys {
+		if key.Private != nil && (key.RetiredAt == nil || now.Before(*key.RetiredAt)) {
+			return key, nil
+		}
+	}
+
+	return nil, errors.New("active jwt signing key not found")
+}
+
+/* Получение открытого ключа для проверки подписи токена */
+func (s *JWTKeySet) VerifyKey(kid string, method jwt.SigningMethod) (crypto.PublicKey, error) {
+	for _, key := range s.PublicKeys() {
+		if key.Kid != kid {
+			continue
+		}
+
+		// Алгоритм токена должен совпадать с алгоритмом ключа
+		if key.Method.Alg() != method.Alg() {
+			return nil, errors.New("invalid signing method")
+		}
+
+		return key.Public, nil
+	}
+
+	return nil, fmt.Errorf("unknown signing key %s", kid)
+}
+
+/* Получение ключей, которые принимаются при проверке подписи в данный момент */
+func (s *JWTKeySet) PublicKeys() []*JWTKey {
+	now := time.Now()
+	keys := make([]*JWTKey, 0, len(s.Keys))
+
+	for _, key := range s.Keys {
+		if key.RetiredAt != nil && now.After(key.RetiredAt.Add(s.Grace)) {
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
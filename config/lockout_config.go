@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	authConstants "main-server/pkg/constant/auth"
+
+	"github.com/spf13/viper"
+)
+
+/*
+* Политика защиты от перебора паролей в файле конфигурации:
+*
+*	lockout:
+*	  store: "memory"          # memory | postgres (postgres - для нескольких экземпляров сервера)
+*	  account_threshold: 5     # неудачных попыток входа в аккаунт (и ввода второго фактора) до блокировки
+*	  ip_threshold: 20         # неудачных попыток входа с одного IP-адреса до блокировки
+*	  recovery_threshold: 3    # писем восстановления пароля на один адрес до блокировки
+*	  base_delay: "30s"        # первая блокировка, каждая следующая - вдвое дольше
+*	  max_delay: "1h"
+*	  window: "1h"             # счётчик сбрасывается, если в течение окна не было неудач
+ */
+type LockoutPolicy struct {
+	Store             string
+	AccountThreshold  int
+	IpThreshold       int
+	RecoveryThreshold int
+	BaseDelay         time.Duration
+	MaxDelay          time.Duration
+	Window            time.Duration
+}
+
+var AppLockoutPolicy LockoutPolicy
+
+/* Инициализация политики защиты от перебора паролей */
+func InitLockoutPolicy() error {
+	viper.SetDefault("lockout.store", authConstants.LOCKOUT_STORE_MEMORY)
+	viper.SetDefault("lockout.account_threshold", 5)
+	viper.SetDefault("lockout.ip_threshold", 20)
+	viper.SetDefault("lockout.recovery_threshold", 3)
+	viper.SetDefault("lockout.base_delay", 30*time.Second)
+	viper.SetDefault("lockout.max_delay", time.Hour)
+	viper.SetDefault("lockout.window", time.Hour)
+
+	policy := LockoutPolicy{
+		Store:             viper.GetString("lockout.store"),
+		AccountThreshold:  viper.GetInt("lockout.account_threshold"),
+		IpThreshold:       viper.GetInt("lockout.ip_threshold"),
+		RecoveryThreshold: viper.GetInt("lockout.recovery_threshold"),
+		BaseDelay:         viper.GetDuration("lockout.base_delay"),
+		MaxDelay:          viper.GetDuration("lockout.max_delay"),
+		Window:            viper.GetDuration("lockout.window"),
+	}
+
+	if policy.Store != authConstants.LOCKOUT_STORE_MEMORY && policy.Store != authConstants.LOCKOUT_STORE_POSTGRES {
+		return fmt.Errorf("unknown lockout store: %s", policy.Store)
+	}
+
+	if policy.AccountThreshold <= 0 || policy.IpThreshold <= 0 || policy.RecoveryThreshold <= 0 {
+		return fmt.Errorf("lockout thresholds must be positive")
+	}
+
+	if policy.BaseDelay <= 0 || policy.MaxDelay < policy.BaseDelay || policy.Window <= 0 {
+		return fmt.Errorf("invalid lockout delays")
+	}
+
+	AppLockoutPolicy = policy
+
+	return nil
+}
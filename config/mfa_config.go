@@ -0,0 +1,53 @@
+package config
+
+import (
+	roleConstants "main-server/pkg/constant/role"
+
+	"github.com/spf13/viper"
+)
+
+/*
+* Политика двухфакторной аутентификации в файле конфигурации:
+*
+*	mfa:
+*	  issuer: "main-server"
+*	  required_roles: ["admin", "super_admin"]
+*
+* Пользователи с одной из ролей required_roles не могут войти в систему без второго фактора.
+* Если параметр не задан, второй фактор обязателен для администраторов
+ */
+type MfaPolicy struct {
+	Issuer        string
+	RequiredRoles []string
+}
+
+var AppMfaPolicy MfaPolicy
+
+/* Инициализация политики двухфакторной аутентификации */
+func InitMfaPolicy() {
+	roles := []string{roleConstants.ROLE_ADMIN, roleConstants.ROLE_SUPER_ADMIN}
+	if viper.IsSet("mfa.required_roles") {
+		roles = viper.GetStringSlice("mfa.required_roles")
+	}
+
+	issuer := viper.GetString("mfa.issuer")
+	if issuer == "" {
+		issuer = viper.GetString("domain")
+	}
+
+	AppMfaPolicy = MfaPolicy{
+		Issuer:        issuer,
+		RequiredRoles: roles,
+	}
+}
+
+/* Проверка, требует ли роль двухфакторной аутентификации */
+func (p MfaPolicy) IsRequired(role string) bool {
+	for _, item := range p.RequiredRoles {
+		if item == role {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	middlewareConstants "main-server/pkg/constant/middleware"
+
+	"github.com/spf13/viper"
+)
+
+/*
+* Политики ограничения частоты запросов в файле конфигурации (по группам маршрутов):
+*
+*	rate_limit:
+*	  policies:
+*	    auth:  { limit: 30, period: "1m", key: "ip" }
+*	    api:   { limit: 300, period: "1m", burst: 60, key: "user" }
+*	    admin: { limit: 60, period: "1m", key: "user" }
+*	    email: { limit: 10, period: "1h", key: "user" }
+*
+* Группы маршрутов user, admin и service ограничиваются отдельно (у каждой своя корзина),
+* незаданные для них параметры берутся из политики api.
+* key - ключ корзины: ip (IP-адрес клиента), user (UUID пользователя, для анонимных запросов - IP-адрес)
+* или route (общий лимит маршрута). Политика с limit: 0 отключена.
+* Незаданные параметры и политики берутся из значений по умолчанию
+ */
+type RateLimitPolicy struct {
+	Limit  int           `mapstructure:"limit"`
+	Period time.Duration `mapstructure:"period"`
+	Burst  int           `mapstructure:"burst"`
+	Key    string        `mapstructure:"key"`
+}
+
+var AppRateLimitPolicies map[string]RateLimitPolicy
+
+/* Инициализация политик ограничения частоты запросов */
+func InitRateLimitPolicies() error {
+	policies := map[string]RateLimitPolicy{
+		middlewareConstants.RATE_LIMIT_POLICY_AUTH:  {Limit: 30, Period: time.Minute, Key: middlewareConstants.RATE_LIMIT_KEY_IP},
+		middlewareConstants.RATE_LIMIT_POLICY_API:   {Limit: 300, Period: time.Minute, Key: middlewareConstants.RATE_LIMIT_KEY_USER},
+		middlewareConstants.RATE_LIMIT_POLICY_EMAIL: {Limit: 10, Period: time.Hour, Key: middlewareConstants.RATE_LIMIT_KEY_USER},
+	}
+
+	for name, policy := range policies {
+		if err := loadRateLimitPolicy(name, &policy); err != nil {
+			return err
+		}
+
+		policies[name] = policy
+	}
+
+	// Группы маршрутов авторизованных пользователей наследуют политику api
+	for _, name := range []string{
+		middlewareConstants.RATE_LIMIT_POLICY_USER,
+		middlewareConstants.RATE_LIMIT_POLICY_ADMIN,
+		middlewareConstants.RATE_LIMIT_POLICY_SERVICE,
+	} {
+		policy := policies[middlewareConstants.RATE_LIMIT_POLICY_API]
+		if err := loadRateLimitPolicy(name, &policy); err != nil {
+			return err
+		}
+
+		policies[name] = policy
+	}
+
+	for name, policy := range policies {
+		switch policy.Key {
+		case middlewareConstants.RATE_LIMIT_KEY_IP, middlewareConstants.RATE_LIMIT_KEY_USER, middlewareConstants.RATE_LIMIT_KEY_ROUTE:
+		default:
+			return fmt.Errorf("unknown rate limit key for policy %s: %s", name, policy.Key)
+		}
+
+		if policy.Limit > 0 && policy.Period <= 0 {
+			return fmt.Errorf("rate limit period for policy %s must be positive", name)
+		}
+	}
+
+	AppRateLimitPolicies = policies
+
+	return nil
+}
+
+/* Чтение параметров политики из файла конфигурации поверх значений по умолчанию */
+func loadRateLimitPolicy(name string, policy *RateLimitPolicy) error {
+	key := "rate_limit.policies." + name
+	if !viper.IsSet(key) {
+		return nil
+	}
+
+	if err := viper.UnmarshalKey(key, policy); err != nil {
+		return fmt.Errorf("invalid rate limit policy %s: %s", name, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"github.com/duo-labs/webauthn/protocol"
+	"github.com/duo-labs/webauthn/webauthn"
+	"github.com/spf13/viper"
+)
+
+/*
+* Параметры проверяющей стороны (Relying Party) для входа по ключам доступа:
+*
+*	webauthn:
+*	  rp_id: "example.com"
+*	  rp_origin: "https://example.com"
+*	  rp_display_name: "Example"
+*
+* Если rp_id не задан, вход по ключам доступа отключён
+ */
+var AppWebAuthn *webauthn.WebAuthn
+
+/* Инициализация параметров WebAuthn */
+func InitWebAuthn() error {
+	rpId := viper.GetString("webauthn.rp_id")
+	if rpId == "" {
+		return nil
+	}
+
+	displayName := viper.GetString("webauthn.rp_display_name")
+	if displayName == "" {
+		displayName = rpId
+	}
+
+	value, err := webauthn.New(&webauthn.Config{
+		RPID:          rpId,
+		RPOrigin:      viper.GetString("webauthn.rp_origin"),
+		RPDisplayName: displayName,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			UserVerification: protocol.VerificationPreferred,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	AppWebAuthn = value
+
+	return nil
+}
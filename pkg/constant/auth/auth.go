@@ -3,12 +3,57 @@ package auth
 import "time"
 
 const (
-	SALT              = "a;ugb*(AW^GFA&WTVFawtfva79wf6g7a6f2r8tc127tVIYTAWCFA&(T"
 	SIGNING_KEY       = "AOgnaiouGHA()wH8WFG8uga8eya7G9g9UBA@e@h(rh@u(!"
 	TOKEN_TLL_ACCESS  = 1 * time.Hour
 	TOKEN_TLL_REFRESH = 12 * time.Hour
 	TOKEN_TLL_RESET   = 5 * time.Minute
 
-	AUTH_TYPE_LOCAL  = "local"
-	AUTH_TYPE_GOOGLE = "google"
+	AUTH_TYPE_LOCAL    = "local"
+	AUTH_TYPE_GOOGLE   = "google"
+	AUTH_TYPE_VK       = "vk"
+	AUTH_TYPE_GITHUB   = "github"
+	AUTH_TYPE_OIDC     = "oidc"
+	AUTH_TYPE_WEBAUTHN = "webauthn"
+
+	AUTH_TYPE_CLIENT_CREDENTIALS = "client_credentials" // Токены клиентов OAuth2, действующих от имени сервисного аккаунта
+
+	TOKEN_TLL_WEBAUTHN = 5 * time.Minute // Время жизни данных незавершённой церемонии WebAuthn
+
+	// Вход без пароля (ссылка или код из письма, время жизни - TOKEN_TLL_RESET)
+	LOGIN_CODE_DIGITS          = 6
+	LOGIN_CODE_MAX_ATTEMPTS    = 5               // После исчерпания попыток код и ссылка аннулируются
+	LOGIN_CODE_RESEND_INTERVAL = 1 * time.Minute // Минимальный интервал между письмами одному пользователю
+
+	// Подтверждение аккаунта
+	TOKEN_TLL_ACTIVATION       = 24 * time.Hour
+	ACTIVATION_RESEND_INTERVAL = 1 * time.Minute // Минимальный интервал между письмами одному пользователю
+
+	ACTIVATION_POLICY_NONE    = "none"    // Подтверждение не требуется
+	ACTIVATION_POLICY_LIMITED = "limited" // До подтверждения доступна ограниченная часть API
+	ACTIVATION_POLICY_BLOCK   = "block"   // До подтверждения вход в систему запрещён
+
+	// Блокировка пользователя
+	BAN_KIND_BAN     = "ban"     // Блокировка (как правило, бессрочная)
+	BAN_KIND_SUSPEND = "suspend" // Временное отстранение (срок обязателен)
+
+	ERROR_CODE_USER_BANNED    = "user_banned"
+	ERROR_CODE_USER_SUSPENDED = "user_suspended"
+
+	// Защита от перебора паролей
+	LOCKOUT_STORE_MEMORY         = "memory"
+	LOCKOUT_STORE_POSTGRES       = "postgres"
+	ERROR_CODE_TOO_MANY_ATTEMPTS = "too_many_attempts"
+
+	// Список отозванных токенов доступа
+	DENYLIST_STORE_MEMORY   = "memory"
+	DENYLIST_STORE_POSTGRES = "postgres"
+
+	ERROR_CODE_PASSWORD_POLICY = "password_policy" // Пароль не соответствует политике паролей
+
+	// API-ключи и сервисные аккаунты
+	AUTH_TYPE_API_KEY            = "api_key"       // Тип авторизации в контексте запроса с API-ключом
+	API_KEY_PREFIX               = "ak_"           // Префикс, по которому API-ключ отличается от прочих секретов
+	API_KEY_PREFIX_LENGTH        = 10              // Длина отображаемой части ключа
+	API_KEY_LAST_USED_INTERVAL   = 1 * time.Minute // Минимальный интервал обновления даты последнего использования
+	SERVICE_ACCOUNT_EMAIL_DOMAIN = "service-account.invalid"
 )
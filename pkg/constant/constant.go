@@ -2,4 +2,8 @@ package constant
 
 const (
 	SEPARATOR = ";"
+
+	// Постраничный вывод списков
+	PAGE_LIMIT_DEFAULT = 20
+	PAGE_LIMIT_MAX     = 100
 )
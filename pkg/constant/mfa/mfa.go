@@ -0,0 +1,22 @@
+package mfa
+
+import "time"
+
+const (
+	TOKEN_TTL_MFA = 5 * time.Minute // Время жизни токена ожидания второго фактора
+
+	TOTP_SECRET_SIZE = 20 // Размер секрета в байтах (160 бит, RFC 4226)
+	TOTP_DIGITS      = 6
+	TOTP_PERIOD      = 30 // Длительность интервала в секундах
+	TOTP_SKEW        = 1  // Допустимое расхождение часов в интервалах
+
+	RECOVERY_CODES_COUNT = 10
+	RECOVERY_CODE_LENGTH = 10
+
+	// Способы подтверждения входа
+	METHOD_TOTP     = "totp"
+	METHOD_RECOVERY = "recovery"
+
+	// Назначение токена ожидания второго фактора
+	AUDIENCE_MFA = "mfa"
+)
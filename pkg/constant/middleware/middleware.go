@@ -7,8 +7,14 @@ const (
 	AUTH_TYPE_VALUE_CTX  = "auth_type_value"
 	ACCESS_TOKEN_CTX     = "access_token"
 	TOKEN_API_CTX        = "token_api"
+	SESSION_CTX          = "session_uuid"
 	DOMAINS_ID           = "domains_id"
 	DOMAINS_UUID         = "domains_uuid"
+	OBJECT_PARAM         = "object_uuid"
+	API_KEY_SCHEME       = "ApiKey" // Схема заголовка авторизации для API-ключей
+	API_KEY_CTX          = "api_key_uuid"
+	SCOPES_CTX           = "scopes"
+	CLIENT_CTX           = "client_id"
 
 	MN_UI                                            = "ui"
 	MN_UI_LOGOUT                                     = "ui_logout"
@@ -19,4 +25,43 @@ const (
 	MN_UI_HAS_ROLE_MANAGER                           = "ui_has_role_manager"
 	MN_UI_HAS_ROLE_BUILDER_MANAGER                   = "ui_has_role_builder_manager"
 	MN_UI_HAS_ROLE_BUILDER_ADMIN                     = "ui_has_role_builder_admin"
+	MN_UI_HAS_ROLES_ADMIN_OR_SUPER_ADMIN             = "ui_has_roles_admin_or_super_admin"
+	MN_UI_HAS_ROLE_SUPER_ADMIN                       = "ui_has_role_super_admin"
+	MN_UI_OBJECT_CREATE                              = "ui_object_create"
+	MN_UI_OBJECT_MODIFY                              = "ui_object_modify"
+	MN_UI_OBJECT_DELETE                              = "ui_object_delete"
+	MN_UI_OBJECT_READ                                = "ui_object_read"
+	MN_UI_OBJECT_ADMINISTRATION                      = "ui_object_administration"
+	MN_UI_OBJECT_MANAGEMENT                          = "ui_object_management"
+
+	// Проверка областей доступа API-ключей и токенов клиентов OAuth2
+	MN_UI_HAS_SCOPE_SERVICE_VERIFY = "ui_has_scope_service_verify"
+	MN_UI_HAS_SCOPE_SERVICE_EMAIL  = "ui_has_scope_service_email"
+	MN_UI_HAS_SCOPE_SERVICE_OBJECT = "ui_has_scope_service_object"
+	MN_UI_HAS_SCOPE_PROFILE        = "ui_has_scope_profile"
+	MN_UI_HAS_SCOPE_OPENID         = "ui_has_scope_openid"
+
+	// Ограничение частоты запросов (политики задаются в rate_limit.policies)
+	MN_RL_AUTH    = "rl_auth"
+	MN_RL_USER    = "rl_user"
+	MN_RL_ADMIN   = "rl_admin"
+	MN_RL_SERVICE = "rl_service"
+	MN_RL_EMAIL   = "rl_email"
+)
+
+const (
+	RATE_LIMIT_POLICY_AUTH    = "auth"    // Публичные маршруты авторизации
+	RATE_LIMIT_POLICY_API     = "api"     // Значения по умолчанию для групп маршрутов авторизованных пользователей
+	RATE_LIMIT_POLICY_USER    = "user"    // Маршруты /user
+	RATE_LIMIT_POLICY_ADMIN   = "admin"   // Маршруты /admin
+	RATE_LIMIT_POLICY_SERVICE = "service" // Маршруты /service
+	RATE_LIMIT_POLICY_EMAIL   = "email"   // Отправка писем
+
+	RATE_LIMIT_KEY_IP    = "ip"
+	RATE_LIMIT_KEY_USER  = "user"
+	RATE_LIMIT_KEY_ROUTE = "route"
+
+	ERROR_CODE_RATE_LIMITED = "rate_limited"
+
+	ERROR_CODE_INSUFFICIENT_SCOPE = "insufficient_scope"
 )
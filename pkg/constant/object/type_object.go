@@ -10,3 +10,16 @@ const (
 	SUB_ENTITY   = "sub_entity"
 	SUB_ENTITIES = "sub_entities"
 )
+
+/* Иерархия типов объектов: тип -> допустимый тип родительского объекта */
+var parents = map[string]string{
+	PROJECT:    COMPANY,
+	ENTITY:     PROJECT,
+	SUB_ENTITY: ENTITY,
+}
+
+/* Получение типа родительского объекта (false - объект данного типа не имеет родителя) */
+func GetParentType(typeObject string) (string, bool) {
+	value, ok := parents[typeObject]
+	return value, ok
+}
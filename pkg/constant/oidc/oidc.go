@@ -0,0 +1,33 @@
+package oidc
+
+import "time"
+
+const (
+	CODE_TTL = 5 * time.Minute
+
+	RESPONSE_TYPE_CODE            = "code"
+	GRANT_TYPE_AUTHORIZATION_CODE = "authorization_code"
+	GRANT_TYPE_CLIENT_CREDENTIALS = "client_credentials"
+	CODE_CHALLENGE_METHOD_S256    = "S256"
+	TOKEN_TYPE_BEARER             = "Bearer"
+
+	// Подсказки о типе токена при интроспекции и отзыве (RFC 7662, RFC 7009)
+	TOKEN_TYPE_HINT_ACCESS  = "access_token"
+	TOKEN_TYPE_HINT_REFRESH = "refresh_token"
+
+	SCOPE_OPENID  = "openid"
+	SCOPE_PROFILE = "profile"
+	SCOPE_EMAIL   = "email"
+	SCOPE_ROLES   = "roles"
+)
+
+/* Коды ошибок OAuth 2.0 (RFC 6749, раздел 5.2) */
+const (
+	ERROR_INVALID_REQUEST           = "invalid_request"
+	ERROR_INVALID_CLIENT            = "invalid_client"
+	ERROR_INVALID_GRANT             = "invalid_grant"
+	ERROR_INVALID_SCOPE             = "invalid_scope"
+	ERROR_UNAUTHORIZED_CLIENT       = "unauthorized_client"
+	ERROR_UNSUPPORTED_GRANT_TYPE    = "unsupported_grant_type"
+	ERROR_UNSUPPORTED_RESPONSE_TYPE = "unsupported_response_type"
+)
@@ -1,5 +1,7 @@
 package role
 
+import actionConstant "main-server/pkg/constant/action"
+
 const (
 	ROLE_CLIENT          = "client"
 	ROLE_BUILDER_ADMIN   = "builder_admin"
@@ -8,3 +10,40 @@ const (
 	ROLE_ADMIN           = "admin"
 	ROLE_SUPER_ADMIN     = "super_admin"
 )
+
+/* Встроенные роли, на которые опираются регистрация и проверка доступа (не могут быть изменены или удалены) */
+var builtinRoles = []string{ROLE_CLIENT, ROLE_ADMIN, ROLE_SUPER_ADMIN}
+
+/* Действия, которые даёт роль, выданная в контексте объекта (распространяются на все дочерние объекты) */
+var objectActions = map[string][]string{
+	ROLE_BUILDER_ADMIN: actionConstant.GetSlice(),
+	ROLE_BUILDER_MANAGER: {
+		actionConstant.CREATE,
+		actionConstant.MODIFY,
+		actionConstant.READ,
+		actionConstant.MANAGEMENT,
+	},
+	ROLE_MANAGER: {
+		actionConstant.MODIFY,
+		actionConstant.READ,
+	},
+	ROLE_CLIENT: {
+		actionConstant.READ,
+	},
+}
+
+/* Получение действий, которые даёт роль в контексте объекта */
+func GetObjectActions(role string) []string {
+	return objectActions[role]
+}
+
+/* Проверка, является ли роль встроенной */
+func IsBuiltin(role string) bool {
+	for _, item := range builtinRoles {
+		if item == role {
+			return true
+		}
+	}
+
+	return false
+}
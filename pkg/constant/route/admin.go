@@ -3,4 +3,15 @@ package route
 const (
 	ADMIN_COMPANY = "/company"
 	SYSTEM        = "/system"
+
+	// Блокировка пользователей
+	BAN      = "/ban"
+	BAN_LIFT = "/lift"
+
+	// Поиск пользователей
+	USER_SEARCH = "/search"
+
+	// Сервисные аккаунты
+	SERVICE_ACCOUNT     = "/service-account"
+	SERVICE_ACCOUNT_KEY = "/key"
 )
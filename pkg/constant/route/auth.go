@@ -10,19 +10,40 @@ const (
 	SIGN_UP              = "/sign-up"
 	SIGN_UP_UPLOAD_IMAGE = "/sign-up/upload/image"
 
-	// Авторизация через внешний сервис VK
-	SIGN_IN_VK          = "/sign-in/vk"
-	SIGN_IN_VK_CALLBACK = "/sign-in/vk/callback"
+	// Авторизация через внешнего провайдера OAuth2 (google, vk, github, ...)
+	SIGN_IN_PROVIDER     = "/sign-in/:provider"
+	SIGN_IN_PROVIDER_URL = "/sign-in/:provider/url"
 
-	// Авторизация через внешний сервис Google
-	SIGN_IN_OAUTH2 = "/sign-in/oauth2"
+	// Подтверждение входа вторым фактором
+	MFA              = "/mfa"
+	MFA_VERIFY       = "/verify"
+	MFA_TOTP_SETUP   = "/totp/setup"
+	MFA_TOTP_ENABLE  = "/totp/enable"
+	MFA_TOTP_DISABLE = "/totp/disable"
+	MFA_RECOVERY     = "/recovery/codes"
+
+	// Вход по ключам доступа (WebAuthn)
+	WEBAUTHN              = "/webauthn"
+	WEBAUTHN_LOGIN_BEGIN  = "/login/begin"
+	WEBAUTHN_LOGIN_FINISH = "/login/finish"
+
+	// Вход без пароля (ссылка или код из письма)
+	PASSWORDLESS         = "/passwordless"
+	PASSWORDLESS_REQUEST = "/request"
+	PASSWORDLESS_LINK    = "/link"
+	PASSWORDLESS_CODE    = "/code"
 
 	// Сброс пароля
 	RECOVERY_PASSWORD = "/recovery/password"
 	RESET_PASSWORD    = "/reset/password"
 
 	// Дополнительные маршруты
-	REFRESH       = "/refresh"
-	LOGOUT        = "/logout"
-	ACTIVATE_LINK = "/activate/:link"
+	REFRESH         = "/refresh"
+	LOGOUT          = "/logout"
+	ACTIVATE_LINK   = "/activate/:link"
+	ACTIVATE_RESEND = "/activate/resend"
+
+	// Подтверждение и отмена смены email-адреса
+	EMAIL_CHANGE_CONFIRM = "/email/confirm/:link"
+	EMAIL_CHANGE_CANCEL  = "/email/cancel/:link"
 )
@@ -1,15 +1,20 @@
 package route
 
 const (
-	// Token info
-	OAUTH2_TOKEN_INFO_ROUTE = "https://www.googleapis.com/oauth2/v1/tokeninfo?access_token="
+	// Google
+	GOOGLE_ISSUER       = "https://accounts.google.com"
+	GOOGLE_USER_INFO    = "https://openidconnect.googleapis.com/v1/userinfo"
+	GOOGLE_REVOKE_TOKEN = "https://oauth2.googleapis.com/revoke"
 
-	// User info
-	OAUTH2_USER_INFO_ROUTE = "https://www.googleapis.com/oauth2/v2/userinfo?access_token="
+	// VK
+	VK_USER_INFO   = "https://api.vk.com/method/users.get"
+	VK_API_VERSION = "5.131"
 
-	// Revoke token
-	OAUTH2_REVOKE_TOKEN_ROUTE = "https://oauth2.googleapis.com/revoke?token="
+	// GitHub
+	GITHUB_USER_INFO    = "https://api.github.com/user"
+	GITHUB_USER_EMAILS  = "https://api.github.com/user/emails"
+	GITHUB_REVOKE_TOKEN = "https://api.github.com/applications/%s/token"
 
-	// Refresh token
-	OAUTH2_REFRESH_TOKEN_ROUTE = "https://oauth2.googleapis.com/token?client_id="
+	// Документ обнаружения OpenID Connect
+	OIDC_DISCOVERY = "/.well-known/openid-configuration"
 )
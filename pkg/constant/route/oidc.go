@@ -0,0 +1,17 @@
+package route
+
+const (
+	OIDC_MAIN_ROUTE      = "/oidc"
+	OIDC_CLIENT          = "/oidc/client"
+	OAUTH_MAIN_ROUTE     = "/oauth" // Эндпоинты OAuth 2.0 для сервисов (client_credentials)
+	OPENID_CONFIGURATION = "/openid-configuration"
+)
+
+const (
+	AUTHORIZE = "/authorize"
+	TOKEN     = "/token"
+	USERINFO  = "/userinfo"
+
+	INTROSPECT = "/introspect"
+	REVOKE     = "/revoke"
+)
@@ -7,4 +7,5 @@ const (
 	GUEST   = "/guest"
 	ACCESS  = "/access"
 	ROLE    = "/role"
+	DOMAIN  = "/domain"
 )
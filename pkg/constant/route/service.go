@@ -5,4 +5,11 @@ const (
 	SERVICE_EXTERNAL   = "/external"
 	SERVICE_VERIFY     = "/verify"
 	SERVICE_EMAIL_SEND = "/email/send"
+	SERVICE_OBJECT     = "/object"
+	SERVICE_OBJECT_ID  = "/:object_uuid"
+)
+
+const (
+	WELL_KNOWN = "/.well-known"
+	JWKS       = "/jwks.json"
 )
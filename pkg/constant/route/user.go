@@ -4,4 +4,25 @@ const (
 	ARTICLE      = "/article"
 	PROFILE      = "/profile"
 	ACCESS_CHECK = "/access/check"
+	ROLES        = "/roles"
+
+	// Сессии пользователя
+	SESSION              = "/session"
+	SESSION_DELETE_OTHER = "/delete/other"
+
+	// Внешние учётные записи пользователя
+	IDENTITY        = "/identity"
+	IDENTITY_LINK   = "/link/:provider"
+	IDENTITY_UNLINK = "/unlink"
+
+	// API-ключи пользователя и сервисных аккаунтов
+	API_KEY        = "/api-key"
+	API_KEY_REVOKE = "/revoke"
+
+	// Смена email-адреса пользователя
+	EMAIL_CHANGE = "/email/change"
+
+	// Ключи доступа пользователя (WebAuthn)
+	WEBAUTHN_REGISTER_BEGIN  = "/register/begin"
+	WEBAUTHN_REGISTER_FINISH = "/register/finish"
 )
@@ -0,0 +1,17 @@
+package scope
+
+/* Области доступа API-ключей и токенов клиентов OAuth2 (client_credentials) */
+const (
+	SERVICE_VERIFY = "service:verify" // Проверка токена доступа (/service/external/verify)
+	SERVICE_EMAIL  = "service:email"  // Отправка писем (/service/external/email/send)
+	SERVICE_OBJECT = "service:object" // Работа с объектами (/service/external/object/*)
+	PROFILE        = "profile"        // Чтение профиля и ролей владельца ключа
+)
+
+/* Области доступа, которые можно выдать API-ключу или клиенту */
+var SCOPES = []string{
+	SERVICE_VERIFY,
+	SERVICE_EMAIL,
+	SERVICE_OBJECT,
+	PROFILE,
+}
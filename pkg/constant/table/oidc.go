@@ -0,0 +1,6 @@
+package table
+
+const (
+	OIDC_CLIENTS = "oidc_clients"
+	OIDC_CODES   = "oidc_codes"
+)
@@ -1,13 +1,27 @@
 package table
 
 const (
-	U_USERS            = "u_users"
-	U_USERS_DATA       = "u_users_data"
-	U_USERS_ROLES      = "u_users_roles"
-	U_ACTIVATIONS      = "u_activations"
-	U_TOKENS           = "u_tokens"
-	U_RESET_TOKENS     = "u_reset_tokens"
-	U_AUTH_TYPES       = "u_auth_types"
-	U_USERS_AUTH_TYPES = "u_users_auth_types"
-	U_BANS             = "u_bans"
+	U_USERS                = "u_users"
+	U_USERS_DATA           = "u_users_data"
+	U_USERS_ROLES          = "u_users_roles"
+	U_ACTIVATIONS          = "u_activations"
+	U_TOKENS               = "u_tokens"
+	U_TOKENS_CONSUMED      = "u_tokens_consumed"
+	U_TOKENS_DENYLIST      = "u_tokens_denylist"
+	U_RESET_TOKENS         = "u_reset_tokens"
+	U_AUTH_TYPES           = "u_auth_types"
+	U_USERS_AUTH_TYPES     = "u_users_auth_types"
+	U_IDENTITIES           = "u_identities"
+	U_MFA_TOTP             = "u_mfa_totp"
+	U_MFA_RECOVERY_CODES   = "u_mfa_recovery_codes"
+	U_MFA_PENDING          = "u_mfa_pending"
+	U_WEBAUTHN_CREDENTIALS = "u_webauthn_credentials"
+	U_WEBAUTHN_SESSIONS    = "u_webauthn_sessions"
+	U_LOGIN_CODES          = "u_login_codes"
+	U_BANS                 = "u_bans"
+	U_LOGIN_ATTEMPTS       = "u_login_attempts"
+	U_PASSWORD_HISTORY     = "u_password_history"
+	U_EMAIL_CHANGES        = "u_email_changes"
+	U_SERVICE_ACCOUNTS     = "u_service_accounts"
+	U_API_KEYS             = "u_api_keys"
 )
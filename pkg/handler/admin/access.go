@@ -0,0 +1,122 @@
+package admin
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	rbacModel "main-server/pkg/model/rbac"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Выдача роли пользователю
+// @Tags API для управления ролями и доступом
+// @Description Выдача роли пользователю в домене (при указании object_uuid - в контексте объекта)
+// @ID admin-access-add
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.AccessRoleModel true "Данные о выдаваемой роли"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,403,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/access/add [post]
+func (h *AdminHandler) accessAdd(c *gin.Context) {
+	var input rbacModel.AccessRoleModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Role.AddRoleForUser(userIdentity, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, roleErrorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
+
+// @Summary Отзыв роли у пользователя
+// @Tags API для управления ролями и доступом
+// @Description Отзыв роли у пользователя в домене (при указании object_uuid - в контексте объекта)
+// @ID admin-access-delete
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.AccessRoleModel true "Данные об отзываемой роли"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,403,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/access/delete [post]
+func (h *AdminHandler) accessDelete(c *gin.Context) {
+	var input rbacModel.AccessRoleModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Role.DeleteRoleForUser(userIdentity, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, roleErrorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
+
+// @Summary Получение ролей пользователя
+// @Tags API для управления ролями и доступом
+// @Description Получение всех ролей пользователя в домене (по умолчанию - текущем)
+// @ID admin-access-get
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.AccessUserModel true "Пользователь и домен"
+// @Success 200 {object} userModel.UserRoleModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/access/get [post]
+func (h *AdminHandler) accessGet(c *gin.Context) {
+	var input rbacModel.AccessUserModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Role.GetUserRoles(userIdentity, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
@@ -0,0 +1,113 @@
+package admin
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Блокировка пользователя
+// @Tags API для блокировки пользователей
+// @Description Блокировка (ban) или временное отстранение (suspend) пользователя. Все сессии пользователя завершаются
+// @ID admin-user-ban-create
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.BanCreateModel true "Данные блокировки"
+// @Success 200 {object} userModel.BanModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/user/ban/create [post]
+func (h *AdminHandler) banCreate(c *gin.Context) {
+	var input userModel.BanCreateModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Ban.Create(userIdentity, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Снятие блокировки пользователя
+// @Tags API для блокировки пользователей
+// @Description Снятие действующей блокировки пользователя (запись остаётся в истории)
+// @ID admin-user-ban-lift
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.BanUserModel true "Идентификатор пользователя"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/user/ban/lift [post]
+func (h *AdminHandler) banLift(c *gin.Context) {
+	var input userModel.BanUserModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Ban.Lift(userIdentity, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
+
+// @Summary Получение истории блокировок пользователя
+// @Tags API для блокировки пользователей
+// @Description Получение истории блокировок пользователя (включая снятые и истёкшие)
+// @ID admin-user-ban-get-all
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param users_uuid query string true "UUID пользователя"
+// @Success 200 {object} userModel.BansModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/user/ban/get/all [get]
+func (h *AdminHandler) banGetAll(c *gin.Context) {
+	usersUuid := c.Query("users_uuid")
+	if usersUuid == "" {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, "Не указан UUID пользователя")
+		return
+	}
+
+	data, err := h.services.Ban.GetAll(usersUuid)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
@@ -0,0 +1,130 @@
+package admin
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	rbacModel "main-server/pkg/model/rbac"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Создание нового домена
+// @Tags API для управления ролями и доступом
+// @Description Создание нового домена
+// @ID admin-domain-create
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.DomainCreateModel true "Данные нового домена"
+// @Success 200 {object} rbacModel.DomainModel "data"
+// @Failure 400,403,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/domain/create [post]
+func (h *AdminHandler) domainCreate(c *gin.Context) {
+	var input rbacModel.DomainCreateModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Domain.Create(userIdentity, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Получение всех доменов
+// @Tags API для управления ролями и доступом
+// @Description Получение всех доменов
+// @ID admin-domain-get-all
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} rbacModel.DomainsModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/domain/get/all [get]
+func (h *AdminHandler) domainGetAll(c *gin.Context) {
+	data, err := h.services.Domain.GetAll()
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Обновление домена
+// @Tags API для управления ролями и доступом
+// @Description Обновление значения и описания домена
+// @ID admin-domain-update
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.DomainUpdateModel true "Новые данные домена"
+// @Success 200 {object} rbacModel.DomainModel "data"
+// @Failure 400,403,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/domain/update [post]
+func (h *AdminHandler) domainUpdate(c *gin.Context) {
+	var input rbacModel.DomainUpdateModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Domain.Update(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Удаление домена
+// @Tags API для управления ролями и доступом
+// @Description Удаление домена вместе с его ролями и правилами доступа
+// @ID admin-domain-delete
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.UuidModel true "UUID домена"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,403,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/domain/delete [post]
+func (h *AdminHandler) domainDelete(c *gin.Context) {
+	var input rbacModel.UuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Domain.Delete(input.Uuid)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
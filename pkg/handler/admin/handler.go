@@ -0,0 +1,146 @@
+package admin
+
+import (
+	_ "main-server/docs"
+
+	middlewareConstant "main-server/pkg/constant/middleware"
+	"main-server/pkg/constant/route"
+	service "main-server/pkg/service"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/swaggo/files"
+	_ "github.com/swaggo/gin-swagger"
+)
+
+type AdminHandler struct {
+	rootHandler *gin.Engine
+	services    *service.Service
+}
+
+func NewAdminHandler(root *gin.Engine, services *service.Service) *AdminHandler {
+	return &AdminHandler{
+		rootHandler: root,
+		services:    services,
+	}
+}
+
+/* Инициализация маршрутов для администрирования системы */
+func (h *AdminHandler) InitRoutes(
+	middleware *map[string]func(c *gin.Context),
+) {
+	// URL: /admin
+	admin := h.rootHandler.Group(
+		route.ADMIN,
+		(*middleware)[middlewareConstant.MN_UI],
+		(*middleware)[middlewareConstant.MN_UI_HAS_ROLES_ADMIN_OR_SUPER_ADMIN],
+		(*middleware)[middlewareConstant.MN_RL_ADMIN],
+	)
+	{
+		// URL: /admin/role
+		role := admin.Group(route.ROLE)
+		{
+			// URL: /admin/role/create
+			role.POST(route.CREATE, h.roleCreate)
+
+			// URL: /admin/role/get/all
+			role.GET(route.GET_ALL, h.roleGetAll)
+
+			// URL: /admin/role/update
+			role.POST(route.UPDATE, h.roleUpdate)
+
+			// URL: /admin/role/delete
+			role.POST(route.DELETE, h.roleDelete)
+		}
+
+		// URL: /admin/domain
+		domain := admin.Group(route.DOMAIN)
+		{
+			// URL: /admin/domain/create
+			domain.POST(route.CREATE, (*middleware)[middlewareConstant.MN_UI_HAS_ROLE_SUPER_ADMIN], h.domainCreate)
+
+			// URL: /admin/domain/get/all
+			domain.GET(route.GET_ALL, h.domainGetAll)
+
+			// URL: /admin/domain/update
+			domain.POST(route.UPDATE, (*middleware)[middlewareConstant.MN_UI_HAS_ROLE_SUPER_ADMIN], h.domainUpdate)
+
+			// URL: /admin/domain/delete
+			domain.POST(route.DELETE, (*middleware)[middlewareConstant.MN_UI_HAS_ROLE_SUPER_ADMIN], h.domainDelete)
+		}
+
+		// URL: /admin/access
+		access := admin.Group(route.ACCESS)
+		{
+			// URL: /admin/access/add
+			access.POST(route.ADD, h.accessAdd)
+
+			// URL: /admin/access/delete
+			access.POST(route.DELETE, h.accessDelete)
+
+			// URL: /admin/access/get
+			access.POST(route.GET, h.accessGet)
+		}
+
+		// URL: /admin/user
+		user := admin.Group(route.USER)
+		{
+			// URL: /admin/user/search
+			user.GET(route.USER_SEARCH, h.userSearch)
+
+			// URL: /admin/user/get
+			user.GET(route.GET, h.userGet)
+		}
+
+		// URL: /admin/user/ban
+		ban := admin.Group(route.USER + route.BAN)
+		{
+			// URL: /admin/user/ban/create
+			ban.POST(route.CREATE, h.banCreate)
+
+			// URL: /admin/user/ban/lift
+			ban.POST(route.BAN_LIFT, h.banLift)
+
+			// URL: /admin/user/ban/get/all
+			ban.GET(route.GET_ALL, h.banGetAll)
+		}
+
+		// URL: /admin/service-account
+		serviceAccount := admin.Group(route.SERVICE_ACCOUNT)
+		{
+			// URL: /admin/service-account/create
+			serviceAccount.POST(route.CREATE, h.serviceAccountCreate)
+
+			// URL: /admin/service-account/get/all
+			serviceAccount.GET(route.GET_ALL, h.serviceAccountGetAll)
+
+			// URL: /admin/service-account/delete
+			serviceAccount.POST(route.DELETE, h.serviceAccountDelete)
+
+			// URL: /admin/service-account/key
+			key := serviceAccount.Group(route.SERVICE_ACCOUNT_KEY)
+			{
+				// URL: /admin/service-account/key/create
+				key.POST(route.CREATE, h.serviceAccountKeyCreate)
+
+				// URL: /admin/service-account/key/get/all
+				key.GET(route.GET_ALL, h.serviceAccountKeyGetAll)
+
+				// URL: /admin/service-account/key/revoke
+				key.POST(route.API_KEY_REVOKE, h.serviceAccountKeyRevoke)
+			}
+		}
+
+		// URL: /admin/oidc/client
+		oidcClient := admin.Group(route.OIDC_CLIENT)
+		{
+			// URL: /admin/oidc/client/create
+			oidcClient.POST(route.CREATE, h.oidcClientCreate)
+
+			// URL: /admin/oidc/client/get/all
+			oidcClient.GET(route.GET_ALL, h.oidcClientGetAll)
+
+			// URL: /admin/oidc/client/delete
+			oidcClient.POST(route.DELETE, h.oidcClientDelete)
+		}
+	}
+}
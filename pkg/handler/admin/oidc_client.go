@@ -0,0 +1,94 @@
+package admin
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	oidcModel "main-server/pkg/model/oidc"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Регистрация нового клиента OpenID Connect
+// @Tags API для управления клиентами OpenID Connect
+// @Description Регистрация нового клиента OpenID Connect (секрет конфиденциального клиента возвращается только один раз)
+// @ID admin-oidc-client-create
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body oidcModel.ClientCreateModel true "Данные нового клиента"
+// @Success 200 {object} oidcModel.ClientCreatedModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/oidc/client/create [post]
+func (h *AdminHandler) oidcClientCreate(c *gin.Context) {
+	var input oidcModel.ClientCreateModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Oidc.CreateClient(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Получение всех клиентов OpenID Connect
+// @Tags API для управления клиентами OpenID Connect
+// @Description Получение всех клиентов OpenID Connect
+// @ID admin-oidc-client-get-all
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} oidcModel.ClientsModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/oidc/client/get/all [get]
+func (h *AdminHandler) oidcClientGetAll(c *gin.Context) {
+	data, err := h.services.Oidc.GetClients()
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Удаление клиента OpenID Connect
+// @Tags API для управления клиентами OpenID Connect
+// @Description Удаление клиента OpenID Connect
+// @ID admin-oidc-client-delete
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body oidcModel.ClientIdModel true "Идентификатор клиента"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/oidc/client/delete [post]
+func (h *AdminHandler) oidcClientDelete(c *gin.Context) {
+	var input oidcModel.ClientIdModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Oidc.DeleteClient(input.ClientId)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
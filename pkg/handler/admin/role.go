@@ -0,0 +1,154 @@
+package admin
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	rbacModel "main-server/pkg/model/rbac"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Создание новой роли
+// @Tags API для управления ролями и доступом
+// @Description Создание новой роли в рамках домена (по умолчанию - текущего)
+// @ID admin-role-create
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.RoleCreateModel true "Данные новой роли"
+// @Success 200 {object} rbacModel.RoleModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/role/create [post]
+func (h *AdminHandler) roleCreate(c *gin.Context) {
+	var input rbacModel.RoleCreateModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Role.Create(userIdentity, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Получение всех ролей домена
+// @Tags API для управления ролями и доступом
+// @Description Получение всех ролей домена (по умолчанию - текущего)
+// @ID admin-role-get-all
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param domain_uuid query string false "UUID домена"
+// @Success 200 {object} rbacModel.RolesModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/role/get/all [get]
+func (h *AdminHandler) roleGetAll(c *gin.Context) {
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	var domainUuid *string
+	if value, ok := c.GetQuery("domain_uuid"); ok {
+		domainUuid = &value
+	}
+
+	data, err := h.services.Role.GetAll(userIdentity, domainUuid)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Обновление роли
+// @Tags API для управления ролями и доступом
+// @Description Обновление значения и описания роли
+// @ID admin-role-update
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.RoleUpdateModel true "Новые данные роли"
+// @Success 200 {object} rbacModel.RoleModel "data"
+// @Failure 400,403,404,409 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/role/update [post]
+func (h *AdminHandler) roleUpdate(c *gin.Context) {
+	var input rbacModel.RoleUpdateModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Role.Update(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, roleErrorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Удаление роли
+// @Tags API для управления ролями и доступом
+// @Description Удаление роли вместе со всеми её выдачами пользователям
+// @ID admin-role-delete
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.UuidModel true "UUID роли"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,403,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/role/delete [post]
+func (h *AdminHandler) roleDelete(c *gin.Context) {
+	var input rbacModel.UuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Role.Delete(input.Uuid)
+	if err != nil {
+		utilContext.NewErrorResponse(c, roleErrorStatus(err), err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
+
+/* Определение кода ответа для ошибки изменения роли */
+func roleErrorStatus(err error) int {
+	switch err {
+	case rbacModel.ErrRoleBuiltin, rbacModel.ErrRoleDenied, rbacModel.ErrRoleSelf:
+		return http.StatusForbidden
+	case rbacModel.ErrRoleExists:
+		return http.StatusConflict
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,197 @@
+package admin
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Создание сервисного аккаунта
+// @Tags API для управления сервисными аккаунтами
+// @Description Создание сервисного аккаунта для межсервисного взаимодействия. Роли назначаются по users_uuid, вход возможен только по API-ключу
+// @ID admin-service-account-create
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.ServiceAccountCreateModel true "Данные сервисного аккаунта"
+// @Success 200 {object} userModel.ServiceAccountModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/service-account/create [post]
+func (h *AdminHandler) serviceAccountCreate(c *gin.Context) {
+	var input userModel.ServiceAccountCreateModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.ServiceAccount.Create(userIdentity, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Получение сервисных аккаунтов
+// @Tags API для управления сервисными аккаунтами
+// @Description Получение всех сервисных аккаунтов
+// @ID admin-service-account-get-all
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} userModel.ServiceAccountsModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/service-account/get/all [get]
+func (h *AdminHandler) serviceAccountGetAll(c *gin.Context) {
+	data, err := h.services.ServiceAccount.GetAll()
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Удаление сервисного аккаунта
+// @Tags API для управления сервисными аккаунтами
+// @Description Удаление сервисного аккаунта. Все API-ключи аккаунта отзываются
+// @ID admin-service-account-delete
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.ServiceAccountUuidModel true "UUID сервисного аккаунта"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/service-account/delete [post]
+func (h *AdminHandler) serviceAccountDelete(c *gin.Context) {
+	var input userModel.ServiceAccountUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.ServiceAccount.Delete(input.Uuid)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
+
+// @Summary Создание API-ключа сервисного аккаунта
+// @Tags API для управления сервисными аккаунтами
+// @Description Создание API-ключа сервисного аккаунта. Ключ возвращается только один раз
+// @ID admin-service-account-key-create
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.ServiceAccountApiKeyCreateModel true "Данные API-ключа"
+// @Success 200 {object} userModel.ApiKeyCreatedModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/service-account/key/create [post]
+func (h *AdminHandler) serviceAccountKeyCreate(c *gin.Context) {
+	var input userModel.ServiceAccountApiKeyCreateModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.ServiceAccount.CreateKey(userIdentity, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Получение API-ключей сервисного аккаунта
+// @Tags API для управления сервисными аккаунтами
+// @Description Получение всех API-ключей сервисного аккаунта (включая отозванные)
+// @ID admin-service-account-key-get-all
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param uuid query string true "UUID сервисного аккаунта"
+// @Success 200 {object} userModel.ApiKeysModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/service-account/key/get/all [get]
+func (h *AdminHandler) serviceAccountKeyGetAll(c *gin.Context) {
+	accountUuid := c.Query("uuid")
+	if accountUuid == "" {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, "Не указан UUID сервисного аккаунта")
+		return
+	}
+
+	data, err := h.services.ServiceAccount.GetKeys(accountUuid)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Отзыв API-ключа сервисного аккаунта
+// @Tags API для управления сервисными аккаунтами
+// @Description Отзыв API-ключа сервисного аккаунта
+// @ID admin-service-account-key-revoke
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.ServiceAccountApiKeyModel true "UUID сервисного аккаунта и API-ключа"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/service-account/key/revoke [post]
+func (h *AdminHandler) serviceAccountKeyRevoke(c *gin.Context) {
+	var input userModel.ServiceAccountApiKeyModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.ServiceAccount.RevokeKey(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
@@ -0,0 +1,93 @@
+package admin
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Поиск пользователей
+// @Tags API для просмотра пользователей
+// @Description Поиск пользователей по email-адресу, имени, фамилии или никнейму с фильтрами по роли, типу авторизации,
+// @Description подтверждению аккаунта и блокировке. Постраничный вывод - по курсору (next_cursor) либо по limit/offset
+// @ID admin-user-search
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param search query string false "Подстрока email-адреса, имени, фамилии или никнейма"
+// @Param role query string false "Роль пользователя в текущем домене"
+// @Param auth_type query string false "Привязанный тип авторизации"
+// @Param activated query bool false "Аккаунт подтверждён"
+// @Param banned query bool false "Пользователь заблокирован"
+// @Param limit query int false "Количество пользователей на странице (по умолчанию 20, не более 100)"
+// @Param offset query int false "Смещение (не используется вместе с cursor)"
+// @Param cursor query string false "Курсор следующей страницы"
+// @Success 200 {object} userModel.UsersListModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/user/search [get]
+func (h *AdminHandler) userSearch(c *gin.Context) {
+	var input userModel.UserSearchModel
+
+	if err := c.ShouldBindQuery(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if input.Cursor != "" && input.Offset > 0 {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, "Параметры cursor и offset не могут использоваться одновременно")
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.UserAdmin.Search(userIdentity, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Получение сведений о пользователе
+// @Tags API для просмотра пользователей
+// @Description Получение профиля, ролей в текущем домене, привязанных типов авторизации, действующей блокировки и сессий пользователя
+// @ID admin-user-get
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param users_uuid query string true "UUID пользователя"
+// @Success 200 {object} userModel.UserDetailModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /admin/user/get [get]
+func (h *AdminHandler) userGet(c *gin.Context) {
+	usersUuid := c.Query("users_uuid")
+	if usersUuid == "" {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, "Не указан UUID пользователя")
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.UserAdmin.Get(userIdentity, usersUuid)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
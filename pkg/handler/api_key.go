@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+
+	authConstants "main-server/pkg/constant/auth"
+	middlewareConstants "main-server/pkg/constant/middleware"
+	utilContext "main-server/pkg/handler/util"
+	userModel "main-server/pkg/model/user"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+/* Проверка пользователя или сервисного аккаунта по API-ключу (заголовок "Authorization: ApiKey <ключ>") */
+func (h *Handler) apiKeyIdentity(c *gin.Context, key string) {
+	data, err := h.services.ApiKey.Authenticate(key)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == userModel.ErrApiKeyInvalid {
+			status = http.StatusUnauthorized
+		}
+
+		utilContext.NewErrorResponse(c, status, err.Error())
+		return
+	}
+
+	// Области доступа проверяются на самих маршрутах (userIdentityHasScope)
+	if !scopedRoutes[c.FullPath()] {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, "Данный маршрут недоступен при авторизации по API-ключу")
+		return
+	}
+
+	domain, err := h.services.Domain.Get("value", viper.GetString("domain"), true)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if !h.checkUserAccess(c, data.UsersId) {
+		return
+	}
+
+	// Заполняются те же данные контекста, что и при авторизации по токену доступа (кроме сессии)
+	c.Set(middlewareConstants.USER_CTX, data.UsersId)
+	c.Set(middlewareConstants.USER_UUID_CTX, data.UsersUuid)
+	c.Set(middlewareConstants.AUTH_TYPE_VALUE_CTX, authConstants.AUTH_TYPE_API_KEY)
+	c.Set(middlewareConstants.API_KEY_CTX, data.ApiKeyUuid)
+	c.Set(middlewareConstants.SCOPES_CTX, data.Scopes)
+	c.Set(middlewareConstants.DOMAINS_ID, domain.Id)
+	c.Set(middlewareConstants.DOMAINS_UUID, domain.Uuid)
+}
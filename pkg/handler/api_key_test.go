@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	config "main-server/config"
+	authConstants "main-server/pkg/constant/auth"
+	middlewareConstants "main-server/pkg/constant/middleware"
+	"main-server/pkg/constant/route"
+	scopeConstants "main-server/pkg/constant/scope"
+	utilContext "main-server/pkg/handler/util"
+	rbacModel "main-server/pkg/model/rbac"
+	userModel "main-server/pkg/model/user"
+	"main-server/pkg/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testApiKey = authConstants.API_KEY_PREFIX + "test"
+
+/* API-ключи, известные тестовому сервису (по значению ключа) */
+type fakeApiKeyService struct {
+	service.ApiKey
+	keys map[string]*userModel.ApiKeyIdentityModel
+}
+
+func (s *fakeApiKeyService) Authenticate(key string) (*userModel.ApiKeyIdentityModel, error) {
+	if data, ok := s.keys[key]; ok {
+		return data, nil
+	}
+
+	return nil, userModel.ErrApiKeyInvalid
+}
+
+type fakeDomainService struct {
+	service.Domain
+}
+
+func (s *fakeDomainService) Get(column string, value interface{}, check bool) (*rbacModel.DomainModel, error) {
+	return &rbacModel.DomainModel{Id: 1, Uuid: "00000000-0000-0000-0000-000000000001"}, nil
+}
+
+type fakeBanService struct {
+	service.Ban
+}
+
+func (s *fakeBanService) GetActive(userId int) (*userModel.BanModel, error) {
+	return nil, nil
+}
+
+/* Маршрутизатор с проверкой пользователя и областей доступа, как в InitRoutes */
+func newScopeTestRouter(t *testing.T, services *service.Service) *gin.Engine {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	config.AppActivationPolicy = config.ActivationPolicy{Mode: authConstants.ACTIVATION_POLICY_NONE}
+	t.Cleanup(func() { config.AppActivationPolicy = config.ActivationPolicy{} })
+
+	services.Domain = &fakeDomainService{}
+	services.Ban = &fakeBanService{}
+	h := NewHandler(services)
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+
+	router := gin.New()
+	router.POST(route.SERVICE+route.SERVICE_EXTERNAL+route.SERVICE_VERIFY,
+		h.userIdentity, h.userIdentityHasScope(scopeConstants.SERVICE_VERIFY), ok)
+	router.GET(route.USER+route.PROFILE, h.userIdentity, h.userIdentityHasScope(scopeConstants.PROFILE), ok)
+	router.POST(route.USER+route.PROFILE+route.UPDATE, h.userIdentity, ok)
+
+	return router
+}
+
+func TestApiKeyScopes(t *testing.T) {
+	router := newScopeTestRouter(t, &service.Service{
+		ApiKey: &fakeApiKeyService{keys: map[string]*userModel.ApiKeyIdentityModel{
+			testApiKey: {
+				UsersId:    10,
+				UsersUuid:  "00000000-0000-0000-0000-000000000010",
+				ApiKeyUuid: "00000000-0000-0000-0000-000000000020",
+				Scopes:     []string{scopeConstants.SERVICE_VERIFY},
+			},
+		}},
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		header string
+		status int
+		code   string
+	}{
+		{
+			name:   "scope granted",
+			method: http.MethodPost,
+			path:   route.SERVICE + route.SERVICE_EXTERNAL + route.SERVICE_VERIFY,
+			header: middlewareConstants.API_KEY_SCHEME + " " + testApiKey,
+			status: http.StatusOK,
+		},
+		{
+			name:   "scope not granted",
+			method: http.MethodGet,
+			path:   route.USER + route.PROFILE,
+			header: middlewareConstants.API_KEY_SCHEME + " " + testApiKey,
+			status: http.StatusForbidden,
+			code:   middlewareConstants.ERROR_CODE_INSUFFICIENT_SCOPE,
+		},
+		{
+			name:   "route is not available for api keys",
+			method: http.MethodPost,
+			path:   route.USER + route.PROFILE + route.UPDATE,
+			header: middlewareConstants.API_KEY_SCHEME + " " + testApiKey,
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "unknown key",
+			method: http.MethodPost,
+			path:   route.SERVICE + route.SERVICE_EXTERNAL + route.SERVICE_VERIFY,
+			header: middlewareConstants.API_KEY_SCHEME + " " + authConstants.API_KEY_PREFIX + "unknown",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "empty header",
+			method: http.MethodPost,
+			path:   route.SERVICE + route.SERVICE_EXTERNAL + route.SERVICE_VERIFY,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(middlewareConstants.AUTHORIZATION_HEADER, tt.header)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d (%s)", w.Code, tt.status, w.Body.String())
+			}
+
+			if tt.code == "" {
+				return
+			}
+
+			var body utilContext.ResponseMessage
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+
+			if body.Code != tt.code {
+				t.Fatalf("code = %q, want %q", body.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestUserIdentityHasScope(t *testing.T) {
+	h := NewHandler(&service.Service{})
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		scopes interface{} // Значение в контексте (nil - токен пользовательской сессии)
+		status int
+	}{
+		{name: "session token", scopes: nil, status: http.StatusOK},
+		{name: "scope granted", scopes: []string{scopeConstants.PROFILE, scopeConstants.SERVICE_VERIFY}, status: http.StatusOK},
+		{name: "scope not granted", scopes: []string{scopeConstants.SERVICE_EMAIL}, status: http.StatusForbidden},
+		{name: "no scopes", scopes: []string{}, status: http.StatusForbidden},
+		{name: "invalid context value", scopes: "profile", status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/", func(c *gin.Context) {
+				if tt.scopes != nil {
+					c.Set(middlewareConstants.SCOPES_CTX, tt.scopes)
+				}
+			}, h.userIdentityHasScope(scopeConstants.PROFILE), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
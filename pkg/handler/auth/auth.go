@@ -1,7 +1,6 @@
 package auth
 
 import (
-	"fmt"
 	config "main-server/config"
 	middlewareConstant "main-server/pkg/constant/middleware"
 	pathConstant "main-server/pkg/constant/path"
@@ -34,9 +33,9 @@ func (h *AuthHandler) signUp(c *gin.Context) {
 		return
 	}
 
-	data, err := h.services.Authorization.CreateUser(input)
+	data, err := h.services.Authorization.CreateUser(input, utilContext.GetSessionInfo(c))
 	if err != nil {
-		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		utilContext.NewAuthErrorResponse(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -96,7 +95,8 @@ func (h *AuthHandler) uploadProfileImage(c *gin.Context) {
 // @Produce  json
 // @Param input body userModel.UserSignInModel true "credentials"
 // @Success 200 {object} userModel.TokenAccessModel "data"
-// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Success 200 {object} userModel.MfaPendingModel "mfa (если требуется второй фактор)"
+// @Failure 400,403,404,429 {object} httpModel.ResponseMessage
 // @Failure 500 {object} httpModel.ResponseMessage
 // @Failure default {object} httpModel.ResponseMessage
 // @Router /auth/sign-in [post]
@@ -107,9 +107,15 @@ func (h *AuthHandler) signIn(c *gin.Context) {
 		return
 	}
 
-	data, err := h.services.Authorization.LoginUser(input)
+	data, err := h.services.Authorization.LoginUser(input, utilContext.GetSessionInfo(c))
 	if err != nil {
-		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		utilContext.NewAuthErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// Вход ожидает подтверждения вторым фактором
+	if data.Mfa != nil {
+		c.JSON(http.StatusOK, data.Mfa)
 		return
 	}
 
@@ -123,29 +129,37 @@ func (h *AuthHandler) signIn(c *gin.Context) {
 	})
 }
 
-// @Summary Авторизация пользователя через VK (no work)
+// @Summary Авторизация пользователя через внешнего провайдера OAuth2
 // @Tags API для авторизации и регистрации пользователя
-// @Description Авторизация пользователя через VK (no work)
-// @ID auth-sign-in-vk
+// @Description Авторизация пользователя через внешнего провайдера OAuth2 (google, vk, github, oidc)
+// @ID auth-sign-in-provider
 // @Accept  json
 // @Produce  json
-// @Param input body userModel.UserSignInModel true "credentials"
+// @Param provider path string true "Провайдер (значение типа авторизации)"
+// @Param input body userModel.UserLoginOAuth2Model true "credentials"
 // @Success 200 {object} userModel.TokenAccessModel "data"
+// @Success 200 {object} userModel.MfaPendingModel "mfa (если требуется второй фактор)"
 // @Failure 400,404 {object} httpModel.ResponseMessage
 // @Failure 500 {object} httpModel.ResponseMessage
 // @Failure default {object} httpModel.ResponseMessage
-// @Router /auth/sign-in/vk [post]
-func (h *AuthHandler) signInVK(c *gin.Context) {
-	var input userModel.UserSignInModel
+// @Router /auth/sign-in/{provider} [post]
+func (h *AuthHandler) signInOAuth2(c *gin.Context) {
+	var input userModel.UserLoginOAuth2Model
 
 	if err := c.BindJSON(&input); err != nil {
 		utilContext.NewErrorResponse(c, http.StatusBadRequest, "invalid input body")
 		return
 	}
 
-	data, err := h.services.Authorization.LoginUser(input)
+	data, err := h.services.Authorization.LoginUserOAuth2(c.Param("provider"), input.Code, utilContext.GetSessionInfo(c))
 	if err != nil {
-		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		utilContext.NewAuthErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// Вход ожидает подтверждения вторым фактором
+	if data.Mfa != nil {
+		c.JSON(http.StatusOK, data.Mfa)
 		return
 	}
 
@@ -159,45 +173,26 @@ func (h *AuthHandler) signInVK(c *gin.Context) {
 	})
 }
 
-// @Summary Авторизация пользователя через Google OAuth2
+// @Summary Адрес страницы авторизации внешнего провайдера OAuth2
 // @Tags API для авторизации и регистрации пользователя
-// @Description Авторизация пользователя через Google OAuth2
-// @ID auth-sign-in-oauth2
-// @Accept  json
+// @Description Адрес страницы авторизации внешнего провайдера OAuth2
+// @ID auth-sign-in-provider-url
 // @Produce  json
-// @Param input body userModel.GoogleOAuth2Code true "credentials"
-// @Success 200 {object} userModel.TokenAccessModel "data"
+// @Param provider path string true "Провайдер (значение типа авторизации)"
+// @Param state query string false "Значение state, которое провайдер вернёт вместе с кодом"
+// @Success 200 {object} userModel.OAuth2UrlModel "data"
 // @Failure 400,404 {object} httpModel.ResponseMessage
 // @Failure 500 {object} httpModel.ResponseMessage
 // @Failure default {object} httpModel.ResponseMessage
-// @Router /auth/sign-in/oauth2 [post]
-func (h *AuthHandler) signInOAuth2(c *gin.Context) {
-	var input userModel.GoogleOAuth2Code
-
-	if err := c.BindJSON(&input); err != nil {
-		utilContext.NewErrorResponse(c, http.StatusBadRequest, "invalid input body")
-		return
-	}
-
-	// For fast tests
-	/*token, _ := configs.AppOAuth2Config.GoogleLogin.Exchange(c, input.Code)
-	_, _ = google_oauth2.RevokeToken(token.AccessToken)
-	return*/
-
-	data, err := h.services.Authorization.LoginUserOAuth2(input.Code)
+// @Router /auth/sign-in/{provider}/url [get]
+func (h *AuthHandler) signInOAuth2Url(c *gin.Context) {
+	data, err := h.services.Authorization.GetOAuth2Url(c.Param("provider"), c.Query("state"))
 	if err != nil {
-		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		utilContext.NewErrorResponse(c, http.StatusNotFound, err.Error())
 		return
 	}
 
-	// Добавление токена обновления в http only cookie
-	c.SetCookie(viper.GetString("environment.refresh_token_key"), data.RefreshToken,
-		30*24*60*60*1000, "/", viper.GetString("environment.domain"), false, true)
-	c.SetSameSite(config.HTTPSameSite)
-
-	c.JSON(http.StatusOK, userModel.TokenAccessModel{
-		AccessToken: data.AccessToken,
-	})
+	c.JSON(http.StatusOK, data)
 }
 
 // @Summary Обновление токена доступа
@@ -216,9 +211,6 @@ func (h *AuthHandler) refresh(c *gin.Context) {
 
 	// Получение токена обновления из файла cookie
 	refreshToken, err := c.Cookie(viper.GetString("environment.refresh_token_key"))
-
-	fmt.Println(refreshToken)
-
 	if err != nil {
 		utilContext.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
@@ -238,7 +230,7 @@ func (h *AuthHandler) refresh(c *gin.Context) {
 	}, refreshToken)
 
 	if err != nil {
-		utilContext.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		utilContext.NewAuthErrorResponse(c, http.StatusUnauthorized, err)
 		return
 	}
 
@@ -305,25 +297,129 @@ func (h *AuthHandler) logout(c *gin.Context) {
 
 // @Summary Активация аккаунта по почте
 // @Tags API для авторизации и регистрации пользователя
-// @Description Активация аккаунта по почте
+// @Description Активация аккаунта по ссылке из письма. Результат отображается HTML-страницей
 // @ID auth-activate
+// @Produce  html
+// @Param link path string true "activation link"
+// @Success 200 {string} string "html"
+// @Failure 404,409,410 {string} string "html"
+// @Failure 500 {string} string "html"
+// @Router /auth/activate/{link} [get]
+func (h *AuthHandler) activate(c *gin.Context) {
+	_, err := h.services.Activate(c.Params.ByName("link"))
+
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err {
+		case userModel.ErrActivationLinkNotFound:
+			status = http.StatusNotFound
+		case userModel.ErrActivationLinkUsed:
+			status = http.StatusConflict
+		case userModel.ErrActivationLinkExpired:
+			status = http.StatusGone
+		}
+
+		c.HTML(status, "account_activate.html", gin.H{
+			"title":   "Подтверждение аккаунта",
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.HTML(http.StatusOK, "account_activate.html", gin.H{
+		"title":   "Подтверждение аккаунта",
+		"success": true,
+	})
+}
+
+// @Summary Подтверждение смены email-адреса
+// @Tags API для авторизации и регистрации пользователя
+// @Description Подтверждение смены email-адреса по ссылке из письма на новый адрес. Результат отображается HTML-страницей
+// @ID auth-email-change-confirm
+// @Produce  html
+// @Param link path string true "confirm link"
+// @Success 200 {string} string "html"
+// @Failure 404,409,410 {string} string "html"
+// @Failure 500 {string} string "html"
+// @Router /auth/email/confirm/{link} [get]
+func (h *AuthHandler) emailChangeConfirm(c *gin.Context) {
+	_, err := h.services.EmailChange.Confirm(c.Params.ByName("link"))
+	emailChangeResult(c, "confirm", err)
+}
+
+// @Summary Отмена смены email-адреса
+// @Tags API для авторизации и регистрации пользователя
+// @Description Отмена смены email-адреса по ссылке из уведомления на текущий адрес. Результат отображается HTML-страницей
+// @ID auth-email-change-cancel
+// @Produce  html
+// @Param link path string true "cancel link"
+// @Success 200 {string} string "html"
+// @Failure 404,409 {string} string "html"
+// @Failure 500 {string} string "html"
+// @Router /auth/email/cancel/{link} [get]
+func (h *AuthHandler) emailChangeCancel(c *gin.Context) {
+	_, err := h.services.EmailChange.Cancel(c.Params.ByName("link"))
+	emailChangeResult(c, "cancel", err)
+}
+
+/* Отображение результата подтверждения или отмены смены email-адреса */
+func emailChangeResult(c *gin.Context, action string, err error) {
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err {
+		case userModel.ErrEmailChangeLinkNotFound:
+			status = http.StatusNotFound
+		case userModel.ErrEmailChangeLinkUsed, userModel.ErrEmailTaken:
+			status = http.StatusConflict
+		case userModel.ErrEmailChangeLinkExpired:
+			status = http.StatusGone
+		}
+
+		c.HTML(status, "email_change.html", gin.H{
+			"title":   "Смена email-адреса",
+			"action":  action,
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.HTML(http.StatusOK, "email_change.html", gin.H{
+		"title":   "Смена email-адреса",
+		"action":  action,
+		"success": true,
+	})
+}
+
+// @Summary Повторная отправка письма для подтверждения аккаунта
+// @Tags API для авторизации и регистрации пользователя
+// @Description Повторная отправка письма для подтверждения аккаунта. Ранее отправленная ссылка аннулируется
+// @ID auth-activate-resend
 // @Accept  json
 // @Produce  json
-// @Success 200 {object} LogoutOutputModel "data"
+// @Param input body userModel.UserEmailModel true "credentials"
+// @Success 200 {object} httpModel.ResponseMessage "data"
 // @Failure 400,404 {object} httpModel.ResponseMessage
 // @Failure 500 {object} httpModel.ResponseMessage
 // @Failure default {object} httpModel.ResponseMessage
-// @Router /auth/activate [get]
-func (h *AuthHandler) activate(c *gin.Context) {
-	_, err := h.services.Activate(c.Params.ByName("link"))
+// @Router /auth/activate/resend [post]
+func (h *AuthHandler) activateResend(c *gin.Context) {
+	var input userModel.UserEmailModel
 
+	if err := c.BindJSON(&input); err != nil || input.Email == "" {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	_, err := h.services.Authorization.ResendActivation(input.Email)
 	if err != nil {
 		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	c.HTML(http.StatusOK, "account_activate.html", gin.H{
-		"title": "Подтверждение аккаунта",
+	c.JSON(http.StatusOK, httpModel.ResponseMessage{
+		Message: "Если аккаунт с данным почтовым адресом не подтверждён, на него было отправлено новое письмо",
 	})
 }
 
@@ -335,7 +431,7 @@ func (h *AuthHandler) activate(c *gin.Context) {
 // @Produce  json
 // @Param input body userModel.UserEmailModel true "credentials"
 // @Success 200 {object} httpModel.ResponseMessage "data"
-// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 400,404,429 {object} httpModel.ResponseMessage
 // @Failure 500 {object} httpModel.ResponseMessage
 // @Failure default {object} httpModel.ResponseMessage
 // @Router /auth/recovery/password [post]
@@ -347,14 +443,14 @@ func (h *AuthHandler) recoveryPassword(c *gin.Context) {
 		return
 	}
 
-	_, err := h.services.Authorization.RecoveryPassword(input.Email)
+	_, err := h.services.Authorization.RecoveryPassword(input.Email, utilContext.GetSessionInfo(c))
 	if err != nil {
-		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		utilContext.NewAuthErrorResponse(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, httpModel.ResponseMessage{
-		Message: "На Вашу почту была отправлена ссылка с подтверждением изменения пароля",
+		Message: "Если пользователь с данным email-адресом существует, на его почту была отправлена ссылка с подтверждением изменения пароля",
 	})
 }
 
@@ -380,7 +476,7 @@ func (h *AuthHandler) resetPassword(c *gin.Context) {
 
 	_, err := h.services.Authorization.ResetPassword(input)
 	if err != nil {
-		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		utilContext.NewAuthErrorResponse(c, http.StatusInternalServerError, err)
 		return
 	}
 
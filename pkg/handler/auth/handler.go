@@ -29,7 +29,7 @@ func (h *AuthHandler) InitRoutes(
 	middleware *map[string]func(c *gin.Context),
 ) {
 	// URL: /auth
-	auth := h.rootHandler.Group(route.AUTH_MAIN_ROUTE)
+	auth := h.rootHandler.Group(route.AUTH_MAIN_ROUTE, (*middleware)[middlewareConstant.MN_RL_AUTH])
 	{
 		// URL: /auth/sign-up
 		auth.POST(route.SIGN_UP, h.signUp)
@@ -37,12 +37,60 @@ func (h *AuthHandler) InitRoutes(
 		// URL: /auth/sign-in
 		auth.POST(route.SIGN_IN, h.signIn)
 
-		// URL: /auth/sign-in/oauth2
-		auth.POST(route.SIGN_IN_OAUTH2, h.signInOAuth2)
+		// URL: /auth/sign-in/:provider
+		auth.POST(route.SIGN_IN_PROVIDER, h.signInOAuth2)
+
+		// URL: /auth/sign-in/:provider/url
+		auth.GET(route.SIGN_IN_PROVIDER_URL, h.signInOAuth2Url)
+
+		// URL: /auth/mfa
+		mfa := auth.Group(route.MFA)
+		{
+			// URL: /auth/mfa/verify
+			mfa.POST(route.MFA_VERIFY, h.mfaVerify)
+
+			// URL: /auth/mfa/totp/setup
+			mfa.POST(route.MFA_TOTP_SETUP, h.mfaTotpSetup)
+
+			// URL: /auth/mfa/totp/enable
+			mfa.POST(route.MFA_TOTP_ENABLE, h.mfaTotpEnable)
+		}
+
+		// URL: /auth/webauthn
+		webAuthn := auth.Group(route.WEBAUTHN)
+		{
+			// URL: /auth/webauthn/login/begin
+			webAuthn.POST(route.WEBAUTHN_LOGIN_BEGIN, h.webAuthnLoginBegin)
+
+			// URL: /auth/webauthn/login/finish
+			webAuthn.POST(route.WEBAUTHN_LOGIN_FINISH, h.webAuthnLoginFinish)
+		}
+
+		// URL: /auth/passwordless
+		passwordless := auth.Group(route.PASSWORDLESS)
+		{
+			// URL: /auth/passwordless/request
+			passwordless.POST(route.PASSWORDLESS_REQUEST, h.passwordlessRequest)
+
+			// URL: /auth/passwordless/link
+			passwordless.POST(route.PASSWORDLESS_LINK, h.passwordlessLink)
+
+			// URL: /auth/passwordless/code
+			passwordless.POST(route.PASSWORDLESS_CODE, h.passwordlessCode)
+		}
 
 		// URL: /auth/activate/:link
 		auth.GET(route.ACTIVATE_LINK, h.activate)
 
+		// URL: /auth/activate/resend
+		auth.POST(route.ACTIVATE_RESEND, h.activateResend)
+
+		// URL: /auth/email/confirm/:link
+		auth.GET(route.EMAIL_CHANGE_CONFIRM, h.emailChangeConfirm)
+
+		// URL: /auth/email/cancel/:link
+		auth.GET(route.EMAIL_CHANGE_CANCEL, h.emailChangeCancel)
+
 		// URL: /auth/refresh
 		auth.POST(route.REFRESH, (*middleware)[middlewareConstant.MN_UI_LOGOUT], h.refresh)
 
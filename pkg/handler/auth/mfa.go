@@ -0,0 +1,110 @@
+package auth
+
+import (
+	config "main-server/config"
+	utilContext "main-server/pkg/handler/util"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// @Summary Подтверждение входа вторым фактором
+// @Tags API для авторизации и регистрации пользователя
+// @Description Завершение входа по токену ожидания и коду из приложения-аутентификатора (или коду восстановления)
+// @ID auth-mfa-verify
+// @Accept  json
+// @Produce  json
+// @Param input body userModel.MfaVerifyModel true "Токен ожидания и код подтверждения"
+// @Success 200 {object} userModel.TokenAccessModel "data"
+// @Failure 400,401,404,429 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /auth/mfa/verify [post]
+func (h *AuthHandler) mfaVerify(c *gin.Context) {
+	var input userModel.MfaVerifyModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Mfa.VerifyLogin(input, utilContext.GetSessionInfo(c))
+	if err != nil {
+		utilContext.NewAuthErrorResponse(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	// Добавление токена обновления в http only cookie
+	c.SetCookie(viper.GetString("environment.refresh_token_key"), data.RefreshToken,
+		30*24*60*60*1000, "/", viper.GetString("environment.domain"), false, true)
+	c.SetSameSite(config.HTTPSameSite)
+
+	c.JSON(http.StatusOK, userModel.TokenAccessModel{
+		AccessToken: data.AccessToken,
+	})
+}
+
+// @Summary Получение секрета для обязательного второго фактора
+// @Tags API для авторизации и регистрации пользователя
+// @Description Генерация секрета TOTP во время входа, если второй фактор обязателен для роли пользователя, но ещё не подключён
+// @ID auth-mfa-totp-setup
+// @Accept  json
+// @Produce  json
+// @Param input body userModel.MfaTokenModel true "Токен ожидания"
+// @Success 200 {object} userModel.MfaTotpSetupModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /auth/mfa/totp/setup [post]
+func (h *AuthHandler) mfaTotpSetup(c *gin.Context) {
+	var input userModel.MfaTokenModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Mfa.SetupTotpLogin(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Подключение обязательного второго фактора
+// @Tags API для авторизации и регистрации пользователя
+// @Description Подключение второго фактора по первому коду из приложения-аутентификатора с завершением входа
+// @ID auth-mfa-totp-enable
+// @Accept  json
+// @Produce  json
+// @Param input body userModel.MfaVerifyModel true "Токен ожидания и код подтверждения"
+// @Success 200 {object} userModel.MfaEnrolledModel "data"
+// @Failure 400,404,429 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /auth/mfa/totp/enable [post]
+func (h *AuthHandler) mfaTotpEnable(c *gin.Context) {
+	var input userModel.MfaVerifyModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, codes, err := h.services.Mfa.EnableTotpLogin(input, utilContext.GetSessionInfo(c))
+	if err != nil {
+		utilContext.NewAuthErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// Добавление токена обновления в http only cookie
+	c.SetCookie(viper.GetString("environment.refresh_token_key"), data.RefreshToken,
+		30*24*60*60*1000, "/", viper.GetString("environment.domain"), false, true)
+	c.SetSameSite(config.HTTPSameSite)
+
+	c.JSON(http.StatusOK, userModel.MfaEnrolledModel{
+		AccessToken:   data.AccessToken,
+		RecoveryCodes: codes.Codes,
+	})
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	config "main-server/config"
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// @Summary Запрос письма для входа без пароля
+// @Tags API для авторизации и регистрации пользователя
+// @Description Отправка на почту одноразовой ссылки и кода для входа (не чаще одного письма в минуту)
+// @ID auth-passwordless-request
+// @Accept  json
+// @Produce  json
+// @Param input body userModel.PasswordlessRequestModel true "Email пользователя"
+// @Success 200 {object} httpModel.ResponseMessage "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /auth/passwordless/request [post]
+func (h *AuthHandler) passwordlessRequest(c *gin.Context) {
+	var input userModel.PasswordlessRequestModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	if _, err := h.services.Authorization.RequestLoginCode(input.Email); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseMessage{
+		Message: "Если пользователь с данным email-адресом существует, на его почту было отправлено письмо для входа",
+	})
+}
+
+// @Summary Вход по ссылке из письма
+// @Tags API для авторизации и регистрации пользователя
+// @Description Вход по одноразовой ссылке из письма
+// @ID auth-passwordless-link
+// @Accept  json
+// @Produce  json
+// @Param input body userModel.PasswordlessLinkModel true "Токен из ссылки"
+// @Success 200 {object} userModel.TokenAccessModel "data"
+// @Success 200 {object} userModel.MfaPendingModel "mfa (если требуется второй фактор)"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /auth/passwordless/link [post]
+func (h *AuthHandler) passwordlessLink(c *gin.Context) {
+	var input userModel.PasswordlessLinkModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.Authorization.LoginByLink(input, utilContext.GetSessionInfo(c))
+	if err != nil {
+		utilContext.NewAuthErrorResponse(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	h.passwordlessResponse(c, data)
+}
+
+// @Summary Вход по коду из письма
+// @Tags API для авторизации и регистрации пользователя
+// @Description Вход по одноразовому коду из письма (количество попыток ограничено)
+// @ID auth-passwordless-code
+// @Accept  json
+// @Produce  json
+// @Param input body userModel.PasswordlessCodeModel true "Email пользователя и код"
+// @Success 200 {object} userModel.TokenAccessModel "data"
+// @Success 200 {object} userModel.MfaPendingModel "mfa (если требуется второй фактор)"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /auth/passwordless/code [post]
+func (h *AuthHandler) passwordlessCode(c *gin.Context) {
+	var input userModel.PasswordlessCodeModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.Authorization.LoginByCode(input, utilContext.GetSessionInfo(c))
+	if err != nil {
+		utilContext.NewAuthErrorResponse(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	h.passwordlessResponse(c, data)
+}
+
+/* Ответ на успешный вход без пароля */
+func (h *AuthHandler) passwordlessResponse(c *gin.Context, data userModel.UserAuthDataModel) {
+	// Вход ожидает подтверждения вторым фактором
+	if data.Mfa != nil {
+		c.JSON(http.StatusOK, data.Mfa)
+		return
+	}
+
+	// Добавление токена обновления в http only cookie
+	c.SetCookie(viper.GetString("environment.refresh_token_key"), data.RefreshToken,
+		30*24*60*60*1000, "/", viper.GetString("environment.domain"), false, true)
+	c.SetSameSite(config.HTTPSameSite)
+
+	c.JSON(http.StatusOK, userModel.TokenAccessModel{
+		AccessToken: data.AccessToken,
+	})
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	config "main-server/config"
+	utilContext "main-server/pkg/handler/util"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// @Summary Начало входа по ключу доступа
+// @Tags API для авторизации и регистрации пользователя
+// @Description Получение параметров для navigator.credentials.get() и идентификатора церемонии
+// @ID auth-webauthn-login-begin
+// @Accept  json
+// @Produce  json
+// @Param input body userModel.WebAuthnLoginBeginModel true "Email пользователя"
+// @Success 200 {object} userModel.WebAuthnBeginModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /auth/webauthn/login/begin [post]
+func (h *AuthHandler) webAuthnLoginBegin(c *gin.Context) {
+	var input userModel.WebAuthnLoginBeginModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.WebAuthn.BeginLogin(input.Email)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Завершение входа по ключу доступа
+// @Tags API для авторизации и регистрации пользователя
+// @Description Проверка ответа аутентификатора и выдача пары токенов
+// @ID auth-webauthn-login-finish
+// @Accept  json
+// @Produce  json
+// @Param input body userModel.WebAuthnLoginFinishModel true "Идентификатор церемонии и ответ аутентификатора"
+// @Success 200 {object} userModel.TokenAccessModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /auth/webauthn/login/finish [post]
+func (h *AuthHandler) webAuthnLoginFinish(c *gin.Context) {
+	var input userModel.WebAuthnLoginFinishModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.WebAuthn.FinishLogin(input, utilContext.GetSessionInfo(c))
+	if err != nil {
+		utilContext.NewAuthErrorResponse(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	// Добавление токена обновления в http only cookie
+	c.SetCookie(viper.GetString("environment.refresh_token_key"), data.RefreshToken,
+		30*24*60*60*1000, "/", viper.GetString("environment.domain"), false, true)
+	c.SetSameSite(config.HTTPSameSite)
+
+	c.JSON(http.StatusOK, userModel.TokenAccessModel{
+		AccessToken: data.AccessToken,
+	})
+}
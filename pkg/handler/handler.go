@@ -1,12 +1,21 @@
 package handler
 
 import (
+	config "main-server/config"
+	actionConstant "main-server/pkg/constant/action"
 	middlewareConstant "main-server/pkg/constant/middleware"
+	oidcConstant "main-server/pkg/constant/oidc"
+	roleConstant "main-server/pkg/constant/role"
+	scopeConstant "main-server/pkg/constant/scope"
+	adminHandler "main-server/pkg/handler/admin"
 	authHandler "main-server/pkg/handler/auth"
+	oidcHandler "main-server/pkg/handler/oidc"
 	serviceHandler "main-server/pkg/handler/service"
+	userHandler "main-server/pkg/handler/user"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 
 	_ "main-server/docs"
@@ -31,6 +40,11 @@ func NewHandler(services *service.Service) *Handler {
 func (h *Handler) InitRoutes() *gin.Engine {
 	router := gin.New()
 
+	// Адрес клиента (c.ClientIP) определяется по X-Forwarded-For только за доверенными прокси-серверами
+	if err := router.SetTrustedProxies(config.HTTPTrustedProxies); err != nil {
+		logrus.Fatalf("failed to set trusted proxies: %s", err.Error())
+	}
+
 	// Установка максимального размера тела Multipart
 	router.MaxMultipartMemory = 50 << 20 // 50 MiB
 
@@ -56,6 +70,32 @@ func (h *Handler) InitRoutes() *gin.Engine {
 	middleware := make(map[string]func(c *gin.Context))
 	middleware[middlewareConstant.MN_UI] = h.userIdentity
 	middleware[middlewareConstant.MN_UI_LOGOUT] = h.userIdentityLogout
+	middleware[middlewareConstant.MN_UI_HAS_ROLES_ADMIN_OR_SUPER_ADMIN] = h.userIdentityHasRoles(
+		"OR", roleConstant.ROLE_ADMIN, roleConstant.ROLE_SUPER_ADMIN,
+	)
+	middleware[middlewareConstant.MN_UI_HAS_ROLE_SUPER_ADMIN] = h.userIdentityHasRole(roleConstant.ROLE_SUPER_ADMIN)
+
+	// Ограничение частоты запросов
+	middleware[middlewareConstant.MN_RL_AUTH] = h.rateLimit(middlewareConstant.RATE_LIMIT_POLICY_AUTH)
+	middleware[middlewareConstant.MN_RL_USER] = h.rateLimit(middlewareConstant.RATE_LIMIT_POLICY_USER)
+	middleware[middlewareConstant.MN_RL_ADMIN] = h.rateLimit(middlewareConstant.RATE_LIMIT_POLICY_ADMIN)
+	middleware[middlewareConstant.MN_RL_SERVICE] = h.rateLimit(middlewareConstant.RATE_LIMIT_POLICY_SERVICE)
+	middleware[middlewareConstant.MN_RL_EMAIL] = h.rateLimit(middlewareConstant.RATE_LIMIT_POLICY_EMAIL)
+
+	// Проверка областей доступа API-ключей и токенов клиентов OAuth2
+	middleware[middlewareConstant.MN_UI_HAS_SCOPE_SERVICE_VERIFY] = h.userIdentityHasScope(scopeConstant.SERVICE_VERIFY)
+	middleware[middlewareConstant.MN_UI_HAS_SCOPE_SERVICE_EMAIL] = h.userIdentityHasScope(scopeConstant.SERVICE_EMAIL)
+	middleware[middlewareConstant.MN_UI_HAS_SCOPE_SERVICE_OBJECT] = h.userIdentityHasScope(scopeConstant.SERVICE_OBJECT)
+	middleware[middlewareConstant.MN_UI_HAS_SCOPE_PROFILE] = h.userIdentityHasScope(scopeConstant.PROFILE)
+	middleware[middlewareConstant.MN_UI_HAS_SCOPE_OPENID] = h.userIdentityHasScope(oidcConstant.SCOPE_OPENID)
+
+	// Проверка прав на объект, UUID которого передаётся в параметре пути
+	middleware[middlewareConstant.MN_UI_OBJECT_CREATE] = h.userIdentityHasPermission(actionConstant.CREATE, middlewareConstant.OBJECT_PARAM)
+	middleware[middlewareConstant.MN_UI_OBJECT_MODIFY] = h.userIdentityHasPermission(actionConstant.MODIFY, middlewareConstant.OBJECT_PARAM)
+	middleware[middlewareConstant.MN_UI_OBJECT_DELETE] = h.userIdentityHasPermission(actionConstant.DELETE, middlewareConstant.OBJECT_PARAM)
+	middleware[middlewareConstant.MN_UI_OBJECT_READ] = h.userIdentityHasPermission(actionConstant.READ, middlewareConstant.OBJECT_PARAM)
+	middleware[middlewareConstant.MN_UI_OBJECT_ADMINISTRATION] = h.userIdentityHasPermission(actionConstant.ADMINISTRATION, middlewareConstant.OBJECT_PARAM)
+	middleware[middlewareConstant.MN_UI_OBJECT_MANAGEMENT] = h.userIdentityHasPermission(actionConstant.MANAGEMENT, middlewareConstant.OBJECT_PARAM)
 
 	// Инициализация маршрутов для сервиса service
 	service := serviceHandler.NewServiceHandler(router, h.services)
@@ -65,5 +105,17 @@ func (h *Handler) InitRoutes() *gin.Engine {
 	auth := authHandler.NewAuthHandler(router, h.services)
 	auth.InitRoutes(&middleware)
 
+	// Инициализация маршрутов для провайдера OpenID Connect
+	oidc := oidcHandler.NewOidcHandler(router, h.services)
+	oidc.InitRoutes(&middleware)
+
+	// Инициализация маршрутов для сервиса user
+	user := userHandler.NewUserHandler(router, h.services)
+	user.InitRoutes(&middleware)
+
+	// Инициализация маршрутов для сервиса admin
+	admin := adminHandler.NewAdminHandler(router, h.services)
+	admin.InitRoutes(&middleware)
+
 	return router
 }
@@ -1,9 +1,15 @@
 package handler
 
 import (
+	"fmt"
+	config "main-server/config"
+	authConstants "main-server/pkg/constant/auth"
 	middlewareConstants "main-server/pkg/constant/middleware"
+	"main-server/pkg/constant/route"
 	utilContext "main-server/pkg/handler/util"
+	userModel "main-server/pkg/model/user"
 	authService "main-server/pkg/service/auth"
+	"main-server/pkg/util"
 	"net/http"
 	"strings"
 
@@ -11,6 +17,40 @@ import (
 	"github.com/spf13/viper"
 )
 
+/* Маршруты, доступные пользователю до подтверждения аккаунта при любой политике */
+var activationExemptRoutes = map[string]bool{
+	route.AUTH_MAIN_ROUTE + route.SIGN_UP_UPLOAD_IMAGE: true,
+}
+
+/* Маршруты, дополнительно доступные до подтверждения аккаунта при политике limited */
+var activationLimitedRoutes = map[string]bool{
+	route.USER + route.PROFILE:                              true,
+	route.USER + route.EMAIL_CHANGE:                         true,
+	route.USER + route.SESSION + route.GET_ALL:              true,
+	route.USER + route.SESSION + route.DELETE:               true,
+	route.USER + route.SESSION + route.SESSION_DELETE_OTHER: true,
+}
+
+const serviceExternalRoute = route.SERVICE + route.SERVICE_EXTERNAL
+
+/*
+* Маршруты, доступные по API-ключу и токену клиента OAuth2 (прочие маршруты для них недоступны).
+* Необходимые области доступа проверяются на самих маршрутах (userIdentityHasScope)
+ */
+var scopedRoutes = map[string]bool{
+	serviceExternalRoute + route.SERVICE_VERIFY:                                                true,
+	serviceExternalRoute + route.SERVICE_EMAIL_SEND:                                            true,
+	serviceExternalRoute + route.SERVICE_OBJECT + route.CREATE:                                 true,
+	serviceExternalRoute + route.SERVICE_OBJECT + route.SERVICE_OBJECT_ID + route.DELETE:       true,
+	serviceExternalRoute + route.SERVICE_OBJECT + route.SERVICE_OBJECT_ID + route.ACCESS_CHECK: true,
+
+	route.USER + route.PROFILE:      true,
+	route.USER + route.ROLES:        true,
+	route.USER + route.ACCESS_CHECK: true,
+
+	route.OIDC_MAIN_ROUTE + route.USERINFO: true,
+}
+
 /* Метод проверки пользователя при обращении к вычислительным ресурсам системы */
 func (h *Handler) userIdentity(c *gin.Context) {
 	header := c.GetHeader(middlewareConstants.AUTHORIZATION_HEADER)
@@ -26,28 +66,52 @@ func (h *Handler) userIdentity(c *gin.Context) {
 		return
 	}
 
+	// Авторизация по API-ключу пользователя или сервисного аккаунта
+	if headerParts[0] == middlewareConstants.API_KEY_SCHEME {
+		h.apiKeyIdentity(c, headerParts[1])
+		return
+	}
+
 	data, err := h.services.Token.ParseToken(headerParts[1], viper.GetString("token.signing_key_access"))
 	if err != nil {
 		utilContext.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
+	// Токен, отозванный при выходе, блокировке или завершении сессии, отклоняется до истечения срока действия
+	revoked, err := h.services.Token.IsRevoked(data.TokenId)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if revoked {
+		utilContext.NewErrorResponse(c, http.StatusUnauthorized, "Токен доступа отозван")
+		return
+	}
+
 	domain, err := h.services.Domain.Get("value", viper.GetString("domain"), true)
 	if err != nil {
 		utilContext.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	switch data.AuthType.Value {
-	case "GOOGLE":
-		if result, err := authService.VerifyAccessToken(*data.TokenApi); err != nil || result != true {
+	// Токен внешнего провайдера должен оставаться действительным
+	if provider, err := authService.GetProvider(data.AuthType.Value); err == nil && data.TokenApi != nil {
+		if result, err := provider.Verify(c.Request.Context(), *data.TokenApi); err != nil || !result {
 			utilContext.NewErrorResponse(c, http.StatusUnauthorized, "Не действительный токен доступа")
 			return
 		}
-		break
+	}
 
-	case "LOCAL":
-		break
+	// Токен клиента OAuth2 (client_credentials или authorization_code) ограничен набором маршрутов и областями доступа
+	if data.ClientId != "" && !scopedRoutes[c.FullPath()] {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, "Данный маршрут недоступен при авторизации токеном клиента")
+		return
+	}
+
+	if !h.checkUserAccess(c, data.UsersId) {
+		return
 	}
 
 	// Добавление к контексту дополнительных данных о пользователе
@@ -56,8 +120,54 @@ func (h *Handler) userIdentity(c *gin.Context) {
 	c.Set(middlewareConstants.AUTH_TYPE_VALUE_CTX, data.AuthType.Value)
 	c.Set(middlewareConstants.TOKEN_API_CTX, data.TokenApi)
 	c.Set(middlewareConstants.ACCESS_TOKEN_CTX, headerParts[1])
+	c.Set(middlewareConstants.SESSION_CTX, data.SessionUuid)
 	c.Set(middlewareConstants.DOMAINS_ID, domain.Id)
 	c.Set(middlewareConstants.DOMAINS_UUID, domain.Uuid)
+
+	if data.ClientId != "" {
+		c.Set(middlewareConstants.CLIENT_CTX, data.ClientId)
+		c.Set(middlewareConstants.SCOPES_CTX, data.Scopes)
+	}
+}
+
+/* Проверка блокировки и подтверждения аккаунта пользователя (при отказе ответ уже отправлен) */
+func (h *Handler) checkUserAccess(c *gin.Context, userId int) bool {
+	// Токены заблокированного пользователя отклоняются до истечения их срока действия
+	ban, err := h.services.Ban.GetActive(userId)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return false
+	}
+
+	if ban != nil {
+		utilContext.NewAuthErrorResponse(c, http.StatusForbidden, &userModel.BanError{Ban: ban})
+		return false
+	}
+
+	// Ограничение доступа к API до подтверждения аккаунта
+	if !h.activationAllowed(c.FullPath()) {
+		activated, err := h.services.Authorization.IsActivated(userId)
+		if err != nil {
+			utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return false
+		}
+
+		if !activated {
+			utilContext.NewErrorResponse(c, http.StatusForbidden, userModel.ErrAccountNotActivated.Error())
+			return false
+		}
+	}
+
+	return true
+}
+
+/* Проверка, доступен ли маршрут без подтверждения аккаунта согласно текущей политике */
+func (h *Handler) activationAllowed(path string) bool {
+	if !config.AppActivationPolicy.IsEnforced() || activationExemptRoutes[path] {
+		return true
+	}
+
+	return config.AppActivationPolicy.Mode == authConstants.ACTIVATION_POLICY_LIMITED && activationLimitedRoutes[path]
 }
 
 /* Метод проверки пользовательских данных при выходе из системы */
@@ -86,6 +196,7 @@ func (h *Handler) userIdentityLogout(c *gin.Context) {
 	c.Set(middlewareConstants.AUTH_TYPE_VALUE_CTX, data.AuthType.Value)
 	c.Set(middlewareConstants.TOKEN_API_CTX, data.TokenApi)
 	c.Set(middlewareConstants.ACCESS_TOKEN_CTX, headerParts[1])
+	c.Set(middlewareConstants.SESSION_CTX, data.SessionUuid)
 }
 
 /* Метод проверки наличия у пользователя определённых ролей */
@@ -218,3 +329,52 @@ func (h *Handler) userIdentityHasRoleWithSubject(subjectId, role string) func(c
 		}
 	}
 }
+
+/* Метод проверки возможности выполнения пользователем действия над объектом (UUID объекта - параметр пути) */
+func (h *Handler) userIdentityHasPermission(action, param string) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		userIdentity, err := utilContext.GetContextUserInfo(c)
+		if err != nil {
+			utilContext.NewErrorResponse(c, http.StatusForbidden, "Нет доступа!")
+			return
+		}
+
+		objectUuid := c.Param(param)
+		if objectUuid == "" {
+			utilContext.NewErrorResponse(c, http.StatusBadRequest, "Не указан идентификатор объекта!")
+			return
+		}
+
+		access, err := h.services.Object.Enforce(userIdentity.UserId, userIdentity.DomainId, objectUuid, action)
+
+		if (err != nil) || (!access) {
+			utilContext.NewErrorResponse(c, http.StatusForbidden, "Нет доступа!")
+			return
+		}
+	}
+}
+
+/*
+* Метод проверки области доступа API-ключа или токена клиента OAuth2. Токены пользовательских
+* сессий областей доступа не имеют и ограничиваются только ролями
+ */
+func (h *Handler) userIdentityHasScope(scope string) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		value, exists := c.Get(middlewareConstants.SCOPES_CTX)
+		if !exists {
+			return
+		}
+
+		scopes, ok := value.([]string)
+		if !ok {
+			utilContext.NewErrorResponse(c, http.StatusForbidden, "Нет доступа!")
+			return
+		}
+
+		if has, _ := util.InArray(scope, scopes); !has {
+			utilContext.NewErrorResponseWithCode(c, http.StatusForbidden, middlewareConstants.ERROR_CODE_INSUFFICIENT_SCOPE,
+				fmt.Sprintf("Недостаточно прав: требуется область доступа %s", scope))
+			return
+		}
+	}
+}
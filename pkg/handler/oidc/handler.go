@@ -0,0 +1,60 @@
+package oidc
+
+import (
+	_ "main-server/docs"
+
+	middlewareConstant "main-server/pkg/constant/middleware"
+	"main-server/pkg/constant/route"
+	service "main-server/pkg/service"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/swaggo/files"
+	_ "github.com/swaggo/gin-swagger"
+)
+
+type OidcHandler struct {
+	rootHandler *gin.Engine
+	services    *service.Service
+}
+
+func NewOidcHandler(root *gin.Engine, services *service.Service) *OidcHandler {
+	return &OidcHandler{
+		rootHandler: root,
+		services:    services,
+	}
+}
+
+/* Инициализация маршрутов провайдера OpenID Connect */
+func (h *OidcHandler) InitRoutes(
+	middleware *map[string]func(c *gin.Context),
+) {
+	// URL: /.well-known/openid-configuration
+	h.rootHandler.GET(route.WELL_KNOWN+route.OPENID_CONFIGURATION, h.discovery)
+
+	// URL: /oidc
+	oidc := h.rootHandler.Group(route.OIDC_MAIN_ROUTE)
+	{
+		// URL: /oidc/authorize
+		oidc.GET(route.AUTHORIZE, h.authorize)
+		oidc.POST(route.AUTHORIZE, (*middleware)[middlewareConstant.MN_UI], h.authorizeConfirm)
+
+		// URL: /oidc/token
+		oidc.POST(route.TOKEN, (*middleware)[middlewareConstant.MN_RL_AUTH], h.token)
+
+		// URL: /oidc/userinfo
+		oidc.GET(route.USERINFO, (*middleware)[middlewareConstant.MN_UI], (*middleware)[middlewareConstant.MN_UI_HAS_SCOPE_OPENID], h.userInfo)
+	}
+
+	// URL: /oauth
+	oauth := h.rootHandler.Group(route.OAUTH_MAIN_ROUTE)
+	{
+		// URL: /oauth/token
+		oauth.POST(route.TOKEN, (*middleware)[middlewareConstant.MN_RL_AUTH], h.token)
+
+		// URL: /oauth/introspect (без ограничения частоты: шлюзы обращаются к нему при каждом запросе)
+		oauth.POST(route.INTROSPECT, h.introspect)
+
+		// URL: /oauth/revoke
+		oauth.POST(route.REVOKE, (*middleware)[middlewareConstant.MN_RL_AUTH], h.revoke)
+	}
+}
@@ -0,0 +1,263 @@
+package oidc
+
+import (
+	"errors"
+	oidcConstants "main-server/pkg/constant/oidc"
+	utilContext "main-server/pkg/handler/util"
+	oidcModel "main-server/pkg/model/oidc"
+	service "main-server/pkg/service"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// @Summary Документ обнаружения OpenID Connect
+// @Tags API провайдера OpenID Connect
+// @Description Документ обнаружения OpenID Connect
+// @ID oidc-discovery
+// @Produce  json
+// @Success 200 {object} oidcModel.DiscoveryModel "data"
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /.well-known/openid-configuration [get]
+func (h *OidcHandler) discovery(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.services.Oidc.GetDiscovery())
+}
+
+// @Summary Запрос авторизации
+// @Tags API провайдера OpenID Connect
+// @Description Проверка параметров запроса и перенаправление пользователя на страницу входа клиентского приложения
+// @ID oidc-authorize
+// @Param input query oidcModel.AuthorizeModel true "Параметры запроса авторизации"
+// @Success 302
+// @Failure 400,401 {object} oidcModel.ErrorModel
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Router /oidc/authorize [get]
+func (h *OidcHandler) authorize(c *gin.Context) {
+	var input oidcModel.AuthorizeModel
+
+	if err := c.ShouldBindQuery(&input); err != nil {
+		oauthErrorResponse(c, oidcModel.NewError(oidcConstants.ERROR_INVALID_REQUEST, err.Error()))
+		return
+	}
+
+	client, err := h.services.Oidc.ValidateAuthorize(input)
+	if err != nil {
+		var oauthError *oidcModel.ErrorModel
+
+		// Ошибки передаются клиенту только на зарегистрированный адрес перенаправления
+		if client != nil && errors.As(err, &oauthError) {
+			params := url.Values{}
+			params.Set("error", oauthError.Code)
+			params.Set("error_description", oauthError.Description)
+			if input.State != nil {
+				params.Set("state", *input.State)
+			}
+
+			c.Redirect(http.StatusFound, service.AppendQuery(input.RedirectUri, params))
+			return
+		}
+
+		oauthErrorResponse(c, err)
+		return
+	}
+
+	// Вход пользователя и подтверждение выполняются клиентским приложением, которое затем
+	// отправляет те же параметры на POST /oidc/authorize
+	loginUrl := viper.GetString("oidc.login_url")
+	if loginUrl == "" {
+		loginUrl = viper.GetString("client_url")
+	}
+
+	c.Redirect(http.StatusFound, service.AppendQuery(loginUrl, c.Request.URL.Query()))
+}
+
+// @Summary Подтверждение авторизации
+// @Tags API провайдера OpenID Connect
+// @Description Выдача кода авторизации текущему пользователю
+// @ID oidc-authorize-confirm
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body oidcModel.AuthorizeModel true "Параметры запроса авторизации"
+// @Success 200 {object} oidcModel.AuthorizeRedirectModel "data"
+// @Failure 400,401 {object} oidcModel.ErrorModel
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /oidc/authorize [post]
+func (h *OidcHandler) authorizeConfirm(c *gin.Context) {
+	var input oidcModel.AuthorizeModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	// Код авторизации выдаётся только в рамках сессии, то есть после полного входа (включая второй фактор)
+	if _, err = utilContext.GetSessionUuid(c); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Oidc.Authorize(userIdentity, input)
+	if err != nil {
+		oauthErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Получение токенов
+// @Tags API провайдера OpenID Connect
+// @Description Обмен кода авторизации на токен доступа и ID-токен или выдача токена доступа сервисному аккаунту (client_credentials)
+// @ID oidc-token
+// @Accept  x-www-form-urlencoded
+// @Produce  json
+// @Param input formData oidcModel.TokenRequestModel true "Параметры запроса"
+// @Success 200 {object} oidcModel.TokenResponseModel "data"
+// @Failure 400,401 {object} oidcModel.ErrorModel
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /oidc/token [post]
+// @Router /oauth/token [post]
+func (h *OidcHandler) token(c *gin.Context) {
+	var input oidcModel.TokenRequestModel
+
+	if err := c.ShouldBind(&input); err != nil {
+		oauthErrorResponse(c, oidcModel.NewError(oidcConstants.ERROR_INVALID_REQUEST, err.Error()))
+		return
+	}
+
+	bindClientAuth(c, &input.ClientId, &input.ClientSecret)
+
+	data, err := h.services.Oidc.Token(input)
+	if err != nil {
+		oauthErrorResponse(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Интроспекция токена
+// @Tags API провайдера OpenID Connect
+// @Description Получение сведений о токене доступа или обновления (RFC 7662). Требуется аутентификация конфиденциального клиента
+// @ID oauth-introspect
+// @Accept  x-www-form-urlencoded
+// @Produce  json
+// @Param input formData oidcModel.TokenActionRequestModel true "Параметры запроса"
+// @Success 200 {object} oidcModel.IntrospectionModel "data"
+// @Failure 400,401 {object} oidcModel.ErrorModel
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /oauth/introspect [post]
+func (h *OidcHandler) introspect(c *gin.Context) {
+	var input oidcModel.TokenActionRequestModel
+
+	if err := c.ShouldBind(&input); err != nil {
+		oauthErrorResponse(c, oidcModel.NewError(oidcConstants.ERROR_INVALID_REQUEST, err.Error()))
+		return
+	}
+
+	bindClientAuth(c, &input.ClientId, &input.ClientSecret)
+
+	data, err := h.services.Oidc.Introspect(input)
+	if err != nil {
+		oauthErrorResponse(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Отзыв токена
+// @Tags API провайдера OpenID Connect
+// @Description Отзыв токена доступа или обновления с завершением сессии (RFC 7009). Требуется аутентификация конфиденциального клиента
+// @ID oauth-revoke
+// @Accept  x-www-form-urlencoded
+// @Param input formData oidcModel.TokenActionRequestModel true "Параметры запроса"
+// @Success 200
+// @Failure 400,401 {object} oidcModel.ErrorModel
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /oauth/revoke [post]
+func (h *OidcHandler) revoke(c *gin.Context) {
+	var input oidcModel.TokenActionRequestModel
+
+	if err := c.ShouldBind(&input); err != nil {
+		oauthErrorResponse(c, oidcModel.NewError(oidcConstants.ERROR_INVALID_REQUEST, err.Error()))
+		return
+	}
+
+	bindClientAuth(c, &input.ClientId, &input.ClientSecret)
+
+	if err := h.services.Oidc.Revoke(input); err != nil {
+		oauthErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// @Summary Сведения о пользователе
+// @Tags API провайдера OpenID Connect
+// @Description Сведения о текущем пользователе (UserInfo)
+// @ID oidc-userinfo
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} oidcModel.UserInfoModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /oidc/userinfo [get]
+func (h *OidcHandler) userInfo(c *gin.Context) {
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Oidc.GetUserInfo(c, userIdentity)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+/* Аутентификация клиента методом client_secret_basic (имеет приоритет над параметрами формы) */
+func bindClientAuth(c *gin.Context, clientId, clientSecret *string) {
+	if id, secret, ok := c.Request.BasicAuth(); ok {
+		*clientId = id
+		*clientSecret = secret
+	}
+}
+
+/* Ответ с ошибкой в формате OAuth 2.0 (прочие ошибки - в формате сервера) */
+func oauthErrorResponse(c *gin.Context, err error) {
+	var oauthError *oidcModel.ErrorModel
+	if !errors.As(err, &oauthError) {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	status := http.StatusBadRequest
+	if oauthError.Code == oidcConstants.ERROR_INVALID_CLIENT {
+		status = http.StatusUnauthorized
+	}
+
+	c.AbortWithStatusJSON(status, oauthError)
+}
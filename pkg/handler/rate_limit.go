@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	config "main-server/config"
+	middlewareConstants "main-server/pkg/constant/middleware"
+	utilContext "main-server/pkg/handler/util"
+	"main-server/pkg/service/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*
+* Метод ограничения частоты запросов по политике policyName. Для ключа user middleware
+* необходимо подключать после проверки пользователя (MN_UI), иначе используется IP-адрес
+ */
+func (h *Handler) rateLimit(policyName string) func(c *gin.Context) {
+	policy := config.AppRateLimitPolicies[policyName]
+	limiter := ratelimit.NewLimiter(ratelimit.Policy{
+		Limit:  policy.Limit,
+		Period: policy.Period,
+		Burst:  policy.Burst,
+	})
+
+	return func(c *gin.Context) {
+		if limiter.Disabled() {
+			return
+		}
+
+		result := limiter.Allow(rateLimitKey(c, policyName, policy.Key), time.Now())
+
+		// Заголовки RateLimit-* (draft-ietf-httpapi-ratelimit-headers). Оба заголовка описывают
+		// ёмкость корзины: Burst запросов за время её полного восстановления
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", result.Limit, ceilSeconds(limiter.Window())))
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			utilContext.NewErrorResponseWithCode(c, http.StatusTooManyRequests,
+				middlewareConstants.ERROR_CODE_RATE_LIMITED, "Слишком много запросов! Повторите попытку позже")
+			return
+		}
+	}
+}
+
+/* Формирование ключа корзины в соответствии с политикой */
+func rateLimitKey(c *gin.Context, policyName, keyType string) string {
+	switch keyType {
+	case middlewareConstants.RATE_LIMIT_KEY_USER:
+		if value, ok := c.Get(middlewareConstants.USER_UUID_CTX); ok {
+			if usersUuid, ok := value.(string); ok && usersUuid != "" {
+				return policyName + ":user:" + usersUuid
+			}
+		}
+	case middlewareConstants.RATE_LIMIT_KEY_ROUTE:
+		return policyName + ":route:" + c.Request.Method + " " + c.FullPath()
+	}
+
+	// Заголовок X-Forwarded-For учитывается только от доверенных прокси (http.trusted_proxies)
+	return policyName + ":ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -29,17 +29,48 @@ func NewServiceHandler(root *gin.Engine, services *service.Service) *ServiceHand
 func (h *ServiceHandler) InitRoutes(
 	middleware *map[string]func(c *gin.Context),
 ) {
+	// URL: /.well-known
+	wellKnown := h.rootHandler.Group(route.WELL_KNOWN)
+	{
+		// URL: /.well-known/jwks.json
+		wellKnown.GET(route.JWKS, h.serviceJWKS)
+	}
+
 	// URL: /service
-	service := h.rootHandler.Group(route.SERVICE, (*middleware)[middlewareConstant.MN_UI])
+	service := h.rootHandler.Group(
+		route.SERVICE,
+		(*middleware)[middlewareConstant.MN_UI],
+		(*middleware)[middlewareConstant.MN_RL_SERVICE],
+	)
 	{
 		// URL: /external
 		external := service.Group(route.SERVICE_EXTERNAL)
 		{
 			// URL: /verify
-			external.POST(route.SERVICE_VERIFY, h.serviceExternalVerify)
+			external.POST(route.SERVICE_VERIFY, (*middleware)[middlewareConstant.MN_UI_HAS_SCOPE_SERVICE_VERIFY], h.serviceExternalVerify)
 
 			// URL: /mail/send
-			external.POST(route.SERVICE_EMAIL_SEND, h.serviceMainEmailSend)
+			external.POST(route.SERVICE_EMAIL_SEND,
+				(*middleware)[middlewareConstant.MN_UI_HAS_SCOPE_SERVICE_EMAIL],
+				(*middleware)[middlewareConstant.MN_RL_EMAIL],
+				h.serviceMainEmailSend,
+			)
+
+			// URL: /object
+			object := external.Group(route.SERVICE_OBJECT, (*middleware)[middlewareConstant.MN_UI_HAS_SCOPE_SERVICE_OBJECT])
+			{
+				// URL: /object/create
+				object.POST(route.CREATE, h.serviceObjectCreate)
+
+				// URL: /object/:object_uuid/delete
+				object.POST(route.SERVICE_OBJECT_ID+route.DELETE,
+					(*middleware)[middlewareConstant.MN_UI_OBJECT_DELETE],
+					h.serviceObjectDelete,
+				)
+
+				// URL: /object/:object_uuid/access/check
+				object.POST(route.SERVICE_OBJECT_ID+route.ACCESS_CHECK, h.serviceObjectAccessCheck)
+			}
 		}
 	}
 }
@@ -0,0 +1,23 @@
+package service
+
+import (
+	serviceModel "main-server/pkg/model/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Открытые ключи подписи токенов доступа
+// @Tags API для внешних сервисов
+// @Description Набор открытых ключей (JWKS) для проверки токенов доступа без обращения к серверу
+// @ID service-jwks
+// @Produce  json
+// @Success 200 {object} serviceModel.JWKSModel "data"
+// @Router /.well-known/jwks.json [get]
+func (h *ServiceHandler) serviceJWKS(c *gin.Context) {
+	// Ключи меняются редко, поэтому клиентам разрешено кэшировать ответ
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, serviceModel.JWKSModel{
+		Keys: h.services.Token.GetJWKS(),
+	})
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	middlewareConstant "main-server/pkg/constant/middleware"
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	rbacModel "main-server/pkg/model/rbac"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Регистрация объекта
+// @Tags API для внешних сервисов
+// @Description Регистрация объекта (компания, проект, сущность, подсущность) с привязкой к родительскому объекту
+// @ID service-external-object-create
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.ResourceModel true "Информация об объекте"
+// @Success 200 {object} rbacModel.ObjectDbModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /service/external/object/create [post]
+func (h *ServiceHandler) serviceObjectCreate(c *gin.Context) {
+	var input rbacModel.ResourceModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Object.Create(userIdentity, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Удаление объекта
+// @Tags API для внешних сервисов
+// @Description Удаление объекта вместе со всеми дочерними объектами (требуется право delete)
+// @ID service-external-object-delete
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param object_uuid path string true "UUID объекта"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /service/external/object/{object_uuid}/delete [post]
+func (h *ServiceHandler) serviceObjectDelete(c *gin.Context) {
+	data, err := h.services.Object.Delete(c.Param(middlewareConstant.OBJECT_PARAM))
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
+
+// @Summary Проверка доступа к объекту
+// @Tags API для внешних сервисов
+// @Description Проверка возможности выполнения текущим пользователем действия над объектом
+// @ID service-external-object-access-check
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param object_uuid path string true "UUID объекта"
+// @Param input body rbacModel.ObjectActionModel true "Действие"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /service/external/object/{object_uuid}/access/check [post]
+func (h *ServiceHandler) serviceObjectAccessCheck(c *gin.Context) {
+	var input rbacModel.ObjectActionModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Object.Enforce(userIdentity.UserId, userIdentity.DomainId, c.Param(middlewareConstant.OBJECT_PARAM), input.Action)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
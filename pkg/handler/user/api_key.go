@@ -0,0 +1,112 @@
+package user
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Создание API-ключа
+// @Tags API для работы с данными пользователя
+// @Description Создание API-ключа текущего пользователя. Ключ передаётся в заголовке Authorization со схемой ApiKey и возвращается только один раз
+// @ID user-api-key-create
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.ApiKeyCreateModel true "Название, области доступа и срок действия ключа"
+// @Success 200 {object} userModel.ApiKeyCreatedModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/api-key/create [post]
+func (h *UserHandler) apiKeyCreate(c *gin.Context) {
+	var input userModel.ApiKeyCreateModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.ApiKey.Create(userIdentity, userIdentity.UserId, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Получение API-ключей пользователя
+// @Tags API для работы с данными пользователя
+// @Description Получение всех API-ключей текущего пользователя (включая отозванные)
+// @ID user-api-key-get-all
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} userModel.ApiKeysModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/api-key/get/all [get]
+func (h *UserHandler) apiKeyGetAll(c *gin.Context) {
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.ApiKey.GetAll(userIdentity.UserId)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Отзыв API-ключа
+// @Tags API для работы с данными пользователя
+// @Description Отзыв API-ключа текущего пользователя
+// @ID user-api-key-revoke
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.ApiKeyUuidModel true "UUID API-ключа"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/api-key/revoke [post]
+func (h *UserHandler) apiKeyRevoke(c *gin.Context) {
+	var input userModel.ApiKeyUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.ApiKey.Revoke(userIdentity.UserId, input.Uuid)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
@@ -0,0 +1,56 @@
+package user
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Запрос на смену email-адреса
+// @Tags API для работы с данными пользователя
+// @Description Запрос на смену email-адреса. На новый адрес отправляется ссылка для подтверждения, на текущий - уведомление со ссылкой для отмены. После подтверждения остальные сессии пользователя завершаются
+// @ID user-email-change
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.EmailChangeRequestModel true "Новый email-адрес и текущий пароль"
+// @Success 200 {object} userModel.EmailChangeModel "data"
+// @Failure 400,404,409 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/email/change [post]
+func (h *UserHandler) emailChange(c *gin.Context) {
+	var input userModel.EmailChangeRequestModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	sessionUuid, err := utilContext.GetSessionUuid(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.EmailChange.Request(userIdentity.UserId, sessionUuid, input)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err == userModel.ErrEmailTaken {
+			status = http.StatusConflict
+		}
+
+		utilContext.NewErrorResponse(c, status, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
@@ -0,0 +1,130 @@
+package user
+
+import (
+	_ "main-server/docs"
+
+	middlewareConstant "main-server/pkg/constant/middleware"
+	"main-server/pkg/constant/route"
+	service "main-server/pkg/service"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/swaggo/files"
+	_ "github.com/swaggo/gin-swagger"
+)
+
+type UserHandler struct {
+	rootHandler *gin.Engine
+	services    *service.Service
+}
+
+func NewUserHandler(root *gin.Engine, services *service.Service) *UserHandler {
+	return &UserHandler{
+		rootHandler: root,
+		services:    services,
+	}
+}
+
+/* Инициализация маршрутов для работы с данными пользователя */
+func (h *UserHandler) InitRoutes(
+	middleware *map[string]func(c *gin.Context),
+) {
+	// URL: /user
+	user := h.rootHandler.Group(
+		route.USER,
+		(*middleware)[middlewareConstant.MN_UI],
+		(*middleware)[middlewareConstant.MN_RL_USER],
+	)
+	{
+		// URL: /user/profile
+		user.GET(route.PROFILE, (*middleware)[middlewareConstant.MN_UI_HAS_SCOPE_PROFILE], h.getProfile)
+
+		// URL: /user/profile/update
+		user.POST(route.PROFILE+route.UPDATE, h.updateProfile)
+
+		// URL: /user/profile/image
+		user.POST(route.PROFILE+route.IMAGE, h.updateProfileImage)
+
+		// URL: /user/email/change
+		user.POST(route.EMAIL_CHANGE, h.emailChange)
+
+		// URL: /user/access/check
+		user.POST(route.ACCESS_CHECK, (*middleware)[middlewareConstant.MN_UI_HAS_SCOPE_PROFILE], h.accessCheck)
+
+		// URL: /user/roles
+		user.GET(route.ROLES, (*middleware)[middlewareConstant.MN_UI_HAS_SCOPE_PROFILE], h.getAllRoles)
+
+		// URL: /user/session
+		session := user.Group(route.SESSION)
+		{
+			// URL: /user/session/get/all
+			session.GET(route.GET_ALL, h.sessionGetAll)
+
+			// URL: /user/session/delete
+			session.POST(route.DELETE, h.sessionDelete)
+
+			// URL: /user/session/delete/other
+			session.POST(route.SESSION_DELETE_OTHER, h.sessionDeleteOther)
+		}
+
+		// URL: /user/mfa
+		mfa := user.Group(route.MFA)
+		{
+			// URL: /user/mfa
+			mfa.GET("", h.mfaStatus)
+
+			// URL: /user/mfa/totp/setup
+			mfa.POST(route.MFA_TOTP_SETUP, h.mfaTotpSetup)
+
+			// URL: /user/mfa/totp/enable
+			mfa.POST(route.MFA_TOTP_ENABLE, h.mfaTotpEnable)
+
+			// URL: /user/mfa/totp/disable
+			mfa.POST(route.MFA_TOTP_DISABLE, h.mfaTotpDisable)
+
+			// URL: /user/mfa/recovery/codes
+			mfa.POST(route.MFA_RECOVERY, h.mfaRecoveryCodes)
+		}
+
+		// URL: /user/webauthn
+		webAuthn := user.Group(route.WEBAUTHN)
+		{
+			// URL: /user/webauthn/get/all
+			webAuthn.GET(route.GET_ALL, h.webAuthnGetAll)
+
+			// URL: /user/webauthn/register/begin
+			webAuthn.POST(route.WEBAUTHN_REGISTER_BEGIN, h.webAuthnRegisterBegin)
+
+			// URL: /user/webauthn/register/finish
+			webAuthn.POST(route.WEBAUTHN_REGISTER_FINISH, h.webAuthnRegisterFinish)
+
+			// URL: /user/webauthn/delete
+			webAuthn.POST(route.DELETE, h.webAuthnDelete)
+		}
+
+		// URL: /user/api-key
+		apiKey := user.Group(route.API_KEY)
+		{
+			// URL: /user/api-key/create
+			apiKey.POST(route.CREATE, h.apiKeyCreate)
+
+			// URL: /user/api-key/get/all
+			apiKey.GET(route.GET_ALL, h.apiKeyGetAll)
+
+			// URL: /user/api-key/revoke
+			apiKey.POST(route.API_KEY_REVOKE, h.apiKeyRevoke)
+		}
+
+		// URL: /user/identity
+		identity := user.Group(route.IDENTITY)
+		{
+			// URL: /user/identity/get/all
+			identity.GET(route.GET_ALL, h.identityGetAll)
+
+			// URL: /user/identity/link/:provider
+			identity.POST(route.IDENTITY_LINK, h.identityLink)
+
+			// URL: /user/identity/unlink
+			identity.POST(route.IDENTITY_UNLINK, h.identityUnlink)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package user
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Получение внешних учётных записей пользователя
+// @Tags API для работы с данными пользователя
+// @Description Получение всех внешних учётных записей (Google, VK и т.д.), привязанных к текущему пользователю
+// @ID user-identity-get-all
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} userModel.IdentitiesModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/identity/get/all [get]
+func (h *UserHandler) identityGetAll(c *gin.Context) {
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Identity.GetAll(userIdentity.UserId)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Привязка внешней учётной записи
+// @Tags API для работы с данными пользователя
+// @Description Привязка внешней учётной записи к текущему пользователю по коду авторизации провайдера
+// @ID user-identity-link
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param provider path string true "Название провайдера (google, vk, github и т.д.)"
+// @Param input body userModel.UserLoginOAuth2Model true "Код авторизации провайдера"
+// @Success 200 {object} userModel.IdentityModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/identity/link/{provider} [post]
+func (h *UserHandler) identityLink(c *gin.Context) {
+	var input userModel.UserLoginOAuth2Model
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Identity.Link(userIdentity.UserId, c.Param("provider"), input.Code)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Отвязка внешней учётной записи
+// @Tags API для работы с данными пользователя
+// @Description Отвязка внешней учётной записи от текущего пользователя (последний способ входа отвязать нельзя)
+// @ID user-identity-unlink
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.IdentityUuidModel true "UUID внешней учётной записи"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/identity/unlink [post]
+func (h *UserHandler) identityUnlink(c *gin.Context) {
+	var input userModel.IdentityUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Identity.Unlink(userIdentity.UserId, input.Uuid)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
@@ -0,0 +1,176 @@
+package user
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Получение состояния двухфакторной аутентификации
+// @Tags API для работы с данными пользователя
+// @Description Получение состояния второго фактора текущего пользователя
+// @ID user-mfa-status
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} userModel.MfaStatusModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/mfa [get]
+func (h *UserHandler) mfaStatus(c *gin.Context) {
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Mfa.GetStatus(userIdentity.UserId)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Получение секрета для приложения-аутентификатора
+// @Tags API для работы с данными пользователя
+// @Description Генерация секрета TOTP и URI для QR-кода (второй фактор включается после подтверждения кодом)
+// @ID user-mfa-totp-setup
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} userModel.MfaTotpSetupModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/mfa/totp/setup [post]
+func (h *UserHandler) mfaTotpSetup(c *gin.Context) {
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Mfa.SetupTotp(userIdentity.UserId)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Включение двухфакторной аутентификации
+// @Tags API для работы с данными пользователя
+// @Description Включение второго фактора по первому коду из приложения-аутентификатора. Возвращает коды восстановления
+// @ID user-mfa-totp-enable
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.MfaCodeModel true "Код подтверждения"
+// @Success 200 {object} userModel.MfaRecoveryCodesModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/mfa/totp/enable [post]
+func (h *UserHandler) mfaTotpEnable(c *gin.Context) {
+	var input userModel.MfaCodeModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Mfa.EnableTotp(userIdentity.UserId, input.Code)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Отключение двухфакторной аутентификации
+// @Tags API для работы с данными пользователя
+// @Description Отключение второго фактора (недоступно, если он обязателен для роли пользователя)
+// @ID user-mfa-totp-disable
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.MfaCodeModel true "Код подтверждения"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/mfa/totp/disable [post]
+func (h *UserHandler) mfaTotpDisable(c *gin.Context) {
+	var input userModel.MfaCodeModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Mfa.DisableTotp(userIdentity.UserId, input.Code)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
+
+// @Summary Выпуск новых кодов восстановления
+// @Tags API для работы с данными пользователя
+// @Description Выпуск нового набора одноразовых кодов восстановления (старые коды перестают действовать)
+// @ID user-mfa-recovery-codes
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.MfaCodeModel true "Код подтверждения"
+// @Success 200 {object} userModel.MfaRecoveryCodesModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/mfa/recovery/codes [post]
+func (h *UserHandler) mfaRecoveryCodes(c *gin.Context) {
+	var input userModel.MfaCodeModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Mfa.RegenerateRecoveryCodes(userIdentity.UserId, input.Code)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
@@ -0,0 +1,186 @@
+package user
+
+import (
+	pathConstant "main-server/pkg/constant/path"
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	rbacModel "main-server/pkg/model/rbac"
+	resourceModel "main-server/pkg/model/resource"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	uuid "github.com/satori/go.uuid"
+)
+
+// @Summary Получение профиля пользователя
+// @Tags API для работы с данными пользователя
+// @Description Получение профиля пользователя
+// @ID user-profile
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} userModel.UserProfileModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/profile [get]
+func (h *UserHandler) getProfile(c *gin.Context) {
+	data, err := h.services.User.GetProfile(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Обновление профиля пользователя
+// @Tags API для работы с данными пользователя
+// @Description Обновление текстовых данных профиля пользователя (и пароля, если он передан)
+// @ID user-profile-update
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.UserProfileUpdateDataModel true "Новые данные профиля"
+// @Success 200 {object} userModel.UserDataDbModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/profile/update [post]
+func (h *UserHandler) updateProfile(c *gin.Context) {
+	var input userModel.UserProfileUpdateDataModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.User.UpdateProfile(c, input)
+	if err != nil {
+		utilContext.NewAuthErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Обновление изображения профиля пользователя
+// @Tags API для работы с данными пользователя
+// @Description Обновление изображения профиля пользователя (multipart/form-data, поле file)
+// @ID user-profile-image
+// @Accept  mpfd
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param file formData file true "Изображение профиля"
+// @Success 200 {object} resourceModel.ImageModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/profile/image [post]
+func (h *UserHandler) updateProfileImage(c *gin.Context) {
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Получение информации о файле из формы
+	images := form.File["file"]
+	if len(images) <= 0 {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, "Изображение профиля не передано")
+		return
+	}
+
+	profileImage := images[len(images)-1]
+	filepath := pathConstant.PUBLIC_USER + uuid.NewV4().String()
+
+	// Загрузка файла на сервер
+	if err := c.SaveUploadedFile(profileImage, filepath); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data, err := h.services.User.UpdateProfileImage(userIdentity, &resourceModel.ImageModel{
+		Filename: profileImage.Filename,
+		Filepath: filepath,
+	})
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Проверка наличия у пользователя роли
+// @Tags API для работы с данными пользователя
+// @Description Проверка наличия у текущего пользователя роли в рамках текущего домена
+// @ID user-access-check
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.RoleValueModel true "Значение роли"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/access/check [post]
+func (h *UserHandler) accessCheck(c *gin.Context) {
+	var input rbacModel.RoleValueModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.User.AccessCheck(userIdentity.UserId, userIdentity.DomainId, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
+
+// @Summary Получение всех ролей пользователя
+// @Tags API для работы с данными пользователя
+// @Description Получение всех ролей текущего пользователя в рамках текущего домена
+// @ID user-roles
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} userModel.UserRoleModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/roles [get]
+func (h *UserHandler) getAllRoles(c *gin.Context) {
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.User.GetAllRoles(*userIdentity)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
@@ -0,0 +1,140 @@
+package user
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Получение ключей доступа пользователя
+// @Tags API для работы с данными пользователя
+// @Description Получение всех ключей доступа (passkeys) текущего пользователя
+// @ID user-webauthn-get-all
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} userModel.WebAuthnCredentialsModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/webauthn/get/all [get]
+func (h *UserHandler) webAuthnGetAll(c *gin.Context) {
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.WebAuthn.GetAll(userIdentity.UserId)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Начало регистрации ключа доступа
+// @Tags API для работы с данными пользователя
+// @Description Получение параметров для navigator.credentials.create() и идентификатора церемонии
+// @ID user-webauthn-register-begin
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} userModel.WebAuthnBeginModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/webauthn/register/begin [post]
+func (h *UserHandler) webAuthnRegisterBegin(c *gin.Context) {
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.WebAuthn.BeginRegistration(userIdentity.UserId)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Завершение регистрации ключа доступа
+// @Tags API для работы с данными пользователя
+// @Description Проверка ответа аутентификатора и сохранение ключа доступа
+// @ID user-webauthn-register-finish
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.WebAuthnRegisterFinishModel true "Идентификатор церемонии и ответ аутентификатора"
+// @Success 200 {object} userModel.WebAuthnCredentialModel "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/webauthn/register/finish [post]
+func (h *UserHandler) webAuthnRegisterFinish(c *gin.Context) {
+	var input userModel.WebAuthnRegisterFinishModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.WebAuthn.FinishRegistration(userIdentity.UserId, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Удаление ключа доступа
+// @Tags API для работы с данными пользователя
+// @Description Удаление ключа доступа текущего пользователя (последний способ входа удалить нельзя)
+// @ID user-webauthn-delete
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.WebAuthnCredentialUuidModel true "UUID ключа доступа"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} httpModel.ResponseMessage
+// @Failure 500 {object} httpModel.ResponseMessage
+// @Failure default {object} httpModel.ResponseMessage
+// @Router /user/webauthn/delete [post]
+func (h *UserHandler) webAuthnDelete(c *gin.Context) {
+	var input userModel.WebAuthnCredentialUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdentity, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.WebAuthn.Delete(userIdentity.UserId, input.Uuid)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
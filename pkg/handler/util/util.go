@@ -2,8 +2,14 @@ package util
 
 import (
 	"errors"
+	authConstants "main-server/pkg/constant/auth"
 	middlewareConstants "main-server/pkg/constant/middleware"
 	userModel "main-server/pkg/model/user"
+	"main-server/pkg/service/password"
+	"main-server/pkg/service/throttle"
+	"math"
+	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -50,9 +56,39 @@ func GetContextUserInfo(c *gin.Context) (*userModel.UserIdentityModel, error) {
 	}, nil
 }
 
+/* Получение сведений об устройстве пользователя для создания сессии */
+func GetSessionInfo(c *gin.Context) userModel.SessionInfoModel {
+	return userModel.SessionInfoModel{
+		UserAgent: c.Request.UserAgent(),
+		Ip:        c.ClientIP(),
+	}
+}
+
+/* Получение идентификатора текущей сессии пользователя */
+func GetSessionUuid(c *gin.Context) (string, error) {
+	value, ok := c.Get(middlewareConstants.SESSION_CTX)
+	if !ok {
+		return "", errors.New("Сессии пользователя не найдено")
+	}
+
+	sessionUuid, ok := value.(string)
+	if !ok || sessionUuid == "" {
+		return "", errors.New("Сессии пользователя не найдено")
+	}
+
+	return sessionUuid, nil
+}
+
 /* Структура сообщения об ошибке */
 type ResponseMessage struct {
 	Message string `json:"message" binding:"required"`
+	Code    string `json:"code,omitempty"` // Машиночитаемый код ошибки (при наличии)
+}
+
+/* Структура сообщения об ошибке проверки данных */
+type ValidationResponseMessage struct {
+	ResponseMessage
+	Violations []password.Violation `json:"violations"`
 }
 
 /* Генерация сообщения об ошибке */
@@ -63,3 +99,40 @@ func NewErrorResponse(c *gin.Context, statusCode int, message string) {
 	// Завершение HTTP-запроса с ошибкой
 	c.AbortWithStatusJSON(statusCode, ResponseMessage{Message: message})
 }
+
+/* Генерация сообщения об ошибке с машиночитаемым кодом */
+func NewErrorResponseWithCode(c *gin.Context, statusCode int, code, message string) {
+	logrus.Error(message)
+
+	c.AbortWithStatusJSON(statusCode, ResponseMessage{Message: message, Code: code})
+}
+
+/*
+* Генерация сообщения об ошибке авторизации: для заблокированного пользователя - 403 с кодом блокировки,
+* для пароля, не соответствующего политике, - 400 со списком нарушений,
+* при превышении числа попыток - 429 с заголовком Retry-After
+ */
+func NewAuthErrorResponse(c *gin.Context, statusCode int, err error) {
+	if banErr, ok := err.(*userModel.BanError); ok {
+		NewErrorResponseWithCode(c, http.StatusForbidden, banErr.Code(), banErr.Error())
+		return
+	}
+
+	if policyErr, ok := err.(*password.PolicyError); ok {
+		logrus.Error(policyErr.Error())
+
+		c.AbortWithStatusJSON(http.StatusBadRequest, ValidationResponseMessage{
+			ResponseMessage: ResponseMessage{Message: policyErr.Error(), Code: authConstants.ERROR_CODE_PASSWORD_POLICY},
+			Violations:      policyErr.Violations,
+		})
+		return
+	}
+
+	if lockedErr, ok := err.(*throttle.LockedError); ok {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+		NewErrorResponseWithCode(c, http.StatusTooManyRequests, authConstants.ERROR_CODE_TOO_MANY_ATTEMPTS, lockedErr.Error())
+		return
+	}
+
+	NewErrorResponse(c, statusCode, err.Error())
+}
@@ -2,6 +2,7 @@ package http
 
 type ResponseMessage struct {
 	Message string `json:"message" binding:"required"`
+	Code    string `json:"code,omitempty"`
 }
 
 type ResponseStatus struct {
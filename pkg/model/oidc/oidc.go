@@ -0,0 +1,174 @@
+package oidc
+
+import (
+	userModel "main-server/pkg/model/user"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+/* Модель клиента OpenID Connect (таблица oidc_clients) */
+type ClientModel struct {
+	Id           int            `json:"-" db:"id"`
+	ClientId     string         `json:"client_id" db:"client_id"`
+	SecretHash   *string        `json:"-" db:"secret_hash"`
+	Name         string         `json:"name" db:"name"`
+	RedirectUris pq.StringArray `json:"redirect_uris" db:"redirect_uris"`
+	CreatedAt    time.Time      `json:"created_at" db:"created_at"`
+
+	Scopes            pq.StringArray `json:"scopes" db:"scopes"`         // Области доступа для client_credentials
+	ServiceAccountsId *int           `json:"-" db:"service_accounts_id"` // Сервисный аккаунт, от имени которого действует клиент
+}
+
+/* Модель для регистрации нового клиента */
+type ClientCreateModel struct {
+	Name         string   `json:"name" binding:"required"`
+	RedirectUris []string `json:"redirect_uris"`
+	Confidential bool     `json:"confidential"` // Клиенту выдаётся секрет (серверные приложения)
+
+	// Получение токенов по client_credentials (клиент должен быть конфиденциальным)
+	ServiceAccountUuid *string  `json:"service_account_uuid"`
+	Scopes             []string `json:"scopes"`
+}
+
+/* Модель зарегистрированного клиента (секрет возвращается только при создании) */
+type ClientCreatedModel struct {
+	ClientId     string   `json:"client_id"`
+	ClientSecret *string  `json:"client_secret"`
+	Name         string   `json:"name"`
+	RedirectUris []string `json:"redirect_uris"`
+	Scopes       []string `json:"scopes"`
+}
+
+/* Модель идентификатора клиента */
+type ClientIdModel struct {
+	ClientId string `json:"client_id" binding:"required"`
+}
+
+/* Модель списка клиентов */
+type ClientsModel struct {
+	Clients []ClientModel `json:"clients"`
+}
+
+/* Параметры запроса авторизации (authorization code + PKCE) */
+type AuthorizeModel struct {
+	ResponseType        string  `json:"response_type" form:"response_type" binding:"required"`
+	ClientId            string  `json:"client_id" form:"client_id" binding:"required"`
+	RedirectUri         string  `json:"redirect_uri" form:"redirect_uri" binding:"required"`
+	Scope               string  `json:"scope" form:"scope" binding:"required"`
+	State               *string `json:"state" form:"state"`
+	Nonce               *string `json:"nonce" form:"nonce"`
+	CodeChallenge       string  `json:"code_challenge" form:"code_challenge"`
+	CodeChallengeMethod string  `json:"code_challenge_method" form:"code_challenge_method"`
+}
+
+/* Модель адреса перенаправления клиента с выданным кодом авторизации */
+type AuthorizeRedirectModel struct {
+	RedirectUri string `json:"redirect_uri"`
+}
+
+/* Модель кода авторизации (таблица oidc_codes) */
+type CodeModel struct {
+	Id                  int       `db:"id"`
+	CodeHash            string    `db:"code_hash"`
+	ClientsId           int       `db:"clients_id"`
+	UsersId             int       `db:"users_id"`
+	RedirectUri         string    `db:"redirect_uri"`
+	Scope               string    `db:"scope"`
+	Nonce               *string   `db:"nonce"`
+	CodeChallenge       string    `db:"code_challenge"`
+	CodeChallengeMethod string    `db:"code_challenge_method"`
+	AuthTime            time.Time `db:"auth_time"`
+	ExpiresAt           time.Time `db:"expires_at"`
+}
+
+/* Параметры запроса на получение токенов (application/x-www-form-urlencoded) */
+type TokenRequestModel struct {
+	GrantType    string `form:"grant_type" binding:"required"`
+	Code         string `form:"code"`
+	RedirectUri  string `form:"redirect_uri"`
+	ClientId     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+	CodeVerifier string `form:"code_verifier"`
+	Scope        string `form:"scope"` // Запрашиваемые области доступа (client_credentials)
+}
+
+/* Параметры запроса интроспекции (RFC 7662) или отзыва (RFC 7009) токена */
+type TokenActionRequestModel struct {
+	Token         string `form:"token" binding:"required"`
+	TokenTypeHint string `form:"token_type_hint"` // access_token или refresh_token
+	ClientId      string `form:"client_id"`
+	ClientSecret  string `form:"client_secret"`
+}
+
+/* Ответ на запрос интроспекции токена (для недействительного токена заполняется только active) */
+type IntrospectionModel struct {
+	Active      bool                         `json:"active"`
+	Sub         string                       `json:"sub,omitempty"`
+	Exp         int64                        `json:"exp,omitempty"`
+	Iat         int64                        `json:"iat,omitempty"`
+	Jti         string                       `json:"jti,omitempty"`
+	TokenType   string                       `json:"token_type,omitempty"`
+	ClientId    string                       `json:"client_id,omitempty"`
+	Scope       string                       `json:"scope,omitempty"`
+	AuthType    string                       `json:"auth_type,omitempty"`
+	SessionUuid string                       `json:"session_uuid,omitempty"`
+	Roles       []userModel.RoleContextModel `json:"roles,omitempty"`
+}
+
+/* Ответ на запрос получения токенов */
+type TokenResponseModel struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	IdToken     string `json:"id_token,omitempty"`
+	Scope       string `json:"scope"`
+}
+
+/* Сведения о пользователе (UserInfo) */
+type UserInfoModel struct {
+	Sub        string                       `json:"sub"`
+	Email      string                       `json:"email,omitempty"`
+	Name       string                       `json:"name,omitempty"`
+	FamilyName string                       `json:"family_name,omitempty"`
+	MiddleName string                       `json:"middle_name,omitempty"`
+	Nickname   string                       `json:"nickname,omitempty"`
+	Picture    string                       `json:"picture,omitempty"`
+	Roles      []userModel.RoleContextModel `json:"roles,omitempty"`
+}
+
+/* Документ обнаружения (OpenID Connect Discovery 1.0) */
+type DiscoveryModel struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JwksUri                           string   `json:"jwks_uri"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	RevocationEndpoint                string   `json:"revocation_endpoint"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IdTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+/* Ошибка OAuth 2.0 в формате RFC 6749 */
+type ErrorModel struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *ErrorModel) Error() string {
+	return e.Description
+}
+
+func NewError(code, description string) *ErrorModel {
+	return &ErrorModel{
+		Code:        code,
+		Description: description,
+	}
+}
@@ -7,3 +7,21 @@ type DomainModel struct {
 	Description string  `json:"description" db:"description"`
 	UsersId     *string `json:"users_id" db:"users_id"`
 }
+
+/* Модель для создания нового домена */
+type DomainCreateModel struct {
+	Value       string `json:"value" binding:"required"`
+	Description string `json:"description"`
+}
+
+/* Модель для обновления домена */
+type DomainUpdateModel struct {
+	Uuid        string `json:"uuid" binding:"required"`
+	Value       string `json:"value" binding:"required"`
+	Description string `json:"description"`
+}
+
+/* Модель списка доменов */
+type DomainsModel struct {
+	Domains []DomainModel `json:"domains"`
+}
@@ -7,3 +7,8 @@ type ObjectDbModel struct {
 	ParentId       *int    `json:"parent_id" binding:"required" db:"parent_id"`
 	TypesObjectsId string  `json:"types_objects_id" binding:"required" db:"types_objects_id"`
 }
+
+/* Модель для проверки возможности выполнения действия над объектом */
+type ObjectActionModel struct {
+	Action string `json:"action" binding:"required"`
+}
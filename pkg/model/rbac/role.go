@@ -1,5 +1,7 @@
 package rbac
 
+import "errors"
+
 type RoleModel struct {
 	Id          int     `json:"id" db:"id"`
 	Uuid        string  `json:"uuid" db:"uuid"`
@@ -26,3 +28,49 @@ type RoleValueModel struct {
 type RolesUserModel struct {
 	Roles []string `json:"roles" binding:"required"`
 }
+
+/* Модель для создания новой роли */
+type RoleCreateModel struct {
+	Value       string  `json:"value" binding:"required"`
+	Description string  `json:"description"`
+	DomainUuid  *string `json:"domain_uuid"` // Домен роли (nil - текущий домен)
+}
+
+/* Модель для обновления роли */
+type RoleUpdateModel struct {
+	Uuid        string `json:"uuid" binding:"required"`
+	Value       string `json:"value" binding:"required"`
+	Description string `json:"description"`
+}
+
+/* Модель для работы с идентификатором роли или домена */
+type UuidModel struct {
+	Uuid string `json:"uuid" binding:"required"`
+}
+
+/* Модель списка ролей */
+type RolesModel struct {
+	Roles []RoleModel `json:"roles"`
+}
+
+/* Модель для выдачи или отзыва роли пользователя в домене */
+type AccessRoleModel struct {
+	UserUuid   string  `json:"user_uuid" binding:"required"`
+	RoleUuid   string  `json:"role_uuid" binding:"required"`
+	DomainUuid *string `json:"domain_uuid"` // Домен, в рамках которого выдаётся роль (nil - текущий домен)
+	ObjectUuid *string `json:"object_uuid"` // Объект, в контексте которого действует роль (nil - весь домен)
+}
+
+/* Модель для получения ролей пользователя в домене */
+type AccessUserModel struct {
+	UserUuid   string  `json:"user_uuid" binding:"required"`
+	DomainUuid *string `json:"domain_uuid"` // Домен (nil - текущий домен)
+}
+
+/* Ошибки изменения ролей */
+var (
+	ErrRoleBuiltin = errors.New("Ошибка: встроенная роль системы не может быть изменена или удалена!")
+	ErrRoleExists  = errors.New("Ошибка: роль с данным значением уже существует в рамках домена!")
+	ErrRoleDenied  = errors.New("Ошибка: недостаточно прав для выдачи или отзыва данной роли!")
+	ErrRoleSelf    = errors.New("Ошибка: нельзя выдать роль самому себе!")
+)
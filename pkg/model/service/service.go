@@ -3,3 +3,20 @@ package service
 type TokenVerifyModel struct {
 	Uuid string `json:"uuid"`
 }
+
+/* Открытый ключ подписи токенов в формате JWK (RFC 7517) */
+type JWKModel struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // OKP curve
+	X   string `json:"x,omitempty"`   // OKP public key
+}
+
+/* Набор открытых ключей подписи токенов (JWKS) */
+type JWKSModel struct {
+	Keys []JWKModel `json:"keys"`
+}
@@ -0,0 +1,100 @@
+package user
+
+import (
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+/* Модель API-ключа (таблица u_api_keys) */
+type ApiKeyModel struct {
+	Id         int            `json:"-" db:"id"`
+	Uuid       string         `json:"uuid" db:"uuid"`
+	UsersId    int            `json:"-" db:"users_id"`
+	Name       string         `json:"name" db:"name"`
+	Prefix     string         `json:"prefix" db:"prefix"` // Начало ключа для его распознавания в списке
+	KeyHash    string         `json:"-" db:"key_hash"`
+	Scopes     pq.StringArray `json:"scopes" db:"scopes"`
+	CreatedBy  *int           `json:"-" db:"created_by"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+	ExpiresAt  *time.Time     `json:"expires_at" db:"expires_at"` // NULL - бессрочно
+	LastUsedAt *time.Time     `json:"last_used_at" db:"last_used_at"`
+	RevokedAt  *time.Time     `json:"revoked_at" db:"revoked_at"`
+}
+
+/* Модель списка API-ключей */
+type ApiKeysModel struct {
+	ApiKeys []ApiKeyModel `json:"api_keys"`
+}
+
+/* Модель создания API-ключа */
+type ApiKeyCreateModel struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+/* Модель созданного API-ключа (ключ возвращается только при создании) */
+type ApiKeyCreatedModel struct {
+	ApiKeyModel
+	Key string `json:"key"`
+}
+
+/* Модель идентификатора API-ключа */
+type ApiKeyUuidModel struct {
+	Uuid string `json:"uuid" binding:"required"`
+}
+
+/* Сведения о владельце API-ключа для контекста запроса */
+type ApiKeyIdentityModel struct {
+	UsersId    int
+	UsersUuid  string
+	ApiKeyUuid string
+	Scopes     []string
+}
+
+/* Модель сервисного аккаунта (таблица u_service_accounts) */
+type ServiceAccountModel struct {
+	Id          int       `json:"-" db:"id"`
+	Uuid        string    `json:"uuid" db:"uuid"`
+	UsersId     int       `json:"-" db:"users_id"`
+	UsersUuid   string    `json:"users_uuid" db:"users_uuid"` // UUID пользователя для назначения ролей
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+/* Модель списка сервисных аккаунтов */
+type ServiceAccountsModel struct {
+	ServiceAccounts []ServiceAccountModel `json:"service_accounts"`
+}
+
+/* Модель создания сервисного аккаунта */
+type ServiceAccountCreateModel struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+/* Модель идентификатора сервисного аккаунта */
+type ServiceAccountUuidModel struct {
+	Uuid string `json:"uuid" binding:"required"`
+}
+
+/* Модель создания API-ключа сервисного аккаунта */
+type ServiceAccountApiKeyCreateModel struct {
+	ServiceAccountUuid string `json:"service_account_uuid" binding:"required"`
+	ApiKeyCreateModel
+}
+
+/* Модель отзыва API-ключа сервисного аккаунта */
+type ServiceAccountApiKeyModel struct {
+	ServiceAccountUuid string `json:"service_account_uuid" binding:"required"`
+	Uuid               string `json:"uuid" binding:"required"`
+}
+
+var (
+	ErrApiKeyInvalid         = errors.New("Недействительный API-ключ!")
+	ErrApiKeyNotFound        = errors.New("Ошибка: API-ключа с данным идентификатором не существует!")
+	ErrServiceAccountMissing = errors.New("Ошибка: сервисного аккаунта с данным идентификатором не существует!")
+)
@@ -1,10 +1,35 @@
 package user
 
-type GoogleOAuth2Code struct {
-	Code string `json:"code" binding:"required"`
-}
+import "time"
 
 type ResetPasswordModel struct {
 	Token    string `json:"token" binding:"required"`
 	Password string `json:"password" binding:"required"`
 }
+
+/* Модель запроса письма для входа без пароля */
+type PasswordlessRequestModel struct {
+	Email string `json:"email" binding:"required"`
+}
+
+/* Модель входа по ссылке из письма */
+type PasswordlessLinkModel struct {
+	Token string `json:"token" binding:"required"`
+}
+
+/* Модель входа по коду из письма */
+type PasswordlessCodeModel struct {
+	Email string `json:"email" binding:"required"`
+	Code  string `json:"code" binding:"required"`
+}
+
+/* Модель одноразовой ссылки и кода для входа без пароля (таблица u_login_codes) */
+type LoginCodeModel struct {
+	Id        int       `json:"id" db:"id"`
+	UsersId   int       `json:"users_id" db:"users_id"`
+	TokenHash string    `json:"token_hash" db:"token_hash"`
+	CodeHash  string    `json:"code_hash" db:"code_hash"`
+	Attempts  int       `json:"attempts" db:"attempts"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+}
@@ -0,0 +1,68 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	authConstants "main-server/pkg/constant/auth"
+)
+
+/* Модель блокировки пользователя (таблица u_bans) */
+type BanModel struct {
+	Id        int        `json:"-" db:"id"`
+	Uuid      string     `json:"uuid" db:"uuid"`
+	UsersId   int        `json:"-" db:"users_id"`
+	Kind      string     `json:"kind" db:"kind"` // ban или suspend
+	Reason    string     `json:"reason" db:"reason"`
+	BannedBy  *string    `json:"banned_by" db:"banned_by"` // UUID администратора
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at" db:"expires_at"` // NULL - бессрочно
+	LiftedAt  *time.Time `json:"lifted_at" db:"lifted_at"`   // Дата снятия (вручную или по истечении срока)
+	LiftedBy  *string    `json:"lifted_by" db:"lifted_by"`   // UUID администратора, снявшего блокировку
+	IsActive  bool       `json:"is_active" db:"is_active"`
+}
+
+/* Модель истории блокировок пользователя */
+type BansModel struct {
+	Bans []BanModel `json:"bans"`
+}
+
+/* Модель создания блокировки пользователя */
+type BanCreateModel struct {
+	UsersUuid string     `json:"users_uuid" binding:"required"`
+	Kind      string     `json:"kind" binding:"required"`
+	Reason    string     `json:"reason" binding:"required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+/* Модель идентификатора блокируемого пользователя */
+type BanUserModel struct {
+	UsersUuid string `json:"users_uuid" binding:"required"`
+}
+
+/* Ошибка доступа заблокированного пользователя */
+type BanError struct {
+	Ban *BanModel
+}
+
+func (e *BanError) Error() string {
+	message := "Пользователь заблокирован"
+	if e.Ban.Kind == authConstants.BAN_KIND_SUSPEND {
+		message = "Пользователь временно отстранён"
+	}
+
+	if e.Ban.ExpiresAt != nil {
+		message += fmt.Sprintf(" до %s", e.Ban.ExpiresAt.Format("02.01.2006 15:04"))
+	}
+
+	return fmt.Sprintf("%s! Причина: %s", message, e.Ban.Reason)
+}
+
+/* Код ошибки, по которому клиент отличает блокировку от прочих ошибок авторизации */
+func (e *BanError) Code() string {
+	if e.Ban.Kind == authConstants.BAN_KIND_SUSPEND {
+		return authConstants.ERROR_CODE_USER_SUSPENDED
+	}
+
+	return authConstants.ERROR_CODE_USER_BANNED
+}
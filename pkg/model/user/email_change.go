@@ -0,0 +1,35 @@
+package user
+
+import (
+	"errors"
+	"time"
+)
+
+/* Модель запроса на смену email-адреса (таблица u_email_changes) */
+type EmailChangeModel struct {
+	Id          int        `json:"-" db:"id"`
+	Uuid        string     `json:"uuid" db:"uuid"`
+	UsersId     int        `json:"-" db:"users_id"`
+	OldEmail    string     `json:"old_email" db:"old_email"`
+	NewEmail    string     `json:"new_email" db:"new_email"`
+	ConfirmLink string     `json:"-" db:"confirm_link"` // Ссылка подтверждения (отправляется на новый адрес)
+	CancelLink  string     `json:"-" db:"cancel_link"`  // Ссылка отмены (отправляется на старый адрес)
+	SessionUuid *string    `json:"-" db:"session_uuid"` // Сессия, из которой была запрошена смена адреса
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt   time.Time  `json:"expires_at" db:"expires_at"`
+	ConfirmedAt *time.Time `json:"confirmed_at" db:"confirmed_at"`
+	CancelledAt *time.Time `json:"cancelled_at" db:"cancelled_at"`
+}
+
+/* Модель запроса на смену email-адреса пользователем */
+type EmailChangeRequestModel struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password"` // Текущий пароль (обязателен, если у пользователя есть локальный пароль)
+}
+
+var (
+	ErrEmailChangeLinkNotFound = errors.New("Ссылка для смены email-адреса не найдена!")
+	ErrEmailChangeLinkExpired  = errors.New("Срок действия ссылки для смены email-адреса истёк! Повторите запрос")
+	ErrEmailChangeLinkUsed     = errors.New("Запрос на смену email-адреса уже подтверждён или отменён!")
+	ErrEmailTaken              = errors.New("Пользователь с данным email-адресом уже существует!")
+)
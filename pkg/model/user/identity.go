@@ -0,0 +1,25 @@
+package user
+
+import "time"
+
+/* Модель внешней учётной записи пользователя (таблица u_identities) */
+type IdentityModel struct {
+	Id          int       `json:"-" db:"id"`
+	Uuid        string    `json:"uuid" db:"uuid"`
+	UsersId     int       `json:"-" db:"users_id"`
+	AuthTypesId int       `json:"-" db:"auth_types_id"`
+	Provider    string    `json:"provider" db:"provider"` // Значение типа авторизации
+	Subject     string    `json:"subject" db:"subject"`   // Идентификатор пользователя у провайдера
+	Email       *string   `json:"email" db:"email"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+/* Модель списка внешних учётных записей пользователя */
+type IdentitiesModel struct {
+	Identities []IdentityModel `json:"identities"`
+}
+
+/* Модель идентификатора внешней учётной записи */
+type IdentityUuidModel struct {
+	Uuid string `json:"uuid" binding:"required"`
+}
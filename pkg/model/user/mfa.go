@@ -0,0 +1,82 @@
+package user
+
+import (
+	"errors"
+	"time"
+)
+
+/* Модель секрета TOTP пользователя (таблица u_mfa_totp) */
+type MfaTotpModel struct {
+	Id           int        `json:"id" db:"id"`
+	UsersId      int        `json:"users_id" db:"users_id"`
+	Secret       string     `json:"-" db:"secret"`
+	IsEnabled    bool       `json:"is_enabled" db:"is_enabled"`
+	LastUsedStep int64      `json:"-" db:"last_used_step"` // Последний использованный интервал (защита от повторного ввода кода)
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	EnabledAt    *time.Time `json:"enabled_at" db:"enabled_at"`
+}
+
+/* Модель состояния двухфакторной аутентификации пользователя */
+type MfaStatusModel struct {
+	Enabled           bool `json:"enabled"`
+	Required          bool `json:"required"` // Второй фактор обязателен для одной из ролей пользователя
+	RecoveryCodesLeft int  `json:"recovery_codes_left"`
+}
+
+/* Модель данных для подключения приложения-аутентификатора */
+type MfaTotpSetupModel struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"` // URI otpauth:// для QR-кода
+}
+
+/* Модель кодов восстановления (показываются пользователю один раз) */
+type MfaRecoveryCodesModel struct {
+	Codes []string `json:"codes"`
+}
+
+/* Модель одноразового кода (TOTP или код восстановления) */
+type MfaCodeModel struct {
+	Code string `json:"code" binding:"required"`
+}
+
+/* Модель ответа на вход, ожидающий подтверждения вторым фактором */
+type MfaPendingModel struct {
+	MfaRequired    bool     `json:"mfa_required"`
+	MfaToken       string   `json:"mfa_token"`
+	Methods        []string `json:"methods"`
+	EnrollRequired bool     `json:"enroll_required"` // Второй фактор обязателен, но ещё не подключён
+}
+
+/* Модель токена ожидания второго фактора */
+type MfaTokenModel struct {
+	MfaToken string `json:"mfa_token" binding:"required"`
+}
+
+/* Модель подтверждения входа вторым фактором */
+type MfaVerifyModel struct {
+	MfaToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+/* Модель ответа на подключение второго фактора во время входа */
+type MfaEnrolledModel struct {
+	AccessToken   string   `json:"access_token"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+/* Данные, полученные из токена ожидания второго фактора */
+type MfaTokenOutputParse struct {
+	UsersId   int    `json:"users_id"`
+	UsersUuid string `json:"users_uuid"`
+	TokenId   string `json:"token_id"` // Идентификатор токена (jti), по которому хранится незавершённый вход
+}
+
+/* Модель незавершённого входа (таблица u_mfa_pending): способ первого входа и токены внешнего провайдера */
+type MfaPendingLoginModel struct {
+	AuthType   string  `db:"auth_type"`
+	AccessApi  *string `db:"access_api"`
+	RefreshApi *string `db:"refresh_api"`
+}
+
+/* Ошибка неверного кода второго фактора (учитывается защитой от перебора) */
+var ErrInvalidMfaCode = errors.New("Ошибка: неверный код подтверждения")
@@ -1,10 +1,53 @@
 package user
 
+import "time"
+
+/* Модель сессии пользователя (таблица u_tokens, одна строка на устройство) */
 type TokenModel struct {
-	Id           int    `json:"id" db:"id"`
-	UsersId      int    `json:"users_id" db:"users_id"`
-	AccessToken  string `json:"access_token" db:"access_token"`
-	RefreshToken string `json:"refresh_token" db:"refresh_token"`
+	Id           int       `json:"id" db:"id"`
+	Uuid         string    `json:"uuid" db:"uuid"`
+	UsersId      int       `json:"users_id" db:"users_id"`
+	AccessToken  string    `json:"access_token" db:"access_token"`
+	RefreshToken string    `json:"refresh_token" db:"refresh_token"`
+	UserAgent    string    `json:"user_agent" db:"user_agent"`
+	Ip           string    `json:"ip" db:"ip"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	RefreshedAt  time.Time `json:"refreshed_at" db:"refreshed_at"`
+}
+
+/* Модель использованного токена обновления (таблица u_tokens_consumed) */
+type TokenConsumedModel struct {
+	Id           int       `json:"id" db:"id"`
+	SessionsUuid string    `json:"sessions_uuid" db:"sessions_uuid"`
+	UsersId      int       `json:"users_id" db:"users_id"`
+	TokenHash    string    `json:"token_hash" db:"token_hash"`
+	ConsumedAt   time.Time `json:"consumed_at" db:"consumed_at"`
+}
+
+/* Сведения об устройстве, с которого выполняется вход */
+type SessionInfoModel struct {
+	UserAgent string `json:"user_agent"`
+	Ip        string `json:"ip"`
+}
+
+/* Модель сессии пользователя для вывода */
+type SessionModel struct {
+	Uuid        string    `json:"uuid" db:"uuid"`
+	UserAgent   string    `json:"user_agent" db:"user_agent"`
+	Ip          string    `json:"ip" db:"ip"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	RefreshedAt time.Time `json:"refreshed_at" db:"refreshed_at"`
+	IsCurrent   bool      `json:"is_current" db:"-"`
+}
+
+/* Модель списка сессий пользователя */
+type SessionsModel struct {
+	Sessions []SessionModel `json:"sessions"`
+}
+
+/* Модель для работы с идентификатором сессии */
+type SessionUuidModel struct {
+	Uuid string `json:"uuid" binding:"required"`
 }
 
 type TokenDataModel struct {
@@ -20,10 +63,16 @@ type TokenLogoutDataModel struct {
 }
 
 type TokenOutputParse struct {
-	UsersId   int           `json:"users_id"`
-	UsersUuid string        `json:"uuid"`
-	AuthType  AuthTypeModel `json:"auth_types"`
-	TokenApi  *string       `json:"token_api"`
+	UsersId     int           `json:"users_id"`
+	UsersUuid   string        `json:"uuid"`
+	SessionUuid string        `json:"session_uuid"`
+	AuthType    AuthTypeModel `json:"auth_types"`
+	TokenApi    *string       `json:"token_api"`
+	ClientId    string        `json:"client_id"` // Клиент OAuth2 (только для токенов client_credentials)
+	Scopes      []string      `json:"scopes"`
+	TokenId     string        `json:"token_id"` // Идентификатор токена (jti)
+	IssuedAt    int64         `json:"issued_at"`
+	ExpiresAt   int64         `json:"expires_at"`
 }
 
 type TokenOutputParseUU struct {
@@ -1,5 +1,10 @@
 package user
 
+import (
+	"errors"
+	"time"
+)
+
 type UserIdentityModel struct {
 	UserId     int
 	UserUuid   string
@@ -20,12 +25,14 @@ type UserModel struct {
 	Password string `json:"password" binding:"required" db:"password"`
 }
 
-/* Model for registration via Google OAuth 2 */
+/* Model for registration via external OAuth 2 provider */
 type UserRegisterOAuth2Model struct {
-	Email      string `json:"email" binding:"required"`
-	FamilyName string `json:"family_name" binding:"required"`
-	GivenName  string `json:"given_name" binding:"required"`
-	Name       string `json:"name" binding:"required"`
+	Subject       string `json:"sub"` // Идентификатор пользователя у внешнего провайдера
+	Email         string `json:"email" binding:"required"`
+	EmailVerified bool   `json:"email_verified"`
+	FamilyName    string `json:"family_name" binding:"required"`
+	GivenName     string `json:"given_name" binding:"required"`
+	Name          string `json:"name" binding:"required"`
 }
 
 /* A model for working with data during user authorization (JSON parsing, etc.) */
@@ -34,23 +41,44 @@ type UserSignInModel struct {
 	Password string `json:"password" binding:"required"`
 }
 
-/* A model for working with data during user authorization via Google OAuth 2 */
+/* A model for working with data during user authorization via external OAuth 2 provider */
 type UserLoginOAuth2Model struct {
 	Code string `json:"code" binding:"required"`
 }
 
+/* A model for the external OAuth 2 provider authorization page address */
+type OAuth2UrlModel struct {
+	Url string `json:"url"`
+}
+
 /* A model representing user authorization data */
 type UserAuthDataModel struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken  string           `json:"access_token"`
+	RefreshToken string           `json:"refresh_token"`
+	Mfa          *MfaPendingModel `json:"-"` // Заполняется вместо пары токенов, если требуется второй фактор
 }
 
 /* A model representing the user's activation data */
 type UserActivateModel struct {
-	ActivationLink string `json:"activation_link" db:"activation_link"`
-	IsActivated    bool   `json:"is_activated" db:"is_activated"`
+	UsersId        int        `json:"users_id" db:"users_id"`
+	ActivationLink string     `json:"activation_link" db:"activation_link"`
+	IsActivated    bool       `json:"is_activated" db:"is_activated"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt      *time.Time `json:"expires_at" db:"expires_at"`
+	ActivatedAt    *time.Time `json:"activated_at" db:"activated_at"`
 }
 
+/* Errors returned when following an activation link */
+var (
+	ErrActivationLinkNotFound = errors.New("Ссылка для подтверждения аккаунта не найдена!")
+	ErrActivationLinkExpired  = errors.New("Срок действия ссылки для подтверждения аккаунта истёк! Запросите новое письмо")
+	ErrActivationLinkUsed     = errors.New("Аккаунт уже подтверждён по данной ссылке!")
+	ErrAccountNotActivated    = errors.New("Аккаунт не подтверждён! Перейдите по ссылке из письма или запросите новое письмо")
+)
+
+/* Error returned for any sign-in failure caused by wrong credentials (does not reveal whether the account exists) */
+var ErrInvalidCredentials = errors.New("Неверный email-адрес или пароль! Повторите попытку")
+
 /* A model for representing authorization types */
 type AuthTypeModel struct {
 	Id    int    `json:"id" db:"id"`
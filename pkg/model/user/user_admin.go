@@ -0,0 +1,52 @@
+package user
+
+import (
+	"errors"
+
+	"main-server/pkg/model/base"
+
+	"github.com/lib/pq"
+)
+
+/* Параметры поиска пользователей (query-параметры). Для постраничного вывода используется либо cursor, либо offset */
+type UserSearchModel struct {
+	Search    string `form:"search"`    // Подстрока email-адреса, имени, фамилии или никнейма
+	Role      string `form:"role"`      // Роль в текущем домене
+	AuthType  string `form:"auth_type"` // Привязанный тип авторизации
+	Activated *bool  `form:"activated"`
+	Banned    *bool  `form:"banned"`
+	Limit     int    `form:"limit"`
+	Offset    int    `form:"offset"`
+	Cursor    string `form:"cursor"` // Значение next_cursor из предыдущего ответа
+}
+
+/* Модель пользователя в результатах поиска */
+type UserListItemModel struct {
+	Id          int             `json:"-" db:"id"`
+	Uuid        string          `json:"uuid" db:"uuid"`
+	Email       string          `json:"email" db:"email"`
+	Data        UserDataDbModel `json:"data" db:"data"`
+	IsActivated bool            `json:"is_activated" db:"is_activated"`
+	IsBanned    bool            `json:"is_banned" db:"is_banned"`
+	AuthTypes   pq.StringArray  `json:"auth_types" db:"auth_types"`
+}
+
+/* Модель страницы результатов поиска пользователей (count - общее количество найденных пользователей) */
+type UsersListModel struct {
+	base.LimitModel
+	Users      []UserListItemModel `json:"users"`
+	Offset     int                 `json:"offset"`
+	NextCursor *string             `json:"next_cursor"` // nil - страница последняя
+}
+
+/* Модель полных сведений о пользователе для администратора */
+type UserDetailModel struct {
+	UserListItemModel
+	Ban        *BanModel          `json:"ban"` // Действующая блокировка
+	Roles      []RoleContextModel `json:"roles"`
+	Identities []IdentityModel    `json:"identities"`
+	Sessions   []SessionModel     `json:"sessions"`
+}
+
+/* Ошибка некорректного курсора постраничного вывода */
+var ErrInvalidCursor = errors.New("Ошибка: некорректный курсор")
@@ -28,5 +28,5 @@ type UserProfileUpdateDataModel struct {
 	Nickname   string  `json:"nickname" binding:"required"`
 	Patronymic string  `json:"patronymic"`
 	Position   string  `json:"position"`
-	Password   *string `json:"password"`
+	Password   *string `json:"password,omitempty"`
 }
@@ -0,0 +1,55 @@
+package user
+
+import (
+	"encoding/json"
+	"time"
+)
+
+/* Модель ключа доступа пользователя (таблица u_webauthn_credentials) */
+type WebAuthnCredentialModel struct {
+	Id              int        `json:"-" db:"id"`
+	Uuid            string     `json:"uuid" db:"uuid"`
+	UsersId         int        `json:"-" db:"users_id"`
+	CredentialId    []byte     `json:"-" db:"credential_id"`
+	PublicKey       []byte     `json:"-" db:"public_key"`
+	AttestationType string     `json:"attestation_type" db:"attestation_type"`
+	Aaguid          []byte     `json:"-" db:"aaguid"`
+	SignCount       int64      `json:"sign_count" db:"sign_count"` // Счётчик подписей аутентификатора (защита от клонирования ключа)
+	Name            string     `json:"name" db:"name"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt      *time.Time `json:"last_used_at" db:"last_used_at"`
+}
+
+/* Модель списка ключей доступа пользователя */
+type WebAuthnCredentialsModel struct {
+	Credentials []WebAuthnCredentialModel `json:"credentials"`
+}
+
+/* Модель идентификатора ключа доступа */
+type WebAuthnCredentialUuidModel struct {
+	Uuid string `json:"uuid" binding:"required"`
+}
+
+/* Модель начала церемонии: параметры для navigator.credentials и идентификатор церемонии */
+type WebAuthnBeginModel struct {
+	Session string      `json:"session"`
+	Options interface{} `json:"options"`
+}
+
+/* Модель завершения регистрации ключа доступа */
+type WebAuthnRegisterFinishModel struct {
+	Session    string          `json:"session" binding:"required"`
+	Name       string          `json:"name"`
+	Credential json.RawMessage `json:"credential" binding:"required"` // Ответ navigator.credentials.create()
+}
+
+/* Модель начала входа по ключу доступа */
+type WebAuthnLoginBeginModel struct {
+	Email string `json:"email" binding:"required"`
+}
+
+/* Модель завершения входа по ключу доступа */
+type WebAuthnLoginFinishModel struct {
+	Session    string          `json:"session" binding:"required"`
+	Credential json.RawMessage `json:"credential" binding:"required"` // Ответ navigator.credentials.get()
+}
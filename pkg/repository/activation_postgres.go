@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	config "main-server/config"
+	authConstants "main-server/pkg/constant/auth"
+	tableConstants "main-server/pkg/constant/table"
+	"main-server/pkg/model/email"
+	userModel "main-server/pkg/model/user"
+	smtpService "main-server/pkg/service/smtp"
+
+	"github.com/jmoiron/sqlx"
+	uuid "github.com/satori/go.uuid"
+	"github.com/spf13/viper"
+)
+
+/* Создание ссылки для подтверждения аккаунта */
+func createActivationLink(tx *sql.Tx, usersId int) (string, error) {
+	link := uuid.NewV4().String()
+	currentDate := time.Now()
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (users_id, is_activated, activation_link, created_at, expires_at) VALUES ($1, false, $2, $3, $4)
+	`, tableConstants.U_ACTIVATIONS)
+	if _, err := tx.Exec(query, usersId, link, currentDate, currentDate.Add(config.AppActivationPolicy.LinkTTL)); err != nil {
+		return "", err
+	}
+
+	return link, nil
+}
+
+/* Отправка письма со ссылкой для подтверждения аккаунта */
+func sendActivationEmail(userEmail, link string) error {
+	return sendLinkEmail(userEmail, "Подтверждение аккаунта \"Rental housing\"", linkEmail{
+		Heading: "Подтверждение E-mail",
+		Text: `<br><text>Вы получили это письмо, так как Ваш почтовый адрес был указан в приложении "Rental housing".</text> 
+			</br><text>Чтобы подтвердить Вашу почту перейдите по ссылке: </text></br>`,
+		Button: "Подтвердить E-mail",
+		Link:   viper.GetString("api_url") + "/auth/activate/" + link,
+		Note: fmt.Sprintf(`<text>Ссылка действует %d ч. и может быть использована только один раз.</text>
+			</br><text>Если Вы не проходили процедуру регистрации в приложении "Rental housing", то не отвечайте на данное сообщение.</text>`,
+			int(config.AppActivationPolicy.LinkTTL.Hours())),
+	})
+}
+
+/* Содержимое письма со ссылкой-кнопкой (подтверждение аккаунта, смена email-адреса) */
+type linkEmail struct {
+	Heading string
+	Text    string
+	Button  string
+	Link    string
+	Note    string
+}
+
+/* Отправка письма со ссылкой-кнопкой */
+func sendLinkEmail(userEmail, subject string, data linkEmail) error {
+	return smtpService.SendMessage(userEmail, smtpService.BuildMessage(email.Mail{
+		Sender:  viper.GetString("smtp.email"),
+		To:      []string{userEmail},
+		Subject: subject,
+		Body: fmt.Sprintf(`<html>
+		<head>
+			<meta charset="utf-8" />
+			<title></title>
+		</head>
+		<style>
+			body {background-color: #FEFEF9;}
+			h2   {color: #181511;}
+			button {
+				color: rgb(0, 0, 0);
+				outline: none;
+				border: none;
+				border-radius: 30px;
+				background-color: #B19472;
+				padding: 8px 16px;
+				margin-top: 16px;
+				cursor: pointer;
+			}
+		</style>
+		<body>
+			<h2>%s</h2>
+			%s
+			<a href="%s">
+			<button>%s</button>
+			</a>
+			<br><br><br>
+			%s
+		</body>
+	</html>`, data.Heading, data.Text, data.Link, data.Button, data.Note),
+	}))
+}
+
+/*
+* Повторная отправка письма для подтверждения аккаунта. Новая ссылка аннулирует предыдущую.
+* Ответ не зависит от того, существует ли пользователь с данным email-адресом
+ */
+func (r *AuthPostgres) ResendActivation(userEmail string) (bool, error) {
+	user, err := r.userPostgres.Get("email", userEmail, false)
+	if err != nil {
+		return false, err
+	}
+
+	if user == nil {
+		return true, nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	var activation userModel.UserActivateModel
+	query := fmt.Sprintf(`
+		SELECT users_id, activation_link, is_activated, created_at, expires_at, activated_at
+		FROM %s WHERE users_id = $1 FOR UPDATE
+	`, tableConstants.U_ACTIVATIONS)
+	err = tx.QueryRow(query, user.Id).Scan(
+		&activation.UsersId, &activation.ActivationLink, &activation.IsActivated,
+		&activation.CreatedAt, &activation.ExpiresAt, &activation.ActivatedAt,
+	)
+	if err == sql.ErrNoRows || (err == nil && activation.IsActivated) {
+		tx.Rollback()
+		return true, nil
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	// Ограничение частоты отправки писем одному пользователю
+	currentDate := time.Now()
+	if currentDate.Sub(activation.CreatedAt) < authConstants.ACTIVATION_RESEND_INTERVAL {
+		tx.Rollback()
+		return false, errors.New("Ошибка: письмо уже было отправлено, повторите попытку через минуту")
+	}
+
+	link := uuid.NewV4().String()
+	query = fmt.Sprintf(`
+		UPDATE %s SET activation_link = $1, created_at = $2, expires_at = $3 WHERE users_id = $4
+	`, tableConstants.U_ACTIVATIONS)
+	if _, err = tx.Exec(query, link, currentDate, currentDate.Add(config.AppActivationPolicy.LinkTTL), user.Id); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err = sendActivationEmail(user.Email, link); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* Проверка, подтверждён ли аккаунт пользователя (аккаунты без записи о подтверждении считаются подтверждёнными) */
+func (r *AuthPostgres) IsActivated(userId int) (bool, error) {
+	return isActivated(r.db, userId)
+}
+
+func isActivated(db *sqlx.DB, userId int) (bool, error) {
+	var activated bool
+	query := fmt.Sprintf("SELECT is_activated FROM %s WHERE users_id = $1 LIMIT 1", tableConstants.U_ACTIVATIONS)
+	if err := db.Get(&activated, query, userId); err != nil {
+		if err == sql.ErrNoRows {
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	return activated, nil
+}
+
+/* Запрет входа в систему для неподтверждённого аккаунта (политика block) */
+func checkActivation(db *sqlx.DB, userId int) error {
+	if config.AppActivationPolicy.Mode != authConstants.ACTIVATION_POLICY_BLOCK {
+		return nil
+	}
+
+	activated, err := isActivated(db, userId)
+	if err != nil {
+		return err
+	}
+
+	if !activated {
+		return userModel.ErrAccountNotActivated
+	}
+
+	return nil
+}
+
+/* Подтверждение аккаунта без перехода по ссылке (например, после входа по письму) */
+func activateUser(tx *sql.Tx, userId int) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET is_activated = true, activated_at = NOW() WHERE users_id = $1 AND is_activated = false
+	`, tableConstants.U_ACTIVATIONS)
+	_, err := tx.Exec(query, userId)
+
+	return err
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	authConstants "main-server/pkg/constant/auth"
+	scopeConstants "main-server/pkg/constant/scope"
+	tableConstants "main-server/pkg/constant/table"
+	userModel "main-server/pkg/model/user"
+	"main-server/pkg/util"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type ApiKeyPostgres struct {
+	db *sqlx.DB
+}
+
+/* Создание нового экземпляра структуры ApiKeyPostgres */
+func NewApiKeyPostgres(db *sqlx.DB) *ApiKeyPostgres {
+	return &ApiKeyPostgres{db: db}
+}
+
+/* Создание API-ключа пользователя. Ключ возвращается один раз, в базе данных хранится только его хэш */
+func (r *ApiKeyPostgres) Create(actor *userModel.UserIdentityModel, userId int, data userModel.ApiKeyCreateModel) (*userModel.ApiKeyCreatedModel, error) {
+	name := strings.TrimSpace(data.Name)
+	if name == "" {
+		return nil, errors.New("Ошибка: название API-ключа не может быть пустым")
+	}
+
+	scopes, err := normalizeScopes(data.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	currentDate := time.Now()
+	if data.ExpiresAt != nil && !data.ExpiresAt.After(currentDate) {
+		return nil, errors.New("Ошибка: срок действия API-ключа должен быть в будущем")
+	}
+
+	secret, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	key := authConstants.API_KEY_PREFIX + secret
+	createdBy := actor.UserId
+
+	apiKey := userModel.ApiKeyModel{
+		Uuid:      uuid.NewV4().String(),
+		UsersId:   userId,
+		Name:      name,
+		Prefix:    key[:authConstants.API_KEY_PREFIX_LENGTH],
+		KeyHash:   hashToken(key),
+		Scopes:    scopes,
+		CreatedBy: &createdBy,
+		CreatedAt: currentDate,
+		ExpiresAt: data.ExpiresAt,
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (uuid, users_id, name, prefix, key_hash, scopes, created_by, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id
+	`, tableConstants.U_API_KEYS)
+	err = r.db.QueryRow(query,
+		apiKey.Uuid, apiKey.UsersId, apiKey.Name, apiKey.Prefix, apiKey.KeyHash,
+		apiKey.Scopes, apiKey.CreatedBy, apiKey.CreatedAt, apiKey.ExpiresAt,
+	).Scan(&apiKey.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userModel.ApiKeyCreatedModel{
+		ApiKeyModel: apiKey,
+		Key:         key,
+	}, nil
+}
+
+/* Получение всех API-ключей пользователя (включая отозванные) */
+func (r *ApiKeyPostgres) GetAll(userId int) (*userModel.ApiKeysModel, error) {
+	var apiKeys []userModel.ApiKeyModel
+	query := fmt.Sprintf("SELECT * FROM %s WHERE users_id = $1 ORDER BY created_at DESC", tableConstants.U_API_KEYS)
+
+	if err := r.db.Select(&apiKeys, query, userId); err != nil {
+		return nil, err
+	}
+
+	return &userModel.ApiKeysModel{
+		ApiKeys: apiKeys,
+	}, nil
+}
+
+/* Отзыв API-ключа пользователя */
+func (r *ApiKeyPostgres) Revoke(userId int, apiKeyUuid string) (bool, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s SET revoked_at = NOW() WHERE users_id = $1 AND uuid = $2 AND revoked_at IS NULL
+	`, tableConstants.U_API_KEYS)
+
+	result, err := r.db.Exec(query, userId, apiKeyUuid)
+	if err != nil {
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if count <= 0 {
+		return false, userModel.ErrApiKeyNotFound
+	}
+
+	return true, nil
+}
+
+/* Проверка API-ключа и получение сведений о его владельце */
+func (r *ApiKeyPostgres) Authenticate(key string) (*userModel.ApiKeyIdentityModel, error) {
+	if !strings.HasPrefix(key, authConstants.API_KEY_PREFIX) {
+		return nil, userModel.ErrApiKeyInvalid
+	}
+
+	var apiKey userModel.ApiKeyModel
+	var usersUuid string
+	query := fmt.Sprintf(`
+		SELECT tk.id, tk.uuid, tk.users_id, tk.scopes, tk.expires_at, tk.last_used_at, tk.revoked_at, tu.uuid
+		FROM %s tk INNER JOIN %s tu ON tu.id = tk.users_id
+		WHERE tk.key_hash = $1 LIMIT 1
+	`, tableConstants.U_API_KEYS, tableConstants.U_USERS)
+	err := r.db.QueryRow(query, hashToken(key)).Scan(
+		&apiKey.Id, &apiKey.Uuid, &apiKey.UsersId, &apiKey.Scopes,
+		&apiKey.ExpiresAt, &apiKey.LastUsedAt, &apiKey.RevokedAt, &usersUuid,
+	)
+	if err == sql.ErrNoRows {
+		return nil, userModel.ErrApiKeyInvalid
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	currentDate := time.Now()
+	if apiKey.RevokedAt != nil || (apiKey.ExpiresAt != nil && currentDate.After(*apiKey.ExpiresAt)) {
+		return nil, userModel.ErrApiKeyInvalid
+	}
+
+	// Дата последнего использования обновляется не чаще заданного интервала
+	if apiKey.LastUsedAt == nil || currentDate.Sub(*apiKey.LastUsedAt) >= authConstants.API_KEY_LAST_USED_INTERVAL {
+		query = fmt.Sprintf("UPDATE %s SET last_used_at = $1 WHERE id = $2", tableConstants.U_API_KEYS)
+		if _, err := r.db.Exec(query, currentDate, apiKey.Id); err != nil {
+			logrus.Errorf("failed to update last use of api key %s: %s", apiKey.Uuid, err.Error())
+		}
+	}
+
+	return &userModel.ApiKeyIdentityModel{
+		UsersId:    apiKey.UsersId,
+		UsersUuid:  usersUuid,
+		ApiKeyUuid: apiKey.Uuid,
+		Scopes:     apiKey.Scopes,
+	}, nil
+}
+
+/* Проверка и удаление повторов в списке областей доступа API-ключа или клиента */
+func normalizeScopes(scopes []string) (pq.StringArray, error) {
+	result := pq.StringArray{}
+
+	for _, item := range scopes {
+		if exists, _ := util.InArray(item, scopeConstants.SCOPES); !exists {
+			return nil, fmt.Errorf("Ошибка: неизвестная область доступа %s", item)
+		}
+
+		if exists, _ := util.InArray(item, []string(result)); !exists {
+			result = append(result, item)
+		}
+	}
+
+	if len(result) <= 0 {
+		return nil, errors.New("Ошибка: необходимо выдать хотя бы одну область доступа")
+	}
+
+	return result, nil
+}
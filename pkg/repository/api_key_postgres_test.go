@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	authConstants "main-server/pkg/constant/auth"
+	scopeConstants "main-server/pkg/constant/scope"
+	userModel "main-server/pkg/model/user"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+)
+
+func TestNormalizeScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		scopes  []string
+		want    pq.StringArray
+		wantErr bool
+	}{
+		{
+			name:   "known scopes",
+			scopes: []string{scopeConstants.SERVICE_VERIFY, scopeConstants.PROFILE},
+			want:   pq.StringArray{scopeConstants.SERVICE_VERIFY, scopeConstants.PROFILE},
+		},
+		{
+			name:   "duplicates",
+			scopes: []string{scopeConstants.PROFILE, scopeConstants.PROFILE},
+			want:   pq.StringArray{scopeConstants.PROFILE},
+		},
+		{
+			name:    "unknown scope",
+			scopes:  []string{scopeConstants.PROFILE, "admin"},
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			scopes:  []string{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeScopes(tt.scopes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("scopes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApiKeyAuthenticate(t *testing.T) {
+	const key = authConstants.API_KEY_PREFIX + "secret"
+
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	columns := []string{"id", "uuid", "users_id", "scopes", "expires_at", "last_used_at", "revoked_at", "uuid"}
+
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		revokedAt *time.Time
+		lastUsed  *time.Time
+		touch     bool // Обновляется дата последнего использования
+		wantErr   error
+	}{
+		{name: "valid key", expiresAt: &future, touch: true},
+		{name: "recently used key", lastUsed: &now},
+		{name: "expired key", expiresAt: &past, wantErr: userModel.ErrApiKeyInvalid},
+		{name: "revoked key", revokedAt: &past, wantErr: userModel.ErrApiKeyInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newTestDB(t)
+			r := NewApiKeyPostgres(db)
+
+			mock.ExpectQuery("SELECT tk.id").WithArgs(hashToken(key)).WillReturnRows(
+				sqlmock.NewRows(columns).AddRow(
+					1, "key-uuid", 10, "{service:verify,profile}", tt.expiresAt, tt.lastUsed, tt.revokedAt, "user-uuid",
+				),
+			)
+
+			if tt.touch {
+				mock.ExpectExec(regexp.QuoteMeta("SET last_used_at")).WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			data, err := r.Authenticate(key)
+			if err != tt.wantErr {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil {
+				want := []string{scopeConstants.SERVICE_VERIFY, scopeConstants.PROFILE}
+				if data.UsersId != 10 || data.UsersUuid != "user-uuid" || !reflect.DeepEqual(data.Scopes, want) {
+					t.Fatalf("unexpected identity %+v", data)
+				}
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	t.Run("unknown prefix", func(t *testing.T) {
+		db, mock := newTestDB(t)
+
+		if _, err := NewApiKeyPostgres(db).Authenticate("secret"); err != userModel.ErrApiKeyInvalid {
+			t.Fatalf("err = %v, want %v", err, userModel.ErrApiKeyInvalid)
+		}
+
+		// Ключ без префикса не ищется в базе данных
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		db, mock := newTestDB(t)
+		mock.ExpectQuery("SELECT tk.id").WillReturnRows(sqlmock.NewRows(columns))
+
+		if _, err := NewApiKeyPostgres(db).Authenticate(key); err != userModel.ErrApiKeyInvalid {
+			t.Fatalf("err = %v, want %v", err, userModel.ErrApiKeyInvalid)
+		}
+	})
+}
@@ -1,12 +1,13 @@
 package repository
 
 import (
-	"crypto/sha1"
+	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
-	"strings"
+	"sync"
 	"time"
 
 	config "main-server/config"
@@ -18,6 +19,8 @@ import (
 	"main-server/pkg/model/user"
 	userModel "main-server/pkg/model/user"
 	authService "main-server/pkg/service/auth"
+	"main-server/pkg/service/denylist"
+	passwordService "main-server/pkg/service/password"
 	smtpService "main-server/pkg/service/smtp"
 
 	roleConstant "main-server/pkg/constant/role"
@@ -27,8 +30,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
-	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/oauth2"
 )
 
@@ -36,24 +39,33 @@ type AuthPostgres struct {
 	db           *sqlx.DB
 	enforcer     *casbin.Enforcer
 	userPostgres UserPostgres
+	mfa          *MfaPostgres
+	denylist     denylist.Store
 }
 
 /* Функция создания нового экземлпяра структуры AuthPostgres */
-func NewAuthPostgres(db *sqlx.DB, enforcer *casbin.Enforcer, userPostgres UserPostgres) *AuthPostgres {
+func NewAuthPostgres(db *sqlx.DB, enforcer *casbin.Enforcer, userPostgres UserPostgres, mfa *MfaPostgres, store denylist.Store) *AuthPostgres {
 	return &AuthPostgres{
 		db:           db,
 		enforcer:     enforcer,
 		userPostgres: userPostgres,
+		mfa:          mfa,
+		denylist:     store,
 	}
 }
 
 /* Метод регистрации нового пользователя в системе */
-func (r *AuthPostgres) CreateUser(user userModel.UserSignUpModel) (userModel.UserAuthDataModel, error) {
+func (r *AuthPostgres) CreateUser(user userModel.UserSignUpModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
 	check := CheckRowExists(r.db, tableConstants.U_USERS, "email", user.Email)
 	if check {
 		return userModel.UserAuthDataModel{}, errors.New("Пользователь с данным email-адресом уже существует!")
 	}
 
+	// Проверка пароля на соответствие политике паролей
+	if err := passwordService.Validate(user.Password, passwordPersonal(user.Email, user.Data)); err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
 	// Начало транзакции
 	tx, err := r.db.Begin()
 	if err != nil {
@@ -61,12 +73,13 @@ func (r *AuthPostgres) CreateUser(user userModel.UserSignUpModel) (userModel.Use
 	}
 
 	// Хэширование пароля
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), viper.GetInt("crypt.cost"))
+	hashedPassword, err := passwordService.Hash(user.Password)
 	if err != nil {
+		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	user.Password = string(hashedPassword)
+	user.Password = hashedPassword
 
 	var id int
 	var userUuid string
@@ -83,6 +96,11 @@ func (r *AuthPostgres) CreateUser(user userModel.UserSignUpModel) (userModel.Use
 		return userModel.UserAuthDataModel{}, errors.New("Пользователь с данными регистрационными данными уже существует!")
 	}
 
+	if err = savePasswordHistory(tx, id, user.Password); err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
+
 	// Запрос на добавление пользовательских данных
 	query = fmt.Sprintf(
 		`INSERT INTO %s (data, created_at, updated_at, users_id) 
@@ -132,73 +150,22 @@ func (r *AuthPostgres) CreateUser(user userModel.UserSignUpModel) (userModel.Use
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Генерация пары токенов (токен доступа и токен обновления)
-	accessToken, err := GenerateToken(userUuid, authTypes.Uuid, nil, authConstants.TOKEN_TLL_ACCESS, viper.GetString("token.signing_key_access"))
-	if err != nil {
-		tx.Rollback()
-		return userModel.UserAuthDataModel{}, err
-	}
-
-	refreshToken, err := GenerateToken(userUuid, authTypes.Uuid, nil, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_refresh"))
-	if err != nil {
-		tx.Rollback()
-		return userModel.UserAuthDataModel{}, err
-	}
-
-	// Добавления токенов пользователю
-	query = fmt.Sprintf("INSERT INTO %s (users_id, access_token, refresh_token) values ($1, $2, $3)", tableConstants.U_TOKENS)
-	_, err = tx.Exec(query, id, accessToken, refreshToken)
+	// Создание сессии пользователя (пара токенов для текущего устройства)
+	authData, err := createSession(tx, id, userUuid, authTypes.Uuid, nil, nil, session)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
 	// Добавление пользователю ссылки для активации аккаунта
-	u2 := uuid.NewV4()
-	query = fmt.Sprintf("INSERT INTO %s (users_id, is_activated, activation_link) values ($1, $2, $3)", tableConstants.U_ACTIVATIONS)
-	_, err = tx.Exec(query, id, false, u2)
+	link, err := createActivationLink(tx, id)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
 	// Отправка сообщения пользователю
-	err = smtpService.SendMessage(user.Email, smtpService.BuildMessage(email.Mail{
-		Sender:  viper.GetString("smtp.email"),
-		To:      []string{user.Email},
-		Subject: "Подтверждение аккаунта \"Rental housing\"",
-		Body: fmt.Sprintf(`<html>
-		<head>
-			<meta charset="utf-8" />
-			<title></title>
-		</head>
-		<style>
-			body {background-color: #FEFEF9;}
-			h2   {color: #181511;}
-			button {
-				color: rgb(0, 0, 0);
-				outline: none;
-				border: none;
-				border-radius: 30px;
-				background-color: #B19472;
-				padding: 8px 16px;
-				margin-top: 16px;
-				cursor: pointer;
-			}
-		</style>
-		<body>
-			<h2>Подтверждение E-mail</h2>
-			<br><text>Вы получили это письмо, так как Ваш почтовый адрес был указан в приложении "Rental housing".</text> 
-			</br><text>Чтобы подтвердить Вашу почту перейдите по ссылке: </text></br>
-			<a href="%s">
-			<button>Подтвердить E-mail</button>
-			</a>
-			<br><br><br>
-			<text>Если Вы не проходили процедуру регистрации в приложении "Rental housing", то не отвечайте на данное сообщение.</text>
-		</body>
-	</html>`, viper.GetString("api_url")+"/auth/activate/"+u2.String()),
-	}))
-
+	err = sendActivationEmail(user.Email, link)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
@@ -209,10 +176,7 @@ func (r *AuthPostgres) CreateUser(user userModel.UserSignUpModel) (userModel.Use
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	return userModel.UserAuthDataModel{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, nil
+	return authData, nil
 }
 
 func (r *AuthPostgres) UploadProfileImage(c *gin.Context, filepath string) (bool, error) {
@@ -261,27 +225,77 @@ func (r *AuthPostgres) UploadProfileImage(c *gin.Context, filepath string) (bool
 	return true, nil
 }
 
+var (
+	dummyHash     string
+	dummyHashOnce sync.Once
+)
+
+/* Фиктивный хэш пароля для проверки входа несуществующего пользователя */
+func dummyPasswordHash() string {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = passwordService.Hash(uuid.NewV4().String())
+	})
+
+	return dummyHash
+}
+
+/* Перехэширование пароля пользователя текущим алгоритмом */
+func (r *AuthPostgres) rehashPassword(userId int, oldHash, password string) error {
+	hash, err := passwordService.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	// Условие на старый хэш защищает от перезаписи пароля, изменённого параллельно
+	query := fmt.Sprintf("UPDATE %s SET password = $1 WHERE id = $2 AND password = $3", tableConstants.U_USERS)
+	if _, err = r.db.Exec(query, hash, userId, oldHash); err != nil {
+		return err
+	}
+
+	// Хэш в истории паролей обновляется, чтобы история не хранила устаревшие параметры
+	query = fmt.Sprintf("UPDATE %s SET password_hash = $1 WHERE users_id = $2 AND password_hash = $3", tableConstants.U_PASSWORD_HISTORY)
+	_, err = r.db.Exec(query, hash, userId, oldHash)
+
+	return err
+}
+
 /* Авторизация пользователя */
-func (r *AuthPostgres) LoginUser(user userModel.UserSignInModel) (userModel.UserAuthDataModel, error) {
+func (r *AuthPostgres) LoginUser(user userModel.UserSignInModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
 	var findUser userModel.UserModel
 	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.email = $1 LIMIT 1", tableConstants.U_USERS)
 	if err := r.db.Get(&findUser, query, user.Email); err != nil {
-		return userModel.UserAuthDataModel{}, errors.New("Пользователя с данным почтовым адресом не существует!")
+		if err != sql.ErrNoRows {
+			return userModel.UserAuthDataModel{}, err
+		}
+
+		// Сравнение с фиктивным хэшем выравнивает время ответа для несуществующих пользователей
+		passwordService.Verify(dummyPasswordHash(), user.Password)
+		return userModel.UserAuthDataModel{}, userModel.ErrInvalidCredentials
 	}
 
-	// Проверка пароля
-	if err := bcrypt.CompareHashAndPassword([]byte(findUser.Password), []byte(user.Password)); err != nil {
-		return userModel.UserAuthDataModel{}, errors.New("Не правильный пароль! Повторите попытку")
+	// Проверка пароля (ошибка не раскрывает, существует ли пользователь)
+	ok, rehash, err := passwordService.Verify(findUser.Password, user.Password)
+	if err != nil || !ok {
+		return userModel.UserAuthDataModel{}, userModel.ErrInvalidCredentials
 	}
 
-	tx, err := r.db.Begin()
-	if err != nil {
+	// Обновление хэша при смене алгоритма или его параметров (ошибка не прерывает вход)
+	if rehash {
+		if err := r.rehashPassword(findUser.Id, findUser.Password, user.Password); err != nil {
+			logrus.Errorf("failed to rehash password of user %d: %s", findUser.Id, err.Error())
+		}
+	}
+
+	if err := checkBan(r.db, findUser.Id); err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.users_id = $1", tableConstants.U_TOKENS)
-	if _, err := r.db.Exec(query, findUser.Id); err != nil {
-		tx.Rollback()
+	if err := checkActivation(r.db, findUser.Id); err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
@@ -316,30 +330,29 @@ func (r *AuthPostgres) LoginUser(user userModel.UserSignInModel) (userModel.User
 		return userModel.UserAuthDataModel{}, errors.New("Данный пользователь не имеет доступа к данному домену!")
 	}
 
-	// Получение типа аутентификации (в данном случае - LOCAL)
-	var authTypes userModel.AuthTypeModel
-	query = fmt.Sprintf("SELECT * FROM %s WHERE value=$1 LIMIT 1", tableConstants.U_AUTH_TYPES)
-	err = r.db.Get(&authTypes, query, authConstants.AUTH_TYPE_LOCAL)
+	// Если требуется второй фактор, вместо пары токенов возвращается токен ожидания
+	pending, err := r.mfa.Challenge(findUser.Id, findUser.Uuid, authConstants.AUTH_TYPE_LOCAL, nil)
 	if err != nil {
 		tx.Rollback()
-		return userModel.UserAuthDataModel{}, errors.New(err.Error())
+		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Генерация токенов доступа и обновления
-	accessToken, err := GenerateToken(findUser.Uuid, authTypes.Uuid, nil, authConstants.TOKEN_TLL_ACCESS, viper.GetString("token.signing_key_access"))
-	if err != nil {
+	if pending != nil {
 		tx.Rollback()
-		return userModel.UserAuthDataModel{}, err
+		return userModel.UserAuthDataModel{Mfa: pending}, nil
 	}
-	refreshToken, err := GenerateToken(findUser.Uuid, authTypes.Uuid, nil, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_refresh"))
+
+	// Получение типа аутентификации (в данном случае - LOCAL)
+	var authTypes userModel.AuthTypeModel
+	query = fmt.Sprintf("SELECT * FROM %s WHERE value=$1 LIMIT 1", tableConstants.U_AUTH_TYPES)
+	err = r.db.Get(&authTypes, query, authConstants.AUTH_TYPE_LOCAL)
 	if err != nil {
 		tx.Rollback()
-		return userModel.UserAuthDataModel{}, err
+		return userModel.UserAuthDataModel{}, errors.New(err.Error())
 	}
 
-	// Установка токенов пользователю
-	query = fmt.Sprintf("INSERT INTO %s (users_id, access_token, refresh_token) values ($1, $2, $3)", tableConstants.U_TOKENS)
-	_, err = tx.Exec(query, findUser.Id, accessToken, refreshToken)
+	// Создание новой сессии (другие сессии пользователя при этом не затрагиваются)
+	authData, err := createSession(tx, findUser.Id, findUser.Uuid, authTypes.Uuid, nil, nil, session)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
@@ -350,14 +363,11 @@ func (r *AuthPostgres) LoginUser(user userModel.UserSignInModel) (userModel.User
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	return userModel.UserAuthDataModel{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, nil
+	return authData, nil
 }
 
-/* Авторизация пользователя через OAuth2 */
-func (r *AuthPostgres) CreateUserOAuth2(user user.UserRegisterOAuth2Model, token *oauth2.Token) (userModel.UserAuthDataModel, error) {
+/* Регистрация пользователя через внешнего провайдера OAuth2 */
+func (r *AuthPostgres) CreateUserOAuth2(provider string, user user.UserRegisterOAuth2Model, token *oauth2.Token, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
 	check := CheckRowExists(r.db, tableConstants.U_USERS, "email", user.Email)
 
 	if check {
@@ -370,13 +380,6 @@ func (r *AuthPostgres) CreateUserOAuth2(user user.UserRegisterOAuth2Model, token
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Хэширование пароля
-	// user.Password = generatePasswordHash(user.Password)
-	/*hashedPassword, err := bcrypt.GenerateFromPassword([]byte(token.AccessToken), viper.GetInt("crypt.cost"))
-	if err != nil {
-		return userModel.UserAuthDataModel{}, err
-	}*/
-
 	var id int
 	var userUuid string
 
@@ -386,7 +389,8 @@ func (r *AuthPostgres) CreateUserOAuth2(user user.UserRegisterOAuth2Model, token
 	// Генерация UUID
 	u1 := uuid.NewV4()
 
-	row := tx.QueryRow(query, user.Email, token.AccessToken, u1)
+	// Пароль не задаётся: вход выполняется через внешнюю учётную запись
+	row := tx.QueryRow(query, user.Email, "", u1)
 	if err := row.Scan(&id, &userUuid); err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, errors.New("Пользователь с данными регистрационными данными уже существует!")
@@ -430,39 +434,23 @@ func (r *AuthPostgres) CreateUserOAuth2(user user.UserRegisterOAuth2Model, token
 	/* Added default user roles */
 	r.enforcer.AddRoleForUserInDomain(strconv.Itoa(id), strconv.Itoa(role.Id), strconv.Itoa(domain.Id))
 
-	// Установка типа аутентификации пользователя (в данном случае - GOOGLE)
+	// Установка типа аутентификации пользователя (значение совпадает с названием провайдера)
 	var authTypes userModel.AuthTypeModel
 	query = fmt.Sprintf("SELECT * FROM %s WHERE value=$1 LIMIT 1", tableConstants.U_AUTH_TYPES)
-	err = r.db.Get(&authTypes, query, authConstants.AUTH_TYPE_GOOGLE)
-	if err != nil {
-		tx.Rollback()
-		return userModel.UserAuthDataModel{}, errors.New(err.Error())
-	}
-
-	query = fmt.Sprintf("INSERT INTO %s (users_id, auth_types_id) values ($1, $2)", tableConstants.U_USERS_AUTH_TYPES)
-	_, err = tx.Exec(query, id, authTypes.Id)
-	if err != nil {
-		tx.Rollback()
-		return userModel.UserAuthDataModel{}, err
-	}
-
-	// Генерация токенов доступа
-	accessToken, err := GenerateToken(userUuid, authTypes.Uuid, &token.AccessToken, authConstants.TOKEN_TLL_ACCESS, viper.GetString("token.signing_key_access"))
+	err = r.db.Get(&authTypes, query, provider)
 	if err != nil {
 		tx.Rollback()
-		return userModel.UserAuthDataModel{}, err
+		return userModel.UserAuthDataModel{}, errors.New("Ошибка: тип авторизации " + provider + " не зарегистрирован")
 	}
 
-	// Генерация токена обновления
-	refreshToken, err := GenerateToken(userUuid, authTypes.Uuid, &token.RefreshToken, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_access"))
-	if err != nil {
+	// Привязка внешней учётной записи к новому пользователю
+	if _, err = createIdentity(tx, id, &authTypes, user); err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Установка токенов пользователю
-	query = fmt.Sprintf("INSERT INTO %s (users_id, access_token, refresh_token) values ($1, $2, $3)", tableConstants.U_TOKENS)
-	_, err = tx.Exec(query, id, accessToken, refreshToken)
+	// Создание сессии пользователя (если роль требует второго фактора - токен ожидания его подключения)
+	authData, err := r.completeOAuth2Login(tx, id, userUuid, &authTypes, token, session)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
@@ -478,106 +466,132 @@ func (r *AuthPostgres) CreateUserOAuth2(user user.UserRegisterOAuth2Model, token
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	return userModel.UserAuthDataModel{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, tx.Commit()
+	return authData, tx.Commit()
 }
 
 /*
-* Функция авторизации пользователя через Google OAuth2
+* Функция авторизации пользователя через внешнего провайдера OAuth2
  */
-func (r *AuthPostgres) LoginUserOAuth2(code string) (userModel.UserAuthDataModel, error) {
-	token, err := config.AppOAuth2Config.GoogleLogin.Exchange(oauth2.NoContext, code)
-
+func (r *AuthPostgres) LoginUserOAuth2(providerName, code string, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	provider, err := authService.GetProvider(providerName)
 	if err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	is_verify, err := authService.VerifyAccessToken(token.AccessToken)
+	ctx := context.Background()
 
+	token, err := provider.Exchange(ctx, code)
 	if err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	if !is_verify {
-		return userModel.UserAuthDataModel{}, errors.New("Данный токен не принадлежит данному пользователю!")
-	}
-
-	var findUser userModel.UserModel
-	var userData userModel.UserRegisterOAuth2Model
-
-	userData, err = authService.GetUserInfo(token)
-
+	userData, err := provider.GetUserInfo(ctx, token)
 	if err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.email = $1 LIMIT 1", tableConstants.U_USERS)
-	if err := r.db.Get(&findUser, query, userData.Email); err != nil {
-		// Если пользователя не существует - создаём его
-		return r.CreateUserOAuth2(userData, token)
-	}
-
-	tx, err := r.db.Begin()
+	// Получение типа аутентификации (значение совпадает с названием провайдера)
+	authType, err := getAuthType(r.db, providerName)
 	if err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	/*hashedPassword, err := bcrypt.GenerateFromPassword([]byte(token.AccessToken), viper.GetInt("crypt.cost"))
+	identity, err := getIdentity(r.db, authType.Id, userData.Subject)
 	if err != nil {
 		return userModel.UserAuthDataModel{}, err
-	}*/
+	}
 
-	// Запрос на обновление пароля в базе данных для пользователя
-	query = fmt.Sprintf("UPDATE %s SET password=$1 WHERE email=$2", tableConstants.U_USERS)
+	var findUser *userModel.UserModel
 
-	if _, err := r.db.Exec(query, token.AccessToken, userData.Email); err != nil {
-		tx.Rollback()
-		return userModel.UserAuthDataModel{}, err
+	if identity != nil {
+		// Внешняя учётная запись уже привязана - вход выполняется под её владельцем
+		if findUser, err = r.userPostgres.Get("id", identity.UsersId, true); err != nil {
+			return userModel.UserAuthDataModel{}, err
+		}
+	} else {
+		if !userData.EmailVerified {
+			return userModel.UserAuthDataModel{}, errors.New("Email-адрес пользователя не подтверждён у провайдера " + providerName)
+		}
+
+		if findUser, err = r.userPostgres.Get("email", userData.Email, false); err != nil {
+			return userModel.UserAuthDataModel{}, err
+		}
+
+		if findUser == nil {
+			// Если пользователя не существует - создаём его
+			return r.CreateUserOAuth2(providerName, userData, token, session)
+		}
+
+		// Автоматически привязываются только учётные записи, созданные через данного провайдера
+		// до появления таблицы u_identities. Остальные привязываются из профиля пользователя
+		legacy, err := r.isLegacyOAuth2User(findUser.Id, authType)
+		if err != nil {
+			return userModel.UserAuthDataModel{}, err
+		}
+
+		if !legacy {
+			return userModel.UserAuthDataModel{}, errors.New(
+				"Пользователь с данным email-адресом уже существует! Войдите в систему и привяжите учётную запись " + providerName + " в профиле",
+			)
+		}
 	}
 
-	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.users_id = $1", tableConstants.U_TOKENS)
-	if _, err := r.db.Exec(query, findUser.Id); err != nil {
-		tx.Rollback()
+	tx, err := r.db.Begin()
+	if err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Получение типа аутентификации (в данном случае - GOOGLE)
-	var authTypes userModel.AuthTypeModel
-	query = fmt.Sprintf("SELECT * FROM %s WHERE value=$1 LIMIT 1", tableConstants.U_AUTH_TYPES)
-	err = r.db.Get(&authTypes, query, authConstants.AUTH_TYPE_GOOGLE)
-	if err != nil {
-		tx.Rollback()
-		return userModel.UserAuthDataModel{}, errors.New(err.Error())
+	if identity == nil {
+		if _, err = createIdentity(tx, findUser.Id, authType, userData); err != nil {
+			tx.Rollback()
+			return userModel.UserAuthDataModel{}, err
+		}
 	}
 
-	// Генерация токена доступа
-	accessToken, err := GenerateToken(findUser.Uuid, authTypes.Uuid, &token.AccessToken, authConstants.TOKEN_TLL_ACCESS, viper.GetString("token.signing_key_access"))
+	// Создание новой сессии (другие сессии пользователя при этом не затрагиваются)
+	authData, err := r.completeOAuth2Login(tx, findUser.Id, findUser.Uuid, authType, token, session)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Генерация токена обновления
-	refreshToken, err := GenerateToken(findUser.Uuid, authTypes.Uuid, &token.RefreshToken, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_access"))
+	return authData, tx.Commit()
+}
+
+/*
+* Завершение входа через внешнего провайдера. Как и при входе по паролю, если требуется второй фактор,
+* вместо пары токенов возвращается токен ожидания (сессия создаётся после его проверки)
+ */
+func (r *AuthPostgres) completeOAuth2Login(
+	tx *sql.Tx, userId int, usersUuid string, authType *userModel.AuthTypeModel,
+	token *oauth2.Token, session userModel.SessionInfoModel,
+) (userModel.UserAuthDataModel, error) {
+	pending, err := r.mfa.Challenge(userId, usersUuid, authType.Value, token)
 	if err != nil {
-		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Установка токенов пользователю
-	query = fmt.Sprintf("INSERT INTO %s (users_id, access_token, refresh_token) values ($1, $2, $3)", tableConstants.U_TOKENS)
-	_, err = tx.Exec(query, findUser.Id, accessToken, refreshToken)
-	if err != nil {
-		tx.Rollback()
-		return userModel.UserAuthDataModel{}, err
+	if pending != nil {
+		return userModel.UserAuthDataModel{Mfa: pending}, nil
 	}
 
-	return userModel.UserAuthDataModel{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, tx.Commit()
+	return createSession(tx, userId, usersUuid, authType.Uuid, &token.AccessToken, &token.RefreshToken, session)
+}
+
+/* Проверка, что пользователь был зарегистрирован через провайдера без сохранения внешней учётной записи */
+func (r *AuthPostgres) isLegacyOAuth2User(userId int, authType *userModel.AuthTypeModel) (bool, error) {
+	has, err := hasAuthType(r.db, userId, authType.Value)
+	if err != nil || !has {
+		return false, err
+	}
+
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE users_id = $1 AND auth_types_id = $2", tableConstants.U_IDENTITIES)
+	if err := r.db.Get(&count, query, userId, authType.Id); err != nil {
+		return false, err
+	}
+
+	return count <= 0, nil
 }
 
 /**
@@ -593,79 +607,114 @@ func (r *AuthPostgres) Refresh(data userModel.TokenLogoutDataModel, rToken strin
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	var findToken userModel.TokenModel
-	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.refresh_token = $1 AND tl.users_id = $2 LIMIT 1", tableConstants.U_TOKENS)
-
-	if err := r.db.Get(&findToken, query, rToken, user.Id); err != nil {
-		return userModel.UserAuthDataModel{}, errors.New("Пользователя с данным токеном обновления не существует!")
+	// Сессии заблокированного пользователя уже удалены, но ошибка должна указывать на блокировку
+	if err := checkBan(r.db, user.Id); err != nil {
+		return userModel.UserAuthDataModel{}, err
 	}
 
-	isValid := ValidToken(rToken, viper.GetString("token.signing_key_refresh"))
-
-	setValues := make([]string, 0)
-	args := make([]interface{}, 0)
-	argId := 1
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
 
-	var refreshToken string
+	// Поиск конкретной сессии, которой принадлежит токен обновления (блокировка строки исключает
+	// параллельную ротацию одного и того же токена)
+	var findToken userModel.TokenModel
+	query := fmt.Sprintf(`SELECT * FROM %s tl WHERE tl.refresh_token = $1 AND tl.users_id = $2 AND tl.uuid = $3
+		LIMIT 1 FOR UPDATE`, tableConstants.U_TOKENS)
 
-	if !isValid {
-		switch token.AuthType.Value {
-		case "LOCAL":
-			refreshToken, err = GenerateToken(user.Uuid, token.AuthType.Uuid, nil, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_refresh"))
-			break
+	if err := tx.Get(&findToken, query, rToken, user.Id, token.SessionUuid); err != nil {
+		tx.Rollback()
 
-		case "GOOGLE":
-			// Если токен от Google OAuth2 не валиден, то нужно чтобы пользователь перезашёл в приложение заново
-			//google_oauth2.RevokeToken(*token.TokenApi)
-			//r.Logout(data)
-			refreshToken, err = GenerateToken(user.Uuid, token.AuthType.Uuid, token.TokenApi, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_refresh"))
-			break
+		if err == sql.ErrNoRows {
+			return userModel.UserAuthDataModel{}, r.checkRefreshTokenReuse(rToken)
 		}
 
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	// Сессия с истёкшим токеном обновления завершается
+	if !ValidToken(rToken, viper.GetString("token.signing_key_refresh")) {
+		tokens, err := deleteSessions(tx, "id = $1", findToken.Id)
 		if err != nil {
+			tx.Rollback()
 			return userModel.UserAuthDataModel{}, err
 		}
 
-		setValues = append(setValues, fmt.Sprintf("refresh_token=$%d", argId))
-		args = append(args, refreshToken)
-		argId++
-	} else {
-		refreshToken = rToken
-	}
+		if err = tx.Commit(); err != nil {
+			return userModel.UserAuthDataModel{}, err
+		}
 
-	var accessToken string
+		if err = denyAccessTokens(r.denylist, tokens); err != nil {
+			return userModel.UserAuthDataModel{}, err
+		}
 
-	switch token.AuthType.Value {
-	case authConstants.AUTH_TYPE_LOCAL:
-		accessToken, err = GenerateToken(user.Uuid, token.AuthType.Uuid, nil, authConstants.TOKEN_TLL_ACCESS, viper.GetString("token.signing_key_access"))
-		break
+		return userModel.UserAuthDataModel{}, errors.New("Срок действия токена обновления истёк! Повторите вход в систему")
+	}
 
-	case authConstants.AUTH_TYPE_GOOGLE:
-		tokenData, err := authService.RefreshAccessToken(oauth2.NoContext, *token.TokenApi)
-		accessToken, err = GenerateToken(user.Uuid, token.AuthType.Uuid, &tokenData.AccessToken, authConstants.TOKEN_TLL_ACCESS, viper.GetString("token.signing_key_access"))
+	// Токены внешнего провайдера обновляются вместе с токенами сессии
+	var accessApi, refreshApi *string
+	if provider, providerErr := authService.GetProvider(token.AuthType.Value); providerErr == nil && token.TokenApi != nil {
+		providerToken := &oauth2.Token{RefreshToken: *token.TokenApi}
+		if data.TokenApi != nil {
+			providerToken.AccessToken = *data.TokenApi
+		}
 
+		providerToken, err = provider.Refresh(context.Background(), providerToken)
 		if err != nil {
+			tx.Rollback()
 			return userModel.UserAuthDataModel{}, err
 		}
+
+		accessApi, refreshApi = &providerToken.AccessToken, &providerToken.RefreshToken
+	}
+
+	// Ротация: новый токен обновления выдаётся при каждом обновлении
+	refreshToken, err := GenerateToken(user.Uuid, token.AuthType.Uuid, findToken.Uuid, refreshApi, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_refresh"))
+	if err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
 	}
 
+	accessToken, err := GenerateAccessToken(user.Uuid, token.AuthType.Uuid, findToken.Uuid, accessApi)
 	if err != nil {
+		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	setValues = append(setValues, fmt.Sprintf("access_token=$%d", argId))
-	args = append(args, accessToken)
-	argId++
+	currentDate := time.Now()
 
-	setQuery := strings.Join(setValues, ", ")
+	// Пометка предыдущего токена обновления как использованного
+	query = fmt.Sprintf(`INSERT INTO %s (sessions_uuid, users_id, token_hash, consumed_at)
+		values ($1, $2, $3, $4)`, tableConstants.U_TOKENS_CONSUMED)
+	if _, err = tx.Exec(query, findToken.Uuid, user.Id, hashToken(rToken), currentDate); err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
 
-	query = fmt.Sprintf("UPDATE %s tl SET %s WHERE tl.users_id = $%d",
-		tableConstants.U_TOKENS, setQuery, argId)
-	args = append(args, user.Id)
+	// Записи старше времени жизни токена обновления больше не нужны
+	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.consumed_at < $1", tableConstants.U_TOKENS_CONSUMED)
+	if _, err = tx.Exec(query, currentDate.Add(-authConstants.TOKEN_TLL_REFRESH)); err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
 
-	// Обновление данных о токене пользователя
-	_, err = r.db.Exec(query, args...)
-	if err != nil {
+	// Обновление затрагивает только текущую сессию пользователя
+	query = fmt.Sprintf(`UPDATE %s tl SET access_token=$1, refresh_token=$2, refreshed_at=$3
+		WHERE tl.id = $4`, tableConstants.U_TOKENS)
+	if _, err = tx.Exec(query, accessToken, refreshToken, currentDate, findToken.Id); err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	// Предыдущий токен доступа больше не хранится в сессии, поэтому отзывается сразу:
+	// иначе он переживёт выход, завершение сессии и блокировку пользователя
+	if err = denyAccessToken(r.denylist, findToken.AccessToken); err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
@@ -676,29 +725,102 @@ func (r *AuthPostgres) Refresh(data userModel.TokenLogoutDataModel, rToken strin
 	}, nil
 }
 
+/*
+* Проверка повторного использования токена обновления. Если токен уже был использован,
+* то отзывается всё семейство токенов (сессия), которому он принадлежал
+ */
+func (r *AuthPostgres) checkRefreshTokenReuse(rToken string) error {
+	var consumed []userModel.TokenConsumedModel
+	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.token_hash = $1 LIMIT 1", tableConstants.U_TOKENS_CONSUMED)
+
+	if err := r.db.Select(&consumed, query, hashToken(rToken)); err != nil {
+		return err
+	}
+
+	if len(consumed) <= 0 {
+		return errors.New("Пользователя с данным токеном обновления не существует!")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	tokens, err := deleteSessions(tx, "uuid = $1", consumed[0].SessionsUuid)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.sessions_uuid = $1", tableConstants.U_TOKENS_CONSUMED)
+	if _, err = tx.Exec(query, consumed[0].SessionsUuid); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = denyAccessTokens(r.denylist, tokens); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"event":        "refresh_token_reuse",
+		"users_id":     consumed[0].UsersId,
+		"session_uuid": consumed[0].SessionsUuid,
+		"consumed_at":  consumed[0].ConsumedAt,
+	}).Warn("Повторное использование токена обновления: сессия пользователя отозвана")
+
+	return errors.New("Токен обновления уже был использован! Сессия завершена, повторите вход в систему")
+}
+
 /*
 *	Функция подтверждения аккаунта
  */
 func (r *AuthPostgres) Activate(link string) (bool, error) {
+	if _, err := uuid.FromString(link); err != nil {
+		return false, userModel.ErrActivationLinkNotFound
+	}
+
 	var findActivate userModel.UserActivateModel
-	query := fmt.Sprintf("SELECT activation_link, is_activated FROM %s WHERE activation_link = $1", tableConstants.U_ACTIVATIONS)
+	query := fmt.Sprintf(`
+		SELECT users_id, activation_link, is_activated, created_at, expires_at, activated_at
+		FROM %s WHERE activation_link = $1
+	`, tableConstants.U_ACTIVATIONS)
 
 	if err := r.db.Get(&findActivate, query, link); err != nil {
-		return false, errors.New(err.Error())
+		if err == sql.ErrNoRows {
+			return false, userModel.ErrActivationLinkNotFound
+		}
+
+		return false, err
 	}
 
 	if findActivate.IsActivated {
-		return true, nil
+		return false, userModel.ErrActivationLinkUsed
 	}
 
-	query = fmt.Sprintf("UPDATE %s SET is_activated=%s WHERE activation_link = $1", tableConstants.U_ACTIVATIONS, "true")
+	if findActivate.ExpiresAt != nil && time.Now().After(*findActivate.ExpiresAt) {
+		return false, userModel.ErrActivationLinkExpired
+	}
 
-	_, err := r.db.Exec(query, link)
+	query = fmt.Sprintf(`
+		UPDATE %s SET is_activated = true, activated_at = NOW() WHERE activation_link = $1 AND is_activated = false
+	`, tableConstants.U_ACTIVATIONS)
 
+	result, err := r.db.Exec(query, link)
 	if err != nil {
 		return false, err
 	}
 
+	// Ссылка могла быть использована параллельным запросом
+	if count, err := result.RowsAffected(); err == nil && count == 0 {
+		return false, userModel.ErrActivationLinkUsed
+	}
+
 	return true, nil
 }
 
@@ -706,54 +828,53 @@ func (r *AuthPostgres) Activate(link string) (bool, error) {
 * Функция разлогирования пользователя
  */
 func (r *AuthPostgres) Logout(data userModel.TokenLogoutDataModel) (bool, error) {
-	// Выход из аккаунта зависит от метода аутентификации (предварительная проверка обязательна)
-	switch data.AuthTypeValue {
-	case authConstants.AUTH_TYPE_GOOGLE:
-		authService.RevokeToken(*data.TokenApi)
-		break
+	// Токен внешнего провайдера отзывается, если пользователь авторизовался через него
+	if provider, err := authService.GetProvider(data.AuthTypeValue); err == nil && data.TokenApi != nil {
+		if err := provider.Revoke(context.Background(), &oauth2.Token{AccessToken: *data.TokenApi}); err != nil {
+			logrus.Warnf("Ошибка при отзыве токена %s: %s", data.AuthTypeValue, err.Error())
+		}
+	}
+
+	// Токен доступа отзывается сразу, а не по истечении срока действия
+	tokens, err := deleteSessions(r.db, "access_token = $1 AND refresh_token = $2", data.AccessToken, data.RefreshToken)
+	if err != nil {
+		return false, err
 	}
 
-	query := fmt.Sprintf("DELETE FROM %s tl WHERE tl.access_token=$1 AND tl.refresh_token=$2 RETURNING id", tableConstants.U_TOKENS)
-	row := r.db.QueryRow(query, data.AccessToken, data.RefreshToken)
+	if len(tokens) <= 0 {
+		return false, sql.ErrNoRows
+	}
 
-	var id int
-	if err := row.Scan(&id); err != nil {
+	if err = denyAccessTokens(r.denylist, tokens); err != nil {
 		return false, err
 	}
 
 	return true, nil
 }
 
-type AuthTypeValue struct {
-	Value string `json:"value" db:"value"`
-}
-
 /*
 * Функция обработки запроса на восстановление пароля
  */
 func (r *AuthPostgres) RecoveryPassword(userEmail string) (bool, error) {
-	// Check exists user in system
+	// Check exists user in system (the response does not reveal whether the account exists)
 	user, err := r.GetUser("email", userEmail)
 	if err != nil {
-		return false, errors.New("Пользователя с данным email-адресом не существует!")
-	}
+		if err == sql.ErrNoRows {
+			return true, nil
+		}
 
-	query := fmt.Sprintf(`SELECT value FROM %s tl
-	INNER JOIN %s td on td.auth_types_id = tl.id WHERE td.users_id=$1 LIMIT 1`,
-		tableConstants.U_AUTH_TYPES, tableConstants.U_USERS_AUTH_TYPES)
+		return false, err
+	}
 
-	var authType AuthTypeValue
-	err = r.db.Get(&authType, query, user.Id)
+	// Восстановление пароля доступно только пользователям с локальным типом авторизации.
+	// Для остальных письмо не отправляется, но ответ тот же, что и для несуществующего адреса
+	hasLocal, err := hasAuthType(r.db, user.Id, authConstants.AUTH_TYPE_LOCAL)
 	if err != nil {
 		return false, err
 	}
 
-	if authType.Value != authConstants.AUTH_TYPE_LOCAL {
-		return false, errors.New(`
-		Восстановление пароля для данного пользователя не поддерживается, так как 
-		пользователь авторизовался через сторонний сервис (Google, VK).
-		Пожалуйста, воспользуйтесь сторонним сервисом для авторизации пользователя
-		`)
+	if !hasLocal {
+		return true, nil
 	}
 
 	// Delete other reset tokens for current user
@@ -762,7 +883,7 @@ func (r *AuthPostgres) RecoveryPassword(userEmail string) (bool, error) {
 		return false, err
 	}
 
-	query = fmt.Sprintf("DELETE FROM %s tl WHERE users_id=$1", tableConstants.U_RESET_TOKENS)
+	query := fmt.Sprintf("DELETE FROM %s tl WHERE users_id=$1", tableConstants.U_RESET_TOKENS)
 
 	_, err = tx.Exec(query, user.Id)
 	if err != nil {
@@ -849,22 +970,23 @@ func (r *AuthPostgres) ResetPassword(data userModel.ResetPasswordModel, token us
 		return false, err
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(data.Password), viper.GetInt("crypt.cost"))
-	if err != nil {
+	// Проверка политики и истории паролей, сохранение нового пароля
+	if err = changePassword(tx, token.UsersId, data.Password, nil); err != nil {
+		tx.Rollback()
 		return false, err
 	}
 
-	query := fmt.Sprintf("UPDATE %s SET password=$1 WHERE id=$2", tableConstants.U_USERS)
-	_, err = tx.Exec(query, string(hashedPassword), token.UsersId)
+	// Delete all reset tokens for current users
+	query := fmt.Sprintf("DELETE FROM %s tl WHERE users_id=$1", tableConstants.U_RESET_TOKENS)
+
+	_, err = tx.Exec(query, token.UsersId)
 	if err != nil {
 		tx.Rollback()
 		return false, err
 	}
 
-	// Delete all reset tokens for current users
-	query = fmt.Sprintf("DELETE FROM %s tl WHERE users_id=$1", tableConstants.U_RESET_TOKENS)
-
-	_, err = tx.Exec(query, token.UsersId)
+	// После сброса пароля все сессии пользователя завершаются, а их токены доступа отзываются
+	tokens, err := deleteSessions(tx, "users_id = $1", token.UsersId)
 	if err != nil {
 		tx.Rollback()
 		return false, err
@@ -877,6 +999,10 @@ func (r *AuthPostgres) ResetPassword(data userModel.ResetPasswordModel, token us
 		return false, err
 	}
 
+	if err = denyAccessTokens(r.denylist, tokens); err != nil {
+		return false, err
+	}
+
 	return true, nil
 }
 
@@ -916,40 +1042,82 @@ func (r *AuthPostgres) GetResetToken(column, value string) (userModel.ResetToken
 	return token, err
 }
 
-/*
-* Password hashing function
- */
-func generatePasswordHash(password string) string {
-	hash := sha1.New()
-	hash.Write([]byte(password))
-
-	return fmt.Sprintf("%x", hash.Sum([]byte(viper.GetString("crypt.salt"))))
-}
-
 /* Working with user authentication tokens */
 /* Token Body Structure */
 type tokenClaims struct {
 	jwt.StandardClaims
 	UsersId     string  `json:"users_id"`      // ID пользователя
 	AuthTypesId string  `json:"auth_types_id"` // Тип аутентификации пользователя
+	SessionId   string  `json:"session_id"`    // Идентификатор сессии пользователя
 	TokenApi    *string `json:"token_api"`     // Внешний токен доступа
+
+	// Только для токенов, выданных клиенту OAuth2 по client_credentials
+	ClientId string `json:"client_id,omitempty"` // Идентификатор клиента
+	Scope    string `json:"scope,omitempty"`     // Области доступа (через пробел)
 }
 
 /*
 * Token generation function
  */
-func GenerateToken(uuid, authTypesUuid string, tokenApi *string, tokenTTL time.Duration, signingKey string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{
+func GenerateToken(usersUuid, authTypesUuid, sessionUuid string, tokenApi *string, tokenTTL time.Duration, signingKey string) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, newTokenClaims(usersUuid, authTypesUuid, sessionUuid, tokenApi, tokenTTL))
+
+	return token.SignedString([]byte(signingKey))
+}
+
+/*
+* Access token generation function. If asymmetric keys are configured, the token is signed
+* with the active key (its kid is written to the header), otherwise with the shared secret
+ */
+func GenerateAccessToken(usersUuid, authTypesUuid, sessionUuid string, tokenApi *string) (string, error) {
+	return signAccessClaims(newTokenClaims(usersUuid, authTypesUuid, sessionUuid, tokenApi, authConstants.TOKEN_TLL_ACCESS))
+}
+
+/*
+* Service token generation function (client_credentials grant). The token has no session
+* and carries the client ID and the granted scopes
+ */
+func GenerateServiceToken(usersUuid, authTypesUuid, clientId, scope string) (string, error) {
+	claims := newTokenClaims(usersUuid, authTypesUuid, "", nil, authConstants.TOKEN_TLL_ACCESS)
+	claims.ClientId = clientId
+	claims.Scope = scope
+
+	return signAccessClaims(claims)
+}
+
+/* Access token signing (asymmetric key if configured, otherwise the shared secret) */
+func signAccessClaims(claims *tokenClaims) (string, error) {
+	if !config.AppJWTKeys.Enabled() {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+		return token.SignedString([]byte(viper.GetString("token.signing_key_access")))
+	}
+
+	key, err := config.AppJWTKeys.SigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.Kid
+
+	return token.SignedString(key.Private)
+}
+
+func newTokenClaims(usersUuid, authTypesUuid, sessionUuid string, tokenApi *string, tokenTTL time.Duration) *tokenClaims {
+	return &tokenClaims{
 		jwt.StandardClaims{
+			Id:        uuid.NewV4().String(),
 			ExpiresAt: time.Now().Add(tokenTTL).Unix(),
 			IssuedAt:  time.Now().Unix(),
 		},
-		uuid,
+		usersUuid,
 		authTypesUuid,
+		sessionUuid,
 		tokenApi,
-	})
-
-	return token.SignedString([]byte(signingKey))
+		"",
+		"",
+	}
 }
 
 /*
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	authConstants "main-server/pkg/constant/auth"
+	tableConstants "main-server/pkg/constant/table"
+	userModel "main-server/pkg/model/user"
+	"main-server/pkg/service/denylist"
+
+	"github.com/jmoiron/sqlx"
+	uuid "github.com/satori/go.uuid"
+)
+
+type BanPostgres struct {
+	db       *sqlx.DB
+	user     *UserPostgres
+	denylist denylist.Store
+}
+
+/* Создание нового экземпляра структуры BanPostgres */
+func NewBanPostgres(db *sqlx.DB, user *UserPostgres, store denylist.Store) *BanPostgres {
+	return &BanPostgres{
+		db:       db,
+		user:     user,
+		denylist: store,
+	}
+}
+
+/* Источник строк для поиска блокировки (подключение к БД или транзакция) */
+type rowQueryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+/*
+* Получение действующей блокировки пользователя. Блокировка с истёкшим сроком
+* снимается автоматически - она просто перестаёт считаться действующей
+ */
+func getActiveBan(q rowQueryer, userId int) (*userModel.BanModel, error) {
+	var ban userModel.BanModel
+	query := fmt.Sprintf(`
+		SELECT id, uuid, users_id, kind, reason, created_at, expires_at FROM %s
+		WHERE users_id = $1 AND lifted_at IS NULL AND (expires_at IS NULL OR expires_at > $2)
+		ORDER BY created_at DESC LIMIT 1
+	`, tableConstants.U_BANS)
+
+	err := q.QueryRow(query, userId, time.Now()).Scan(
+		&ban.Id, &ban.Uuid, &ban.UsersId, &ban.Kind, &ban.Reason, &ban.CreatedAt, &ban.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	ban.IsActive = true
+
+	return &ban, nil
+}
+
+/* Запрет доступа заблокированному пользователю */
+func checkBan(q rowQueryer, userId int) error {
+	ban, err := getActiveBan(q, userId)
+	if err != nil {
+		return err
+	}
+
+	if ban != nil {
+		return &userModel.BanError{Ban: ban}
+	}
+
+	return nil
+}
+
+/* Получение действующей блокировки пользователя (nil, если пользователь не заблокирован) */
+func (r *BanPostgres) GetActive(userId int) (*userModel.BanModel, error) {
+	return getActiveBan(r.db, userId)
+}
+
+/* Получение истории блокировок пользователя */
+func (r *BanPostgres) GetAll(usersUuid string) (*userModel.BansModel, error) {
+	user, err := r.user.Get("uuid", usersUuid, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var bans []userModel.BanModel
+	query := fmt.Sprintf(`
+		SELECT tb.id, tb.uuid, tb.users_id, tb.kind, tb.reason, tb.created_at, tb.expires_at,
+			COALESCE(tb.lifted_at, CASE WHEN tb.expires_at <= $2 THEN tb.expires_at END) AS lifted_at,
+			ub.uuid AS banned_by, ul.uuid AS lifted_by,
+			(tb.lifted_at IS NULL AND (tb.expires_at IS NULL OR tb.expires_at > $2)) AS is_active
+		FROM %s tb
+		LEFT JOIN %s ub ON ub.id = tb.banned_by
+		LEFT JOIN %s ul ON ul.id = tb.lifted_by
+		WHERE tb.users_id = $1 ORDER BY tb.created_at DESC
+	`, tableConstants.U_BANS, tableConstants.U_USERS, tableConstants.U_USERS)
+
+	if err := r.db.Select(&bans, query, user.Id, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return &userModel.BansModel{
+		Bans: bans,
+	}, nil
+}
+
+/* Блокировка пользователя с немедленным завершением всех его сессий */
+func (r *BanPostgres) Create(actor *userModel.UserIdentityModel, data userModel.BanCreateModel) (*userModel.BanModel, error) {
+	if data.Kind != authConstants.BAN_KIND_BAN && data.Kind != authConstants.BAN_KIND_SUSPEND {
+		return nil, errors.New("Ошибка: неизвестный тип блокировки")
+	}
+
+	if data.Kind == authConstants.BAN_KIND_SUSPEND && data.ExpiresAt == nil {
+		return nil, errors.New("Ошибка: для временного отстранения необходимо указать срок")
+	}
+
+	currentDate := time.Now()
+	if data.ExpiresAt != nil && !data.ExpiresAt.After(currentDate) {
+		return nil, errors.New("Ошибка: срок блокировки должен быть в будущем")
+	}
+
+	user, err := r.user.Get("uuid", data.UsersUuid, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Id == actor.UserId {
+		return nil, errors.New("Ошибка: нельзя заблокировать самого себя")
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+
+	// Блокировка строки пользователя исключает параллельное создание двух блокировок
+	query := fmt.Sprintf("SELECT id FROM %s WHERE id = $1 FOR UPDATE", tableConstants.U_USERS)
+	if _, err = tx.Exec(query, user.Id); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	active, err := getActiveBan(tx, user.Id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if active != nil {
+		tx.Rollback()
+		return nil, errors.New("Ошибка: пользователь уже заблокирован, снимите текущую блокировку")
+	}
+
+	ban := userModel.BanModel{
+		Uuid:      uuid.NewV4().String(),
+		UsersId:   user.Id,
+		Kind:      data.Kind,
+		Reason:    data.Reason,
+		BannedBy:  &actor.UserUuid,
+		CreatedAt: currentDate,
+		ExpiresAt: data.ExpiresAt,
+		IsActive:  true,
+	}
+
+	query = fmt.Sprintf(`
+		INSERT INTO %s (uuid, users_id, kind, reason, banned_by, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id
+	`, tableConstants.U_BANS)
+	if err = tx.QueryRow(query, ban.Uuid, user.Id, ban.Kind, ban.Reason, actor.UserId, currentDate, ban.ExpiresAt).Scan(&ban.Id); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Все сессии пользователя завершаются сразу, их токены доступа заносятся в список отозванных
+	tokens, err := deleteSessions(tx, "users_id = $1", user.Id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if err = denyAccessTokens(r.denylist, tokens); err != nil {
+		return nil, err
+	}
+
+	return &ban, nil
+}
+
+/* Снятие действующей блокировки пользователя */
+func (r *BanPostgres) Lift(actor *userModel.UserIdentityModel, data userModel.BanUserModel) (bool, error) {
+	user, err := r.user.Get("uuid", data.UsersUuid, true)
+	if err != nil {
+		return false, err
+	}
+
+	currentDate := time.Now()
+	query := fmt.Sprintf(`
+		UPDATE %s SET lifted_at = $1, lifted_by = $2
+		WHERE users_id = $3 AND lifted_at IS NULL AND (expires_at IS NULL OR expires_at > $1)
+	`, tableConstants.U_BANS)
+
+	result, err := r.db.Exec(query, currentDate, actor.UserId, user.Id)
+	if err != nil {
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if count == 0 {
+		return false, errors.New("Ошибка: пользователь не заблокирован")
+	}
+
+	return true, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	tableConstants "main-server/pkg/constant/table"
+
+	"github.com/jmoiron/sqlx"
+)
+
+/* Список отозванных токенов доступа в PostgreSQL (общий для всех экземпляров сервера) */
+type DenylistPostgres struct {
+	db *sqlx.DB
+}
+
+/* Создание нового экземпляра структуры DenylistPostgres */
+func NewDenylistPostgres(db *sqlx.DB) *DenylistPostgres {
+	return &DenylistPostgres{db: db}
+}
+
+func (r *DenylistPostgres) Add(tokenId string, expiresAt time.Time) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s (token_id, expires_at) VALUES ($1, $2)
+		ON CONFLICT (token_id) DO UPDATE SET expires_at = GREATEST(%[1]s.expires_at, EXCLUDED.expires_at)
+	`, tableConstants.U_TOKENS_DENYLIST)
+	if _, err := r.db.Exec(query, tokenId, expiresAt); err != nil {
+		return err
+	}
+
+	// Удаление записей о токенах, срок действия которых истёк
+	query = fmt.Sprintf("DELETE FROM %s WHERE expires_at <= $1", tableConstants.U_TOKENS_DENYLIST)
+	_, err := r.db.Exec(query, time.Now())
+
+	return err
+}
+
+func (r *DenylistPostgres) Contains(tokenId string, now time.Time) (bool, error) {
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE token_id = $1 AND expires_at > $2", tableConstants.U_TOKENS_DENYLIST)
+	if err := r.db.Get(&count, query, tokenId, now); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
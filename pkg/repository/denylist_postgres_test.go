@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	authConstants "main-server/pkg/constant/auth"
+	userModel "main-server/pkg/model/user"
+	"main-server/pkg/service/denylist"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/spf13/viper"
+)
+
+/* Токен доступа сессии с заданным временем жизни и его идентификатор (jti) */
+func newTestSessionToken(t *testing.T, ttl time.Duration) (string, string) {
+	t.Helper()
+
+	claims := newTokenClaims(testRootUuid, "auth-type-uuid", "session-uuid", nil, ttl)
+	token, err := signAccessClaims(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token, claims.Id
+}
+
+func newTestDenylist(t *testing.T) *denylist.MemoryStore {
+	t.Helper()
+
+	store := denylist.NewMemoryStore(time.Hour)
+	t.Cleanup(store.Stop)
+
+	return store
+}
+
+func TestDenyAccessToken(t *testing.T) {
+	viper.Set("token.signing_key_access", "access-secret")
+
+	active, activeId := newTestSessionToken(t, time.Hour)
+	expired, expiredId := newTestSessionToken(t, -time.Minute)
+
+	tests := []struct {
+		name    string
+		token   string
+		tokenId string
+		want    bool
+	}{
+		{name: "active token", token: active, tokenId: activeId, want: true},
+		{name: "expired token", token: expired, tokenId: expiredId, want: false},
+		{name: "malformed token", token: "invalid", tokenId: "invalid", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestDenylist(t)
+
+			if err := denyAccessToken(store, tt.token); err != nil {
+				t.Fatal(err)
+			}
+
+			if got, _ := store.Contains(tt.tokenId, time.Now()); got != tt.want {
+				t.Fatalf("revoked = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteSessionsDeniesAccessTokens(t *testing.T) {
+	viper.Set("token.signing_key_access", "access-secret")
+
+	first, firstId := newTestSessionToken(t, time.Hour)
+	second, secondId := newTestSessionToken(t, time.Hour)
+
+	db, mock := newTestDB(t)
+	store := newTestDenylist(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("DELETE FROM u_tokens WHERE users_id = $1 RETURNING access_token")).
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"access_token"}).AddRow(first).AddRow(second))
+
+	tokens, err := deleteSessions(db, "users_id = $1", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tokens) != 2 {
+		t.Fatalf("count = %d, want 2", len(tokens))
+	}
+
+	if err = denyAccessTokens(store, tokens); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tokenId := range []string{firstId, secondId} {
+		if got, _ := store.Contains(tokenId, time.Now()); !got {
+			t.Fatalf("token %s was not revoked", tokenId)
+		}
+	}
+}
+
+func TestRefreshDeniesPreviousAccessToken(t *testing.T) {
+	viper.Set("token.signing_key_access", "access-secret")
+	viper.Set("token.signing_key_refresh", "refresh-secret")
+
+	for _, commitErr := range []error{nil, errors.New("commit failed")} {
+		name := "committed"
+		if commitErr != nil {
+			name = "rolled back"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			testRefreshDeniesPreviousAccessToken(t, commitErr)
+		})
+	}
+}
+
+/* Обновление токенов сессии: предыдущий токен доступа отзывается только после фиксации транзакции */
+func testRefreshDeniesPreviousAccessToken(t *testing.T, commitErr error) {
+	previous, previousId := newTestSessionToken(t, time.Hour)
+	refresh, err := GenerateToken(testRootUuid, "auth-type-uuid", "session-uuid", nil, time.Hour, "refresh-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, mock := newTestDB(t)
+	store := newTestDenylist(t)
+	r := NewAuthPostgres(db, nil, UserPostgres{db: db}, nil, store)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM u_users WHERE id=$1")).WithArgs(10).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "uuid", "email", "password"}).AddRow(10, testRootUuid, "user@example.com", ""),
+	)
+	mock.ExpectQuery("SELECT id, uuid, users_id, kind").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "uuid", "users_id", "kind", "reason", "created_at", "expires_at"}),
+	)
+	mock.ExpectBegin()
+	mock.ExpectQuery("FOR UPDATE").WithArgs(refresh, 10, "session-uuid").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "uuid", "users_id", "access_token", "refresh_token", "user_agent", "ip", "created_at", "refreshed_at"}).
+			AddRow(1, "session-uuid", 10, previous, refresh, "", "", time.Now(), time.Now()),
+	)
+	mock.ExpectExec("INSERT INTO u_tokens_consumed").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE FROM u_tokens_consumed").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE u_tokens").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit().WillReturnError(commitErr)
+
+	data, err := r.Refresh(userModel.TokenLogoutDataModel{}, refresh, userModel.TokenOutputParse{
+		UsersId:     10,
+		UsersUuid:   testRootUuid,
+		SessionUuid: "session-uuid",
+		AuthType:    userModel.AuthTypeModel{Uuid: "auth-type-uuid", Value: authConstants.AUTH_TYPE_LOCAL},
+	})
+
+	// Сессия не обновлена - её текущий токен доступа остаётся действительным
+	if commitErr != nil {
+		if err == nil {
+			t.Fatal("refresh succeeded without commit")
+		}
+
+		if got, _ := store.Contains(previousId, time.Now()); got {
+			t.Fatal("access token was revoked although the session was not updated")
+		}
+		return
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data.AccessToken == "" || data.AccessToken == previous {
+		t.Fatal("access token was not rotated")
+	}
+
+	if got, _ := store.Contains(previousId, time.Now()); !got {
+		t.Fatal("previous access token was not revoked")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDenylistPostgres(t *testing.T) {
+	db, mock := newTestDB(t)
+	r := NewDenylistPostgres(db)
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	// Повторное занесение продлевает запись, а не сокращает её
+	mock.ExpectExec(regexp.QuoteMeta("GREATEST(u_tokens_denylist.expires_at, EXCLUDED.expires_at)")).
+		WithArgs("jti", expiresAt).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM u_tokens_denylist WHERE expires_at <= $1")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := r.Add("jti", expiresAt); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE token_id = $1 AND expires_at > $2")).
+		WithArgs("jti", now).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	got, err := r.Contains("jti", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !got {
+		t.Fatal("token is not revoked")
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -5,19 +5,28 @@ import (
 	"fmt"
 	tableConstants "main-server/pkg/constant/table"
 	rbacModel "main-server/pkg/model/rbac"
+	userModel "main-server/pkg/model/user"
+	"strconv"
 
+	"github.com/casbin/casbin/v2"
 	"github.com/jmoiron/sqlx"
+	uuid "github.com/satori/go.uuid"
+	"github.com/spf13/viper"
 )
 
 type DomainPostgres struct {
-	db *sqlx.DB
+	db       *sqlx.DB
+	enforcer *casbin.Enforcer
 }
 
 /*
 * Функция создания экземпляра сервиса
  */
-func NewDomainPostgres(db *sqlx.DB) *DomainPostgres {
-	return &DomainPostgres{db: db}
+func NewDomainPostgres(db *sqlx.DB, enforcer *casbin.Enforcer) *DomainPostgres {
+	return &DomainPostgres{
+		db:       db,
+		enforcer: enforcer,
+	}
 }
 
 /* Получение информации о домене */
@@ -46,3 +55,111 @@ func (r *DomainPostgres) Get(column string, value interface{}, check bool) (*rba
 
 	return &domains[len(domains)-1], err
 }
+
+/* Создание нового домена */
+func (r *DomainPostgres) Create(user *userModel.UserIdentityModel, data rbacModel.DomainCreateModel) (*rbacModel.DomainModel, error) {
+	check, err := r.Get("value", data.Value, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if check != nil {
+		return nil, errors.New("Ошибка: домен с данным значением уже существует!")
+	}
+
+	var domain rbacModel.DomainModel
+	query := fmt.Sprintf(`
+		INSERT INTO %s (uuid, value, description, users_id)
+		VALUES ($1, $2, $3, $4) RETURNING *
+	`, tableConstants.AC_DOMAINS)
+
+	if err = r.db.Get(&domain, query, uuid.NewV4().String(), data.Value, data.Description, user.UserId); err != nil {
+		return nil, err
+	}
+
+	return &domain, nil
+}
+
+/* Получение всех доменов */
+func (r *DomainPostgres) GetAll() (*rbacModel.DomainsModel, error) {
+	var domains []rbacModel.DomainModel
+	query := fmt.Sprintf("SELECT * FROM %s ORDER BY id", tableConstants.AC_DOMAINS)
+
+	if err := r.db.Select(&domains, query); err != nil {
+		return nil, err
+	}
+
+	return &rbacModel.DomainsModel{
+		Domains: domains,
+	}, nil
+}
+
+/* Обновление данных о домене */
+func (r *DomainPostgres) Update(data rbacModel.DomainUpdateModel) (*rbacModel.DomainModel, error) {
+	current, err := r.Get("uuid", data.Uuid, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if (current.Value == viper.GetString("domain")) && (current.Value != data.Value) {
+		return nil, errors.New("Ошибка: значение текущего домена системы не может быть изменено!")
+	}
+
+	var domain rbacModel.DomainModel
+	query := fmt.Sprintf(`
+		UPDATE %s SET value = $1, description = $2 WHERE id = $3 RETURNING *
+	`, tableConstants.AC_DOMAINS)
+
+	if err = r.db.Get(&domain, query, data.Value, data.Description, current.Id); err != nil {
+		return nil, err
+	}
+
+	return &domain, nil
+}
+
+/* Удаление домена (вместе с его ролями и правилами доступа) */
+func (r *DomainPostgres) Delete(domainUuid string) (bool, error) {
+	domain, err := r.Get("uuid", domainUuid, true)
+	if err != nil {
+		return false, err
+	}
+
+	if domain.Value == viper.GetString("domain") {
+		return false, errors.New("Ошибка: текущий домен системы не может быть удалён!")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE domains_id = $1", tableConstants.AC_ROLES)
+	if _, err = tx.Exec(query, domain.Id); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE id = $1", tableConstants.AC_DOMAINS)
+	if _, err = tx.Exec(query, domain.Id); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	// Правила группировки (g = user, role, domain) и политики (p = user, domain, object, action)
+	// удаляются после фиксации транзакции, чтобы её откат не рассинхронизировал правила и данные
+	r.enforcer.LoadPolicy()
+
+	if _, err = r.enforcer.RemoveFilteredGroupingPolicy(2, strconv.Itoa(domain.Id)); err != nil {
+		return false, err
+	}
+
+	if _, err = r.enforcer.RemoveFilteredPolicy(1, strconv.Itoa(domain.Id)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"html"
+	"net/mail"
+	"strings"
+	"time"
+
+	config "main-server/config"
+	authConstants "main-server/pkg/constant/auth"
+	tableConstants "main-server/pkg/constant/table"
+	userModel "main-server/pkg/model/user"
+	"main-server/pkg/service/denylist"
+	passwordService "main-server/pkg/service/password"
+
+	"github.com/jmoiron/sqlx"
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type EmailChangePostgres struct {
+	db       *sqlx.DB
+	user     *UserPostgres
+	denylist denylist.Store
+}
+
+/* Создание нового экземпляра структуры EmailChangePostgres */
+func NewEmailChangePostgres(db *sqlx.DB, user *UserPostgres, store denylist.Store) *EmailChangePostgres {
+	return &EmailChangePostgres{
+		db:       db,
+		user:     user,
+		denylist: store,
+	}
+}
+
+/*
+* Запрос на смену email-адреса. На новый адрес отправляется ссылка для подтверждения,
+* на старый - уведомление со ссылкой для отмены. Адрес меняется только после подтверждения
+ */
+func (r *EmailChangePostgres) Request(userId int, sessionUuid string, data userModel.EmailChangeRequestModel) (*userModel.EmailChangeModel, error) {
+	address, err := mail.ParseAddress(data.Email)
+	if err != nil || address.Address != strings.TrimSpace(data.Email) {
+		return nil, errors.New("Ошибка: некорректный email-адрес")
+	}
+
+	user, err := r.user.Get("id", userId, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(user.Email, address.Address) {
+		return nil, errors.New("Ошибка: новый email-адрес совпадает с текущим")
+	}
+
+	// Подтверждение владельца аккаунта текущим паролем (если он установлен)
+	hasPassword, err := hasLocalPassword(r.db, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasPassword {
+		if ok, _, err := passwordService.Verify(user.Password, data.Password); err != nil || !ok {
+			return nil, errors.New("Ошибка: неверный текущий пароль")
+		}
+	}
+
+	taken, err := r.user.Get("email", address.Address, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if taken != nil {
+		return nil, userModel.ErrEmailTaken
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	// Ограничение частоты отправки писем одному пользователю
+	var lastRequest sql.NullTime
+	query := fmt.Sprintf("SELECT MAX(created_at) FROM %s WHERE users_id = $1", tableConstants.U_EMAIL_CHANGES)
+	if err = tx.QueryRow(query, userId).Scan(&lastRequest); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	currentDate := time.Now()
+	if lastRequest.Valid && currentDate.Sub(lastRequest.Time) < authConstants.ACTIVATION_RESEND_INTERVAL {
+		tx.Rollback()
+		return nil, errors.New("Ошибка: письмо уже было отправлено, повторите попытку через минуту")
+	}
+
+	// Новый запрос аннулирует предыдущие неподтверждённые запросы
+	query = fmt.Sprintf(`
+		UPDATE %s SET cancelled_at = $1 WHERE users_id = $2 AND confirmed_at IS NULL AND cancelled_at IS NULL
+	`, tableConstants.U_EMAIL_CHANGES)
+	if _, err = tx.Exec(query, currentDate, userId); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var session *string
+	if sessionUuid != "" {
+		session = &sessionUuid
+	}
+
+	change := userModel.EmailChangeModel{
+		Uuid:        uuid.NewV4().String(),
+		UsersId:     userId,
+		OldEmail:    user.Email,
+		NewEmail:    address.Address,
+		ConfirmLink: uuid.NewV4().String(),
+		CancelLink:  uuid.NewV4().String(),
+		SessionUuid: session,
+		CreatedAt:   currentDate,
+		ExpiresAt:   currentDate.Add(config.AppActivationPolicy.LinkTTL),
+	}
+
+	query = fmt.Sprintf(`
+		INSERT INTO %s (uuid, users_id, old_email, new_email, confirm_link, cancel_link, session_uuid, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id
+	`, tableConstants.U_EMAIL_CHANGES)
+	err = tx.QueryRow(query,
+		change.Uuid, change.UsersId, change.OldEmail, change.NewEmail, change.ConfirmLink,
+		change.CancelLink, change.SessionUuid, change.CreatedAt, change.ExpiresAt,
+	).Scan(&change.Id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err = sendEmailChangeConfirmEmail(change.NewEmail, change.ConfirmLink); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// Уведомление на старый адрес не должно мешать смене адреса, если он уже недоступен
+	if err = sendEmailChangeNoticeEmail(change.OldEmail, change.NewEmail, change.CancelLink); err != nil {
+		logrus.Errorf("failed to send email change notice to user %d: %s", userId, err.Error())
+	}
+
+	return &change, nil
+}
+
+/* Подтверждение смены email-адреса по ссылке из письма на новый адрес */
+func (r *EmailChangePostgres) Confirm(link string) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	change, err := getEmailChange(tx, "confirm_link", link)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if time.Now().After(change.ExpiresAt) {
+		tx.Rollback()
+		return false, userModel.ErrEmailChangeLinkExpired
+	}
+
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE LOWER(email) = LOWER($1) AND id <> $2", tableConstants.U_USERS)
+	if err = tx.QueryRow(query, change.NewEmail, change.UsersId).Scan(&count); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if count > 0 {
+		tx.Rollback()
+		return false, userModel.ErrEmailTaken
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET email = $1 WHERE id = $2 AND email = $3", tableConstants.U_USERS)
+	result, err := tx.Exec(query, change.NewEmail, change.UsersId, change.OldEmail)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	// Текущий адрес был изменён другим способом после создания запроса
+	if affected, err := result.RowsAffected(); err != nil || affected <= 0 {
+		tx.Rollback()
+		return false, userModel.ErrEmailChangeLinkUsed
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET confirmed_at = NOW() WHERE id = $1", tableConstants.U_EMAIL_CHANGES)
+	if _, err = tx.Exec(query, change.Id); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	// Переход по ссылке подтверждает владение новым адресом
+	if err = activateUser(tx, change.UsersId); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	// Завершение всех сессий, кроме той, из которой была запрошена смена адреса
+	tokens, err := deleteSessions(tx, "users_id = $1 AND uuid IS DISTINCT FROM $2", change.UsersId, change.SessionUuid)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	if err = denyAccessTokens(r.denylist, tokens); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* Отмена смены email-адреса по ссылке из уведомления на старый адрес */
+func (r *EmailChangePostgres) Cancel(link string) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	change, err := getEmailChange(tx, "cancel_link", link)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET cancelled_at = NOW() WHERE id = $1", tableConstants.U_EMAIL_CHANGES)
+	if _, err = tx.Exec(query, change.Id); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* Получение неподтверждённого и неотменённого запроса на смену email-адреса по ссылке */
+func getEmailChange(tx *sql.Tx, column, link string) (*userModel.EmailChangeModel, error) {
+	var change userModel.EmailChangeModel
+	query := fmt.Sprintf(`
+		SELECT id, users_id, old_email, new_email, session_uuid, expires_at, confirmed_at, cancelled_at
+		FROM %s WHERE %s = $1 FOR UPDATE
+	`, tableConstants.U_EMAIL_CHANGES, column)
+	err := tx.QueryRow(query, link).Scan(
+		&change.Id, &change.UsersId, &change.OldEmail, &change.NewEmail, &change.SessionUuid,
+		&change.ExpiresAt, &change.ConfirmedAt, &change.CancelledAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, userModel.ErrEmailChangeLinkNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if change.ConfirmedAt != nil || change.CancelledAt != nil {
+		return nil, userModel.ErrEmailChangeLinkUsed
+	}
+
+	return &change, nil
+}
+
+/* Отправка письма со ссылкой для подтверждения нового email-адреса */
+func sendEmailChangeConfirmEmail(userEmail, link string) error {
+	return sendLinkEmail(userEmail, "Смена email-адреса \"Rental housing\"", linkEmail{
+		Heading: "Подтверждение нового E-mail",
+		Text: `<br><text>Вы получили это письмо, так как Ваш почтовый адрес был указан в качестве нового адреса аккаунта в приложении "Rental housing".</text>
+			</br><text>Чтобы подтвердить смену адреса перейдите по ссылке: </text></br>`,
+		Button: "Подтвердить E-mail",
+		Link:   viper.GetString("api_url") + "/auth/email/confirm/" + link,
+		Note: fmt.Sprintf(`<text>Ссылка действует %d ч. и может быть использована только один раз.</text>
+			</br><text>После подтверждения все остальные сессии аккаунта будут завершены.</text>
+			</br><text>Если Вы не запрашивали смену адреса, то не отвечайте на данное сообщение.</text>`,
+			int(config.AppActivationPolicy.LinkTTL.Hours())),
+	})
+}
+
+/* Отправка уведомления о смене email-адреса со ссылкой для отмены */
+func sendEmailChangeNoticeEmail(userEmail, newEmail, link string) error {
+	return sendLinkEmail(userEmail, "Смена email-адреса \"Rental housing\"", linkEmail{
+		Heading: "Запрошена смена E-mail",
+		Text: fmt.Sprintf(`<br><text>Для Вашего аккаунта в приложении "Rental housing" запрошена смена почтового адреса на %s.</text>
+			</br><text>Если Вы не запрашивали смену адреса, отмените её и смените пароль: </text></br>`, html.EscapeString(newEmail)),
+		Button: "Отменить смену E-mail",
+		Link:   viper.GetString("api_url") + "/auth/email/cancel/" + link,
+		Note:   `<text>Адрес будет изменён только после подтверждения по ссылке, отправленной на новый адрес.</text>`,
+	})
+}
@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	authConstants "main-server/pkg/constant/auth"
+	tableConstants "main-server/pkg/constant/table"
+	userModel "main-server/pkg/model/user"
+	authService "main-server/pkg/service/auth"
+
+	"github.com/jmoiron/sqlx"
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type IdentityPostgres struct {
+	db *sqlx.DB
+}
+
+/* Создание нового экземпляра структуры IdentityPostgres */
+func NewIdentityPostgres(db *sqlx.DB) *IdentityPostgres {
+	return &IdentityPostgres{db: db}
+}
+
+/* Получение всех внешних учётных записей пользователя */
+func (r *IdentityPostgres) GetAll(userId int) (*userModel.IdentitiesModel, error) {
+	var identities []userModel.IdentityModel
+	query := fmt.Sprintf(`
+		SELECT ti.*, ta.value AS provider FROM %s ti
+		INNER JOIN %s ta ON ta.id = ti.auth_types_id
+		WHERE ti.users_id = $1 ORDER BY ti.created_at
+	`, tableConstants.U_IDENTITIES, tableConstants.U_AUTH_TYPES)
+
+	if err := r.db.Select(&identities, query, userId); err != nil {
+		return nil, err
+	}
+
+	return &userModel.IdentitiesModel{
+		Identities: identities,
+	}, nil
+}
+
+/* Привязка внешней учётной записи к пользователю по коду авторизации провайдера */
+func (r *IdentityPostgres) Link(userId int, providerName, code string) (*userModel.IdentityModel, error) {
+	provider, err := authService.GetProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	token, err := provider.Exchange(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	userData, err := provider.GetUserInfo(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Токен провайдера нужен только для получения сведений о пользователе
+	if err := provider.Revoke(ctx, token); err != nil {
+		logrus.Warnf("Ошибка при отзыве токена %s: %s", providerName, err.Error())
+	}
+
+	authType, err := getAuthType(r.db, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := getIdentity(r.db, authType.Id, userData.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if identity != nil {
+		if identity.UsersId == userId {
+			return nil, errors.New("Ошибка: данная учётная запись уже привязана к пользователю")
+		}
+
+		return nil, errors.New("Ошибка: данная учётная запись привязана к другому пользователю")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	if identity, err = createIdentity(tx, userId, authType, userData); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+/* Отвязка внешней учётной записи (у пользователя должен остаться хотя бы один способ входа) */
+func (r *IdentityPostgres) Unlink(userId int, identityUuid string) (bool, error) {
+	var identities []userModel.IdentityModel
+	query := fmt.Sprintf(`
+		SELECT ti.*, ta.value AS provider FROM %s ti
+		INNER JOIN %s ta ON ta.id = ti.auth_types_id
+		WHERE ti.users_id = $1
+	`, tableConstants.U_IDENTITIES, tableConstants.U_AUTH_TYPES)
+
+	if err := r.db.Select(&identities, query, userId); err != nil {
+		return false, err
+	}
+
+	var identity *userModel.IdentityModel
+	for index, item := range identities {
+		if item.Uuid == identityUuid {
+			identity = &identities[index]
+		}
+	}
+
+	if identity == nil {
+		return false, errors.New("Ошибка: внешней учётной записи с данным идентификатором не существует!")
+	}
+
+	sameType := 0
+	for _, item := range identities {
+		if item.AuthTypesId == identity.AuthTypesId {
+			sameType++
+		}
+	}
+
+	methods, err := countLoginMethods(r.db, userId)
+	if err != nil {
+		return false, err
+	}
+
+	if methods <= 1 {
+		return false, errors.New("Ошибка: нельзя отвязать единственный способ входа в систему")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE id = $1", tableConstants.U_IDENTITIES)
+	if _, err = tx.Exec(query, identity.Id); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	// Тип авторизации отвязывается вместе с последней учётной записью данного провайдера
+	if sameType <= 1 {
+		query = fmt.Sprintf("DELETE FROM %s WHERE users_id = $1 AND auth_types_id = $2", tableConstants.U_USERS_AUTH_TYPES)
+		if _, err = tx.Exec(query, userId, identity.AuthTypesId); err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* Получение типа авторизации по его значению */
+func getAuthType(db *sqlx.DB, value string) (*userModel.AuthTypeModel, error) {
+	var authTypes []userModel.AuthTypeModel
+	query := fmt.Sprintf("SELECT * FROM %s WHERE value = $1 LIMIT 1", tableConstants.U_AUTH_TYPES)
+
+	if err := db.Select(&authTypes, query, value); err != nil {
+		return nil, err
+	}
+
+	if len(authTypes) <= 0 {
+		return nil, errors.New("Ошибка: тип авторизации " + value + " не зарегистрирован")
+	}
+
+	return &authTypes[0], nil
+}
+
+/* Поиск внешней учётной записи по идентификатору пользователя у провайдера */
+func getIdentity(db *sqlx.DB, authTypesId int, subject string) (*userModel.IdentityModel, error) {
+	var identities []userModel.IdentityModel
+	query := fmt.Sprintf(`
+		SELECT ti.*, ta.value AS provider FROM %s ti
+		INNER JOIN %s ta ON ta.id = ti.auth_types_id
+		WHERE ti.auth_types_id = $1 AND ti.subject = $2 LIMIT 1
+	`, tableConstants.U_IDENTITIES, tableConstants.U_AUTH_TYPES)
+
+	if err := db.Select(&identities, query, authTypesId, subject); err != nil {
+		return nil, err
+	}
+
+	if len(identities) <= 0 {
+		return nil, nil
+	}
+
+	return &identities[0], nil
+}
+
+/* Проверка наличия у пользователя типа авторизации */
+func hasAuthType(db *sqlx.DB, userId int, value string) (bool, error) {
+	var count int
+	query := fmt.Sprintf(`
+		SELECT COUNT(*) FROM %s tu
+		INNER JOIN %s ta ON ta.id = tu.auth_types_id
+		WHERE tu.users_id = $1 AND ta.value = $2
+	`, tableConstants.U_USERS_AUTH_TYPES, tableConstants.U_AUTH_TYPES)
+
+	if err := db.Get(&count, query, userId, value); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+/* Проверка наличия у пользователя локального пароля */
+func hasLocalPassword(db *sqlx.DB, userId int) (bool, error) {
+	has, err := hasAuthType(db, userId, authConstants.AUTH_TYPE_LOCAL)
+	if err != nil || !has {
+		return false, err
+	}
+
+	var password string
+	query := fmt.Sprintf("SELECT password FROM %s WHERE id = $1", tableConstants.U_USERS)
+	if err := db.Get(&password, query, userId); err != nil {
+		return false, err
+	}
+
+	return password != "", nil
+}
+
+/* Подсчёт способов входа пользователя: пароль, внешние учётные записи и ключи доступа */
+func countLoginMethods(db *sqlx.DB, userId int) (int, error) {
+	hasPassword, err := hasLocalPassword(db, userId)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	query := fmt.Sprintf(`
+		SELECT (SELECT COUNT(*) FROM %s WHERE users_id = $1) + (SELECT COUNT(*) FROM %s WHERE users_id = $1)
+	`, tableConstants.U_IDENTITIES, tableConstants.U_WEBAUTHN_CREDENTIALS)
+	if err := db.Get(&count, query, userId); err != nil {
+		return 0, err
+	}
+
+	if hasPassword {
+		count++
+	}
+
+	return count, nil
+}
+
+/* Создание внешней учётной записи пользователя в рамках транзакции */
+func createIdentity(tx *sql.Tx, userId int, authType *userModel.AuthTypeModel, data userModel.UserRegisterOAuth2Model) (*userModel.IdentityModel, error) {
+	if data.Subject == "" {
+		return nil, errors.New("Ошибка: провайдер не передал идентификатор пользователя")
+	}
+
+	var email *string
+	if data.Email != "" {
+		email = &data.Email
+	}
+
+	identity := userModel.IdentityModel{
+		Uuid:        uuid.NewV4().String(),
+		UsersId:     userId,
+		AuthTypesId: authType.Id,
+		Provider:    authType.Value,
+		Subject:     data.Subject,
+		Email:       email,
+		CreatedAt:   time.Now(),
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (uuid, users_id, auth_types_id, subject, email, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`, tableConstants.U_IDENTITIES)
+	if err := tx.QueryRow(query, identity.Uuid, userId, authType.Id, data.Subject, email, identity.CreatedAt).Scan(&identity.Id); err != nil {
+		return nil, err
+	}
+
+	// Связь пользователя с типом авторизации (если её ещё нет)
+	query = fmt.Sprintf(`INSERT INTO %s (users_id, auth_types_id)
+		SELECT $1, $2 WHERE NOT EXISTS (SELECT 1 FROM %s WHERE users_id = $1 AND auth_types_id = $2)`,
+		tableConstants.U_USERS_AUTH_TYPES, tableConstants.U_USERS_AUTH_TYPES)
+	if _, err := tx.Exec(query, userId, authType.Id); err != nil {
+		return nil, err
+	}
+
+	return &identity, nil
+}
@@ -0,0 +1,579 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	config "main-server/config"
+	mfaConstants "main-server/pkg/constant/mfa"
+	tableConstants "main-server/pkg/constant/table"
+	rbacModel "main-server/pkg/model/rbac"
+	userModel "main-server/pkg/model/user"
+	mfaService "main-server/pkg/service/mfa"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	uuid "github.com/satori/go.uuid"
+	"github.com/spf13/viper"
+	"golang.org/x/oauth2"
+)
+
+type MfaPostgres struct {
+	db       *sqlx.DB
+	enforcer *casbin.Enforcer
+	user     *UserPostgres
+	domain   *DomainPostgres
+	authType *AuthTypePostgres
+}
+
+/* Создание нового экземпляра структуры MfaPostgres */
+func NewMfaPostgres(
+	db *sqlx.DB,
+	enforcer *casbin.Enforcer,
+	user *UserPostgres,
+	domain *DomainPostgres,
+	authType *AuthTypePostgres,
+) *MfaPostgres {
+	return &MfaPostgres{
+		db:       db,
+		enforcer: enforcer,
+		user:     user,
+		domain:   domain,
+		authType: authType,
+	}
+}
+
+/* Получение состояния двухфакторной аутентификации пользователя */
+func (r *MfaPostgres) GetStatus(userId int) (*userModel.MfaStatusModel, error) {
+	totp, err := r.getTotp(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	required, err := r.IsRequired(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var codesLeft int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE users_id = $1 AND used_at IS NULL", tableConstants.U_MFA_RECOVERY_CODES)
+	if err := r.db.Get(&codesLeft, query, userId); err != nil {
+		return nil, err
+	}
+
+	return &userModel.MfaStatusModel{
+		Enabled:           totp != nil && totp.IsEnabled,
+		Required:          required,
+		RecoveryCodesLeft: codesLeft,
+	}, nil
+}
+
+/* Проверка, требует ли хотя бы одна из ролей пользователя двухфакторной аутентификации */
+func (r *MfaPostgres) IsRequired(userId int) (bool, error) {
+	if len(config.AppMfaPolicy.RequiredRoles) <= 0 {
+		return false, nil
+	}
+
+	domain, err := r.domain.Get("value", viper.GetString("domain"), true)
+	if err != nil {
+		return false, err
+	}
+
+	roles, err := r.enforcer.GetRolesForUser(strconv.Itoa(userId), strconv.Itoa(domain.Id))
+	if err != nil {
+		return false, err
+	}
+
+	// Роль может быть выдана в контексте объекта (id;uuid) - учитывается только идентификатор роли
+	var rolesId []string
+	for _, item := range roles {
+		rolesId = append(rolesId, strings.Split(item, rbacModel.Separator)[0])
+	}
+
+	if len(rolesId) <= 0 {
+		return false, nil
+	}
+
+	var values []string
+	query := fmt.Sprintf("SELECT value FROM %s WHERE id::TEXT = ANY($1)", tableConstants.AC_ROLES)
+	if err := r.db.Select(&values, query, pq.Array(rolesId)); err != nil {
+		return false, err
+	}
+
+	for _, value := range values {
+		if config.AppMfaPolicy.IsRequired(value) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+/*
+* Проверка необходимости второго фактора при входе. Если он требуется, возвращается
+* короткоживущий токен ожидания, иначе nil. Способ первого входа (authType) и токены внешнего
+* провайдера (token, nil - вход без провайдера) сохраняются до проверки второго фактора
+ */
+func (r *MfaPostgres) Challenge(userId int, usersUuid, authType string, token *oauth2.Token) (*userModel.MfaPendingModel, error) {
+	totp, err := r.getTotp(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := totp != nil && totp.IsEnabled
+
+	required, err := r.IsRequired(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !enabled && !required {
+		return nil, nil
+	}
+
+	mfaToken, err := r.createPending(userId, usersUuid, authType, token)
+	if err != nil {
+		return nil, err
+	}
+
+	methods := []string{}
+	if enabled {
+		methods = append(methods, mfaConstants.METHOD_TOTP, mfaConstants.METHOD_RECOVERY)
+	}
+
+	return &userModel.MfaPendingModel{
+		MfaRequired:    true,
+		MfaToken:       mfaToken,
+		Methods:        methods,
+		EnrollRequired: !enabled,
+	}, nil
+}
+
+/* Генерация нового секрета TOTP (до подтверждения кодом второй фактор не включается) */
+func (r *MfaPostgres) SetupTotp(userId int) (*userModel.MfaTotpSetupModel, error) {
+	user, err := r.user.Get("id", userId, true)
+	if err != nil {
+		return nil, err
+	}
+
+	totp, err := r.getTotp(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if totp != nil && totp.IsEnabled {
+		return nil, errors.New("Ошибка: двухфакторная аутентификация уже подключена")
+	}
+
+	secret, err := mfaService.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (users_id, secret, is_enabled, last_used_step, created_at) VALUES ($1, $2, FALSE, 0, $3)
+		ON CONFLICT (users_id) DO UPDATE SET secret = EXCLUDED.secret, last_used_step = 0, created_at = EXCLUDED.created_at
+	`, tableConstants.U_MFA_TOTP)
+	if _, err := r.db.Exec(query, userId, secret, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return &userModel.MfaTotpSetupModel{
+		Secret: secret,
+		Uri:    mfaService.ProvisioningURI(config.AppMfaPolicy.Issuer, user.Email, secret),
+	}, nil
+}
+
+/* Включение второго фактора после проверки первого кода из приложения-аутентификатора */
+func (r *MfaPostgres) EnableTotp(userId int, code string) (*userModel.MfaRecoveryCodesModel, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := enableTotp(tx, userId, code)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+/* Отключение второго фактора (недоступно, если он обязателен для роли пользователя) */
+func (r *MfaPostgres) DisableTotp(userId int, code string) (bool, error) {
+	required, err := r.IsRequired(userId)
+	if err != nil {
+		return false, err
+	}
+
+	if required {
+		return false, errors.New("Ошибка: двухфакторная аутентификация обязательна для роли пользователя")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	if err = verifyCode(tx, userId, code); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE users_id = $1", tableConstants.U_MFA_TOTP)
+	if _, err = tx.Exec(query, userId); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE users_id = $1", tableConstants.U_MFA_RECOVERY_CODES)
+	if _, err = tx.Exec(query, userId); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* Выпуск нового набора кодов восстановления (старые коды перестают действовать) */
+func (r *MfaPostgres) RegenerateRecoveryCodes(userId int, code string) (*userModel.MfaRecoveryCodesModel, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = verifyCode(tx, userId, code); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	codes, err := replaceRecoveryCodes(tx, userId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+/* Создание токена ожидания второго фактора и сохранение незавершённого входа по его идентификатору */
+func (r *MfaPostgres) createPending(userId int, usersUuid, authType string, token *oauth2.Token) (string, error) {
+	tokenId := uuid.NewV4().String()
+	expiresAt := time.Now().Add(mfaConstants.TOKEN_TTL_MFA)
+
+	mfaToken, err := GenerateMfaToken(usersUuid, tokenId, expiresAt)
+	if err != nil {
+		return "", err
+	}
+
+	var accessApi, refreshApi *string
+	if token != nil {
+		accessApi, refreshApi = &token.AccessToken, &token.RefreshToken
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (token_id, users_id, auth_type, access_api, refresh_api, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, tableConstants.U_MFA_PENDING)
+	if _, err = r.db.Exec(query, tokenId, userId, authType, accessApi, refreshApi, expiresAt); err != nil {
+		return "", err
+	}
+
+	// Удаление незавершённых входов, срок действия токенов которых истёк
+	query = fmt.Sprintf("DELETE FROM %s WHERE expires_at <= $1", tableConstants.U_MFA_PENDING)
+	if _, err = r.db.Exec(query, time.Now()); err != nil {
+		return "", err
+	}
+
+	return mfaToken, nil
+}
+
+/*
+* Использование токена ожидания второго фактора: запись о незавершённом входе удаляется,
+* поэтому по одному токену создаётся не более одной сессии. При откате транзакции
+* (например, из-за неверного кода) токен остаётся действительным
+ */
+func consumePending(tx *sql.Tx, token userModel.MfaTokenOutputParse) (*userModel.MfaPendingLoginModel, error) {
+	var pending userModel.MfaPendingLoginModel
+	query := fmt.Sprintf(`
+		DELETE FROM %s WHERE token_id = $1 AND users_id = $2 AND expires_at > $3
+		RETURNING auth_type, access_api, refresh_api
+	`, tableConstants.U_MFA_PENDING)
+
+	err := tx.QueryRow(query, token.TokenId, token.UsersId, time.Now()).Scan(&pending.AuthType, &pending.AccessApi, &pending.RefreshApi)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("Ошибка: токен уже был использован или просрочен, повторите вход")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &pending, nil
+}
+
+/* Завершение входа: проверка второго фактора и создание сессии пользователя */
+func (r *MfaPostgres) Login(token userModel.MfaTokenOutputParse, code string, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	pending, err := consumePending(tx, token)
+	if err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	if err = verifyCode(tx, token.UsersId, code); err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	authData, err := r.createPendingSession(tx, token.UsersId, pending, session)
+	if err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	return authData, tx.Commit()
+}
+
+/* Завершение входа с обязательным подключением второго фактора */
+func (r *MfaPostgres) EnrollLogin(token userModel.MfaTokenOutputParse, code string, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, *userModel.MfaRecoveryCodesModel, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.UserAuthDataModel{}, nil, err
+	}
+
+	pending, err := consumePending(tx, token)
+	if err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, nil, err
+	}
+
+	codes, err := enableTotp(tx, token.UsersId, code)
+	if err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, nil, err
+	}
+
+	authData, err := r.createPendingSession(tx, token.UsersId, pending, session)
+	if err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return userModel.UserAuthDataModel{}, nil, err
+	}
+
+	return authData, codes, nil
+}
+
+/* Получение секрета TOTP пользователя */
+func (r *MfaPostgres) getTotp(userId int) (*userModel.MfaTotpModel, error) {
+	var items []userModel.MfaTotpModel
+	query := fmt.Sprintf("SELECT * FROM %s WHERE users_id = $1", tableConstants.U_MFA_TOTP)
+
+	if err := r.db.Select(&items, query, userId); err != nil {
+		return nil, err
+	}
+
+	if len(items) <= 0 {
+		return nil, nil
+	}
+
+	return &items[0], nil
+}
+
+/* Создание сессии пользователя с типом авторизации первого входа (в том числе с токенами внешнего провайдера) */
+func (r *MfaPostgres) createPendingSession(
+	tx *sql.Tx, userId int, pending *userModel.MfaPendingLoginModel, session userModel.SessionInfoModel,
+) (userModel.UserAuthDataModel, error) {
+	user, err := r.user.Get("id", userId, true)
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	authType, err := r.authType.Get("value", pending.AuthType, true)
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	return createSession(tx, user.Id, user.Uuid, authType.Uuid, pending.AccessApi, pending.RefreshApi, session)
+}
+
+/* Получение секрета TOTP пользователя с блокировкой строки до конца транзакции */
+func getTotpForUpdate(tx *sql.Tx, userId int) (*userModel.MfaTotpModel, error) {
+	var totp userModel.MfaTotpModel
+	query := fmt.Sprintf(`
+		SELECT id, users_id, secret, is_enabled, last_used_step FROM %s WHERE users_id = $1 FOR UPDATE
+	`, tableConstants.U_MFA_TOTP)
+
+	err := tx.QueryRow(query, userId).Scan(&totp.Id, &totp.UsersId, &totp.Secret, &totp.IsEnabled, &totp.LastUsedStep)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &totp, nil
+}
+
+/* Проверка кода TOTP и фиксация использованного интервала */
+func checkTotp(tx *sql.Tx, totp *userModel.MfaTotpModel, code string) (bool, error) {
+	step, ok := mfaService.Validate(totp.Secret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return false, nil
+	}
+
+	if step <= totp.LastUsedStep {
+		return false, errors.New("Ошибка: данный код уже был использован, дождитесь следующего")
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET last_used_step = $1 WHERE id = $2", tableConstants.U_MFA_TOTP)
+	if _, err := tx.Exec(query, step, totp.Id); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* Проверка второго фактора: кода TOTP или одноразового кода восстановления */
+func verifyCode(tx *sql.Tx, userId int, code string) error {
+	totp, err := getTotpForUpdate(tx, userId)
+	if err != nil {
+		return err
+	}
+
+	if totp == nil || !totp.IsEnabled {
+		return errors.New("Ошибка: двухфакторная аутентификация не подключена")
+	}
+
+	ok, err := checkTotp(tx, totp, code)
+	if err != nil || ok {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s SET used_at = $1 WHERE users_id = $2 AND code_hash = $3 AND used_at IS NULL
+	`, tableConstants.U_MFA_RECOVERY_CODES)
+
+	result, err := tx.Exec(query, time.Now(), userId, hashToken(mfaService.NormalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+
+	if count, err := result.RowsAffected(); err != nil || count <= 0 {
+		return userModel.ErrInvalidMfaCode
+	}
+
+	return nil
+}
+
+/* Включение второго фактора в рамках транзакции */
+func enableTotp(tx *sql.Tx, userId int, code string) (*userModel.MfaRecoveryCodesModel, error) {
+	totp, err := getTotpForUpdate(tx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if totp == nil {
+		return nil, errors.New("Ошибка: сначала необходимо получить секрет для приложения-аутентификатора")
+	}
+
+	if totp.IsEnabled {
+		return nil, errors.New("Ошибка: двухфакторная аутентификация уже подключена")
+	}
+
+	ok, err := checkTotp(tx, totp, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, userModel.ErrInvalidMfaCode
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET is_enabled = TRUE, enabled_at = $1 WHERE id = $2", tableConstants.U_MFA_TOTP)
+	if _, err = tx.Exec(query, time.Now(), totp.Id); err != nil {
+		return nil, err
+	}
+
+	return replaceRecoveryCodes(tx, userId)
+}
+
+/* Замена кодов восстановления пользователя новым набором */
+func replaceRecoveryCodes(tx *sql.Tx, userId int) (*userModel.MfaRecoveryCodesModel, error) {
+	codes, err := mfaService.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE users_id = $1", tableConstants.U_MFA_RECOVERY_CODES)
+	if _, err = tx.Exec(query, userId); err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf("INSERT INTO %s (users_id, code_hash) VALUES ($1, $2)", tableConstants.U_MFA_RECOVERY_CODES)
+	for _, code := range codes {
+		if _, err = tx.Exec(query, userId, hashToken(mfaService.NormalizeRecoveryCode(code))); err != nil {
+			return nil, err
+		}
+	}
+
+	return &userModel.MfaRecoveryCodesModel{
+		Codes: codes,
+	}, nil
+}
+
+/* Токен ожидания второго фактора */
+type tokenMfaClaims struct {
+	jwt.StandardClaims
+	UsersId string `json:"users_id"` // ID пользователя
+}
+
+/*
+* Генерация токена ожидания второго фактора. Подписывается отдельным ключом,
+* поэтому не может быть использован в качестве токена доступа
+ */
+func GenerateMfaToken(usersUuid, tokenId string, expiresAt time.Time) (string, error) {
+	signingKey := viper.GetString("token.signing_key_mfa")
+	if signingKey == "" {
+		return "", errors.New("Ошибка: не задан ключ подписи token.signing_key_mfa")
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenMfaClaims{
+		jwt.StandardClaims{
+			Id:        tokenId,
+			Audience:  mfaConstants.AUDIENCE_MFA,
+			ExpiresAt: expiresAt.Unix(),
+			IssuedAt:  time.Now().Unix(),
+		},
+		usersUuid,
+	})
+
+	return token.SignedString([]byte(signingKey))
+}
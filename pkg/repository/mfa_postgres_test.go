@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	authConstants "main-server/pkg/constant/auth"
+	userModel "main-server/pkg/model/user"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/spf13/viper"
+)
+
+func TestMfaLoginPendingSession(t *testing.T) {
+	viper.Set("token.signing_key_access", "access-secret")
+	viper.Set("token.signing_key_refresh", "refresh-secret")
+
+	token := userModel.MfaTokenOutputParse{UsersId: 10, UsersUuid: testRootUuid, TokenId: "jti"}
+	pendingColumns := []string{"auth_type", "access_api", "refresh_api"}
+
+	t.Run("session keeps provider login", func(t *testing.T) {
+		db, mock := newTestDB(t)
+		r := NewMfaPostgres(db, nil, &UserPostgres{db: db}, nil, NewAuthTypePostgres(db))
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("DELETE FROM u_mfa_pending WHERE token_id = $1 AND users_id = $2")).
+			WithArgs("jti", 10, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows(pendingColumns).AddRow(authConstants.AUTH_TYPE_GOOGLE, "provider-access", "provider-refresh"))
+
+		// Код восстановления вместо кода TOTP
+		mock.ExpectQuery("FOR UPDATE").WithArgs(10).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "users_id", "secret", "is_enabled", "last_used_step"}).AddRow(1, 10, "JBSWY3DPEHPK3PXP", true, 0),
+		)
+		mock.ExpectExec("UPDATE u_mfa_recovery_codes").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM u_users WHERE id=$1")).WithArgs(10).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "uuid", "email", "password"}).AddRow(10, testRootUuid, "user@example.com", ""),
+		)
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM u_auth_types WHERE value=$1")).WithArgs(authConstants.AUTH_TYPE_GOOGLE).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "uuid", "value"}).AddRow(2, "google-uuid", authConstants.AUTH_TYPE_GOOGLE))
+		mock.ExpectQuery("SELECT id, uuid, users_id, kind").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "uuid", "users_id", "kind", "reason", "created_at", "expires_at"}),
+		)
+		mock.ExpectExec("INSERT INTO u_tokens").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		data, err := r.Login(token, "recovery-code", userModel.SessionInfoModel{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Сессия создаётся для провайдера первого входа и сохраняет его токен
+		claims := &tokenClaims{}
+		if _, _, err = new(jwt.Parser).ParseUnverified(data.AccessToken, claims); err != nil {
+			t.Fatal(err)
+		}
+
+		if claims.AuthTypesId != "google-uuid" || claims.TokenApi == nil || *claims.TokenApi != "provider-access" {
+			t.Fatalf("unexpected claims %+v", claims)
+		}
+
+		if err = mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("token is single-use", func(t *testing.T) {
+		db, mock := newTestDB(t)
+		r := NewMfaPostgres(db, nil, &UserPostgres{db: db}, nil, NewAuthTypePostgres(db))
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("DELETE FROM u_mfa_pending")).WillReturnRows(sqlmock.NewRows(pendingColumns))
+		mock.ExpectRollback()
+
+		if _, err := r.Login(token, "123456", userModel.SessionInfoModel{}); err == nil {
+			t.Fatal("consumed token created a session")
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
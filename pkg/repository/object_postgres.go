@@ -0,0 +1,414 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	actionConstant "main-server/pkg/constant/action"
+	objectConstant "main-server/pkg/constant/object"
+	roleConstant "main-server/pkg/constant/role"
+	tableConstant "main-server/pkg/constant/table"
+	rbacModel "main-server/pkg/model/rbac"
+	userModel "main-server/pkg/model/user"
+	"main-server/pkg/util"
+	"strconv"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+type ObjectPostgres struct {
+	db       *sqlx.DB
+	enforcer *casbin.Enforcer
+}
+
+/* Создание нового экземпляра структуры ObjectPostgres */
+func NewObjectPostgres(db *sqlx.DB, enforcer *casbin.Enforcer) *ObjectPostgres {
+	return &ObjectPostgres{
+		db:       db,
+		enforcer: enforcer,
+	}
+}
+
+/* Получение информации об объекте */
+func (r *ObjectPostgres) Get(column string, value interface{}, check bool) (*rbacModel.ObjectDbModel, error) {
+	var objects []rbacModel.ObjectDbModel
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s=$1", tableConstant.AC_OBJECTS, column)
+
+	var err error
+
+	switch value.(type) {
+	case int:
+		err = r.db.Select(&objects, query, value.(int))
+		break
+	case string:
+		err = r.db.Select(&objects, query, value.(string))
+		break
+	}
+
+	if len(objects) <= 0 {
+		if check {
+			return nil, errors.New(fmt.Sprintf("Ошибка: объекта по запросу %s:%s не найдено!", column, value))
+		}
+
+		return nil, nil
+	}
+
+	return &objects[len(objects)-1], err
+}
+
+/* Получение информации о типе объекта */
+func (r *ObjectPostgres) GetType(column string, value interface{}, check bool) (*rbacModel.TypesObjectsModel, error) {
+	var types []rbacModel.TypesObjectsModel
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s=$1", tableConstant.AC_TYPES_OBJECTS, column)
+
+	var err error
+
+	switch value.(type) {
+	case int:
+		err = r.db.Select(&types, query, value.(int))
+		break
+	case string:
+		err = r.db.Select(&types, query, value.(string))
+		break
+	}
+
+	if len(types) <= 0 {
+		if check {
+			return nil, errors.New(fmt.Sprintf("Ошибка: типа объекта по запросу %s:%s не найдено!", column, value))
+		}
+
+		return nil, nil
+	}
+
+	return &types[len(types)-1], err
+}
+
+/* Регистрация нового объекта (с привязкой к родительскому объекту) */
+func (r *ObjectPostgres) Create(user *userModel.UserIdentityModel, data rbacModel.ResourceModel) (*rbacModel.ObjectDbModel, error) {
+	check, err := r.Get("value", data.Resource.ResourceUuid, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if check != nil {
+		return nil, errors.New("Ошибка: объект с данным идентификатором уже зарегистрирован!")
+	}
+
+	typeObject, err := r.GetType("value", data.TypeResource, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Проверка соответствия родительского объекта иерархии типов объектов
+	parentType, hasParent := objectConstant.GetParentType(data.TypeResource)
+
+	var parentId *int
+	if data.ParentUuid != nil {
+		if !hasParent {
+			return nil, errors.New(fmt.Sprintf("Ошибка: объект типа %s не может иметь родительского объекта!", data.TypeResource))
+		}
+
+		parent, err := r.Get("value", *data.ParentUuid, true)
+		if err != nil {
+			return nil, err
+		}
+
+		parentTypeObject, err := r.GetType("id", parent.TypesObjectsId, true)
+		if err != nil {
+			return nil, err
+		}
+
+		if parentTypeObject.Value != parentType {
+			return nil, errors.New(fmt.Sprintf("Ошибка: родительским объектом для %s может быть только %s!", data.TypeResource, parentType))
+		}
+
+		parentId = &parent.Id
+	} else if hasParent {
+		return nil, errors.New(fmt.Sprintf("Ошибка: для объекта типа %s необходимо указать родительский объект!", data.TypeResource))
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+
+	var object rbacModel.ObjectDbModel
+	query := fmt.Sprintf(`
+		INSERT INTO %s (value, description, parent_id, types_objects_id)
+		VALUES ($1, $2, $3, $4) RETURNING *
+	`, tableConstant.AC_OBJECTS)
+
+	if err = tx.Get(&object, query, data.Resource.ResourceUuid, data.Resource.Description, parentId, typeObject.Id); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Создатель объекта получает все действия над ним
+	r.enforcer.LoadPolicy()
+
+	if _, err = r.enforcer.AddPolicies(util.GeneratePolicies(
+		strconv.Itoa(user.UserId),
+		strconv.Itoa(user.DomainId),
+		strconv.Itoa(object.Id),
+		actionConstant.GetSlice(),
+	)); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return &object, nil
+}
+
+/* Удаление объекта вместе со всеми дочерними объектами, их политиками и ролями, выданными в их контексте */
+func (r *ObjectPostgres) Delete(objectUuid string) (bool, error) {
+	object, err := r.Get("value", objectUuid, true)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return false, err
+	}
+
+	// UNION (а не UNION ALL) исключает зацикливание при некорректных ссылках на родителя
+	var objects []rbacModel.ObjectDbModel
+	query := fmt.Sprintf(`
+		WITH RECURSIVE tree AS (
+			SELECT id FROM %[1]s WHERE id = $1
+			UNION
+			SELECT o.id FROM %[1]s o INNER JOIN tree t ON o.parent_id = t.id
+		)
+		DELETE FROM %[1]s WHERE id IN (SELECT id FROM tree) RETURNING *
+	`, tableConstant.AC_OBJECTS)
+
+	if err = tx.Select(&objects, query, object.Id); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	// Правила удаляются после фиксации транзакции, чтобы её откат не рассинхронизировал правила и данные
+	r.enforcer.LoadPolicy()
+
+	values := make(map[string]bool)
+	for _, item := range objects {
+		// Политики (p = user, domain, object, action)
+		if _, err = r.enforcer.RemoveFilteredPolicy(2, strconv.Itoa(item.Id)); err != nil {
+			return false, err
+		}
+
+		values[item.Value] = true
+	}
+
+	// Правила группировки (g = user, role;object, domain), иначе роли перешли бы к новому объекту с тем же UUID
+	rules := make([][]string, 0)
+	for _, item := range r.enforcer.GetGroupingPolicy() {
+		if len(item) < 2 {
+			continue
+		}
+
+		if gpsm, err := rbacModel.NewGPSubjectModel(item[1]); err == nil && values[gpsm.ObjectUuid] {
+			rules = append(rules, item)
+		}
+	}
+
+	if len(rules) > 0 {
+		if _, err = r.enforcer.RemoveGroupingPolicies(rules); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+/* Получение цепочки объектов от заданного до корневого (сам объект - первый элемент цепочки) */
+func (r *ObjectPostgres) GetAncestors(objectUuid string) ([]rbacModel.ObjectDbModel, error) {
+	// Вся цепочка загружается одним запросом. UNION (а не UNION ALL) исключает зацикливание
+	// рекурсии, а порядок и защита от циклов в самой цепочке обеспечиваются walkAncestors
+	var objects []rbacModel.ObjectDbModel
+	query := fmt.Sprintf(`
+		WITH RECURSIVE chain AS (
+			SELECT * FROM %[1]s WHERE value = $1
+			UNION
+			SELECT o.* FROM %[1]s o INNER JOIN chain c ON o.id = c.parent_id
+		)
+		SELECT * FROM chain
+	`, tableConstant.AC_OBJECTS)
+
+	if err := r.db.Select(&objects, query, objectUuid); err != nil {
+		return nil, err
+	}
+
+	var object *rbacModel.ObjectDbModel
+	loaded := make(map[int]rbacModel.ObjectDbModel, len(objects))
+
+	for i, item := range objects {
+		loaded[item.Id] = item
+
+		if item.Value == objectUuid {
+			object = &objects[i]
+		}
+	}
+
+	if object == nil {
+		return nil, fmt.Errorf("Ошибка: объекта по запросу value:%s не найдено!", objectUuid)
+	}
+
+	return walkAncestors(*object, func(id int) (*rbacModel.ObjectDbModel, error) {
+		item, ok := loaded[id]
+		if !ok {
+			return nil, fmt.Errorf("Ошибка: объекта по запросу id:%d не найдено!", id)
+		}
+
+		return &item, nil
+	})
+}
+
+/* Обход цепочки родительских объектов с защитой от циклических ссылок */
+func walkAncestors(
+	object rbacModel.ObjectDbModel,
+	parent func(id int) (*rbacModel.ObjectDbModel, error),
+) ([]rbacModel.ObjectDbModel, error) {
+	chain := []rbacModel.ObjectDbModel{object}
+	visited := map[int]bool{object.Id: true}
+
+	for current := object; current.ParentId != nil; {
+		if visited[*current.ParentId] {
+			logrus.Warnf("Ошибка: циклическая ссылка в иерархии объектов (%d -> %d)", current.Id, *current.ParentId)
+			break
+		}
+
+		next, err := parent(*current.ParentId)
+		if err != nil {
+			return nil, err
+		}
+
+		visited[next.Id] = true
+		chain = append(chain, *next)
+		current = *next
+	}
+
+	return chain, nil
+}
+
+/*
+* Проверка возможности выполнения пользователем действия над объектом в рамках домена.
+* Право, выданное на родительский объект (политикой или ролью в контексте объекта),
+* распространяется на все его дочерние объекты
+ */
+func (r *ObjectPostgres) Enforce(userId, domainId int, objectUuid, action string) (bool, error) {
+	chain, err := r.GetAncestors(objectUuid)
+	if err != nil {
+		return false, err
+	}
+
+	r.enforcer.LoadPolicy()
+
+	// Вся цепочка проверяется по одному снимку правил пользователя
+	granted, roles, err := policySnapshot(r.enforcer, strconv.Itoa(userId), strconv.Itoa(domainId))
+	if err != nil {
+		return false, err
+	}
+
+	scoped, err := r.getScopedActions(roles)
+	if err != nil {
+		return false, err
+	}
+
+	return enforceChain(chain, action, granted, scoped), nil
+}
+
+/*
+* Снимок правил пользователя в домене: действия, выданные политиками (id объекта -> действия),
+* с учётом ролей пользователя, и роли, выданные в контексте объектов
+ */
+func policySnapshot(enforcer *casbin.Enforcer, user, domain string) (map[string][]string, []rbacModel.GPSubjectModel, error) {
+	roles, err := enforcer.GetImplicitRolesForUser(user, domain)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subjects := map[string]bool{user: true}
+	scoped := make([]rbacModel.GPSubjectModel, 0)
+
+	for _, item := range roles {
+		subjects[item] = true
+
+		// Роли в рамках всего домена не связаны с объектами
+		if gpsm, err := rbacModel.NewGPSubjectModel(item); err == nil {
+			scoped = append(scoped, *gpsm)
+		}
+	}
+
+	// Политики: p = user, domain, object, action
+	granted := make(map[string][]string)
+	for _, rule := range enforcer.GetFilteredPolicy(1, domain) {
+		if len(rule) < 4 || !subjects[rule[0]] {
+			continue
+		}
+
+		granted[rule[2]] = append(granted[rule[2]], rule[3])
+	}
+
+	return granted, scoped, nil
+}
+
+/* Проверка действия над цепочкой объектов (от объекта к корню) по снимку правил пользователя */
+func enforceChain(chain []rbacModel.ObjectDbModel, action string, granted, scoped map[string][]string) bool {
+	for _, item := range chain {
+		if exists, _ := util.InArray(action, granted[strconv.Itoa(item.Id)]); exists {
+			return true
+		}
+
+		if exists, _ := util.InArray(action, scoped[item.Value]); exists {
+			return true
+		}
+	}
+
+	return false
+}
+
+/* Получение действий, которые дают роли в контексте объектов (UUID объекта -> действия) */
+func (r *ObjectPostgres) getScopedActions(roles []rbacModel.GPSubjectModel) (map[string][]string, error) {
+	scoped := make(map[string][]string)
+	if len(roles) <= 0 {
+		return scoped, nil
+	}
+
+	ids := make([]int64, 0, len(roles))
+	for _, item := range roles {
+		ids = append(ids, int64(item.RoleId))
+	}
+
+	// Значения всех ролей загружаются одним запросом
+	var values []rbacModel.RoleModel
+	query := fmt.Sprintf("SELECT id, value FROM %s WHERE id = ANY($1)", tableConstant.AC_ROLES)
+
+	if err := r.db.Select(&values, query, pq.Array(ids)); err != nil {
+		return nil, err
+	}
+
+	names := make(map[int]string, len(values))
+	for _, item := range values {
+		names[item.Id] = item.Value
+	}
+
+	for _, item := range roles {
+		if name, ok := names[item.RoleId]; ok {
+			scoped[item.ObjectUuid] = append(scoped[item.ObjectUuid], roleConstant.GetObjectActions(name)...)
+		}
+	}
+
+	return scoped, nil
+}
@@ -0,0 +1,361 @@
+package repository
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	actionConstant "main-server/pkg/constant/action"
+	rbacModel "main-server/pkg/model/rbac"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+	fileadapter "github.com/casbin/casbin/v2/persist/file-adapter"
+	"github.com/jmoiron/sqlx"
+)
+
+/* Модель доступа с доменами (p = user, domain, object, action; g = user, role, domain) */
+const testPermModel = `
+[request_definition]
+r = sub, dom, obj, act
+
+[policy_definition]
+p = sub, dom, obj, act
+
+[role_definition]
+g = _, _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub, r.dom) && r.dom == p.dom && r.obj == p.obj && r.act == p.act
+`
+
+const (
+	testRootUuid  = "00000000-0000-0000-0000-000000000001"
+	testMidUuid   = "00000000-0000-0000-0000-000000000002"
+	testLeafUuid  = "00000000-0000-0000-0000-000000000003"
+	testOtherUuid = "00000000-0000-0000-0000-000000000004"
+)
+
+func intPtr(value int) *int {
+	return &value
+}
+
+/* Создание enforcer с правилами в формате CSV */
+func newTestEnforcer(t *testing.T, rules string) *casbin.Enforcer {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "policy.csv")
+	if err := os.WriteFile(path, []byte(rules), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := model.NewModelFromString(testPermModel)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enforcer, err := casbin.NewEnforcer(m, fileadapter.NewAdapter(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return enforcer
+}
+
+func newTestDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	return sqlx.NewDb(db, "postgres"), mock
+}
+
+func TestWalkAncestors(t *testing.T) {
+	deep := map[int]rbacModel.ObjectDbModel{1: {Id: 1}}
+	for id := 2; id <= 500; id++ {
+		deep[id] = rbacModel.ObjectDbModel{Id: id, ParentId: intPtr(id - 1)}
+	}
+
+	tests := []struct {
+		name    string
+		objects map[int]rbacModel.ObjectDbModel
+		start   int
+		chain   []int
+		wantErr bool
+	}{
+		{
+			name:    "root",
+			objects: map[int]rbacModel.ObjectDbModel{1: {Id: 1}},
+			start:   1,
+			chain:   []int{1},
+		},
+		{
+			name: "chain",
+			objects: map[int]rbacModel.ObjectDbModel{
+				1: {Id: 1},
+				2: {Id: 2, ParentId: intPtr(1)},
+				3: {Id: 3, ParentId: intPtr(2)},
+			},
+			start: 3,
+			chain: []int{3, 2, 1},
+		},
+		{
+			name:    "self loop",
+			objects: map[int]rbacModel.ObjectDbModel{1: {Id: 1, ParentId: intPtr(1)}},
+			start:   1,
+			chain:   []int{1},
+		},
+		{
+			name: "two object cycle",
+			objects: map[int]rbacModel.ObjectDbModel{
+				1: {Id: 1, ParentId: intPtr(2)},
+				2: {Id: 2, ParentId: intPtr(1)},
+			},
+			start: 1,
+			chain: []int{1, 2},
+		},
+		{
+			name: "cycle above object",
+			objects: map[int]rbacModel.ObjectDbModel{
+				1: {Id: 1, ParentId: intPtr(2)},
+				2: {Id: 2, ParentId: intPtr(3)},
+				3: {Id: 3, ParentId: intPtr(2)},
+			},
+			start: 1,
+			chain: []int{1, 2, 3},
+		},
+		{
+			name:    "missing parent",
+			objects: map[int]rbacModel.ObjectDbModel{1: {Id: 1, ParentId: intPtr(2)}},
+			start:   1,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			chain, err := walkAncestors(tt.objects[tt.start], func(id int) (*rbacModel.ObjectDbModel, error) {
+				calls++
+				item, ok := tt.objects[id]
+				if !ok {
+					return nil, errors.New("not found")
+				}
+
+				return &item, nil
+			})
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got chain %v", chain)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(chain) != len(tt.chain) {
+				t.Fatalf("chain length = %d, want %d", len(chain), len(tt.chain))
+			}
+
+			for i, id := range tt.chain {
+				if chain[i].Id != id {
+					t.Fatalf("chain[%d] = %d, want %d", i, chain[i].Id, id)
+				}
+			}
+
+			// Каждый родитель запрашивается не более одного раза
+			if calls != len(tt.chain)-1 {
+				t.Fatalf("parent lookups = %d, want %d", calls, len(tt.chain)-1)
+			}
+		})
+	}
+
+	t.Run("deep chain", func(t *testing.T) {
+		chain, err := walkAncestors(deep[500], func(id int) (*rbacModel.ObjectDbModel, error) {
+			item := deep[id]
+			return &item, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(chain) != 500 || chain[len(chain)-1].Id != 1 {
+			t.Fatalf("chain length = %d, last = %d", len(chain), chain[len(chain)-1].Id)
+		}
+	})
+}
+
+func TestObjectEnforce(t *testing.T) {
+	// Цепочка: root (1) <- mid (2) <- leaf (3); other (4) - отдельный корневой объект
+	objects := []rbacModel.ObjectDbModel{
+		{Id: 1, Value: testRootUuid},
+		{Id: 2, Value: testMidUuid, ParentId: intPtr(1)},
+		{Id: 3, Value: testLeafUuid, ParentId: intPtr(2)},
+		{Id: 4, Value: testOtherUuid},
+	}
+
+	tests := []struct {
+		name   string
+		rules  string
+		object int
+		action string
+		roles  map[int]string // Роли, значения которых загружаются из базы данных
+		want   bool
+	}{
+		{
+			name:   "own policy",
+			rules:  "p, 10, 1, 3, read\n",
+			object: 3,
+			action: actionConstant.READ,
+			want:   true,
+		},
+		{
+			name:   "policy inherited from root",
+			rules:  "p, 10, 1, 1, modify\n",
+			object: 3,
+			action: actionConstant.MODIFY,
+			want:   true,
+		},
+		{
+			name:   "policy does not go up",
+			rules:  "p, 10, 1, 3, read\n",
+			object: 1,
+			action: actionConstant.READ,
+			want:   false,
+		},
+		{
+			name:   "other action",
+			rules:  "p, 10, 1, 1, read\n",
+			object: 3,
+			action: actionConstant.DELETE,
+			want:   false,
+		},
+		{
+			name:   "other domain",
+			rules:  "p, 10, 2, 1, read\n",
+			object: 3,
+			action: actionConstant.READ,
+			want:   false,
+		},
+		{
+			name:   "other user",
+			rules:  "p, 11, 1, 1, read\n",
+			object: 3,
+			action: actionConstant.READ,
+			want:   false,
+		},
+		{
+			name:   "unrelated object",
+			rules:  "p, 10, 1, 4, read\n",
+			object: 3,
+			action: actionConstant.READ,
+			want:   false,
+		},
+		{
+			name:   "policy through domain role",
+			rules:  "p, 7, 1, 2, read\ng, 10, 7, 1\n",
+			object: 3,
+			action: actionConstant.READ,
+			want:   true,
+		},
+		{
+			name:   "role in context of ancestor",
+			rules:  "g, 10, 5;" + testRootUuid + ", 1\n",
+			object: 3,
+			action: actionConstant.MODIFY,
+			roles:  map[int]string{5: "manager"},
+			want:   true,
+		},
+		{
+			name:   "role in context does not give other actions",
+			rules:  "g, 10, 5;" + testRootUuid + ", 1\n",
+			object: 3,
+			action: actionConstant.DELETE,
+			roles:  map[int]string{5: "manager"},
+			want:   false,
+		},
+		{
+			name:   "role in context of descendant",
+			rules:  "g, 10, 5;" + testLeafUuid + ", 1\n",
+			object: 2,
+			action: actionConstant.READ,
+			roles:  map[int]string{5: "manager"},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newTestDB(t)
+			r := NewObjectPostgres(db, newTestEnforcer(t, tt.rules))
+
+			// Цепочка загружается одним запросом
+			rows := sqlmock.NewRows([]string{"id", "value", "description", "parent_id", "types_objects_id"})
+			for current := &objects[tt.object-1]; current != nil; {
+				rows.AddRow(current.Id, current.Value, nil, current.ParentId, "1")
+
+				if current.ParentId == nil {
+					break
+				}
+				current = &objects[*current.ParentId-1]
+			}
+			mock.ExpectQuery("WITH RECURSIVE chain").WithArgs(objects[tt.object-1].Value).WillReturnRows(rows)
+
+			// Значения ролей в контексте объектов загружаются одним запросом
+			if tt.roles != nil {
+				roleRows := sqlmock.NewRows([]string{"id", "value"})
+				for id, value := range tt.roles {
+					roleRows.AddRow(id, value)
+				}
+				mock.ExpectQuery(regexp.QuoteMeta("WHERE id = ANY($1)")).WillReturnRows(roleRows)
+			}
+
+			got, err := r.Enforce(10, 1, objects[tt.object-1].Value, tt.action)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("Enforce = %v, want %v", got, tt.want)
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestObjectEnforceCycle(t *testing.T) {
+	db, mock := newTestDB(t)
+	r := NewObjectPostgres(db, newTestEnforcer(t, "p, 10, 1, 2, read\n"))
+
+	// Некорректные данные: 1 -> 2 -> 1
+	rows := sqlmock.NewRows([]string{"id", "value", "description", "parent_id", "types_objects_id"}).
+		AddRow(1, testRootUuid, nil, 2, "1").
+		AddRow(2, testMidUuid, nil, 1, "1")
+	mock.ExpectQuery("WITH RECURSIVE chain").WillReturnRows(rows)
+
+	got, err := r.Enforce(10, 1, testRootUuid, actionConstant.READ)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !got {
+		t.Fatal("expected access inherited through cyclic parent")
+	}
+}
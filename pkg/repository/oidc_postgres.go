@@ -0,0 +1,534 @@
+package repository
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	config "main-server/config"
+	authConstants "main-server/pkg/constant/auth"
+	oidcConstants "main-server/pkg/constant/oidc"
+	tableConstants "main-server/pkg/constant/table"
+	oidcModel "main-server/pkg/model/oidc"
+	userModel "main-server/pkg/model/user"
+	"main-server/pkg/service/denylist"
+	"main-server/pkg/util"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	uuid "github.com/satori/go.uuid"
+	"github.com/spf13/viper"
+)
+
+type OidcPostgres struct {
+	db       *sqlx.DB
+	user     *UserPostgres
+	domain   *DomainPostgres
+	authType *AuthTypePostgres
+	denylist denylist.Store
+}
+
+/* Создание нового экземпляра структуры OidcPostgres */
+func NewOidcPostgres(db *sqlx.DB, user *UserPostgres, domain *DomainPostgres, authType *AuthTypePostgres, store denylist.Store) *OidcPostgres {
+	return &OidcPostgres{
+		db:       db,
+		user:     user,
+		domain:   domain,
+		authType: authType,
+		denylist: store,
+	}
+}
+
+/* Регистрация нового клиента */
+func (r *OidcPostgres) CreateClient(data oidcModel.ClientCreateModel) (*oidcModel.ClientCreatedModel, error) {
+	clientId := uuid.NewV4().String()
+
+	var secret, secretHash *string
+	if data.Confidential {
+		value, err := randomToken()
+		if err != nil {
+			return nil, err
+		}
+
+		hash := hashToken(value)
+		secret, secretHash = &value, &hash
+	}
+
+	// Клиент, получающий токены по client_credentials, действует от имени сервисного аккаунта
+	var serviceAccountsId *int
+	scopes := pq.StringArray{}
+	if data.ServiceAccountUuid != nil {
+		var id int
+		query := fmt.Sprintf("SELECT id FROM %s WHERE uuid = $1 AND deleted_at IS NULL", tableConstants.U_SERVICE_ACCOUNTS)
+		err := r.db.Get(&id, query, *data.ServiceAccountUuid)
+		if err == sql.ErrNoRows {
+			return nil, userModel.ErrServiceAccountMissing
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if scopes, err = normalizeScopes(data.Scopes); err != nil {
+			return nil, err
+		}
+
+		serviceAccountsId = &id
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (client_id, secret_hash, name, redirect_uris, created_at, scopes, service_accounts_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`, tableConstants.OIDC_CLIENTS)
+
+	if _, err := r.db.Exec(query,
+		clientId, secretHash, data.Name, pq.StringArray(data.RedirectUris), time.Now(), scopes, serviceAccountsId,
+	); err != nil {
+		return nil, err
+	}
+
+	return &oidcModel.ClientCreatedModel{
+		ClientId:     clientId,
+		ClientSecret: secret,
+		Name:         data.Name,
+		RedirectUris: data.RedirectUris,
+		Scopes:       scopes,
+	}, nil
+}
+
+/* Получение клиента */
+func (r *OidcPostgres) GetClient(column string, value interface{}, check bool) (*oidcModel.ClientModel, error) {
+	var clients []oidcModel.ClientModel
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s=$1", tableConstants.OIDC_CLIENTS, column)
+
+	if err := r.db.Select(&clients, query, value); err != nil {
+		return nil, err
+	}
+
+	if len(clients) <= 0 {
+		if check {
+			return nil, errors.New(fmt.Sprintf("Ошибка: клиента по запросу %s:%v не найдено!", column, value))
+		}
+
+		return nil, nil
+	}
+
+	return &clients[len(clients)-1], nil
+}
+
+/* Получение всех клиентов */
+func (r *OidcPostgres) GetClients() (*oidcModel.ClientsModel, error) {
+	var clients []oidcModel.ClientModel
+	query := fmt.Sprintf("SELECT * FROM %s ORDER BY created_at", tableConstants.OIDC_CLIENTS)
+
+	if err := r.db.Select(&clients, query); err != nil {
+		return nil, err
+	}
+
+	return &oidcModel.ClientsModel{
+		Clients: clients,
+	}, nil
+}
+
+/* Удаление клиента (вместе с выданными ему кодами авторизации) */
+func (r *OidcPostgres) DeleteClient(clientId string) (bool, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE client_id = $1", tableConstants.OIDC_CLIENTS)
+
+	result, err := r.db.Exec(query, clientId)
+	if err != nil {
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if count <= 0 {
+		return false, errors.New("Ошибка: клиента с данным идентификатором не существует!")
+	}
+
+	return true, nil
+}
+
+/* Выдача одноразового кода авторизации пользователю */
+func (r *OidcPostgres) CreateCode(user *userModel.UserIdentityModel, client *oidcModel.ClientModel, data oidcModel.AuthorizeModel) (string, error) {
+	code, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	currentDate := time.Now()
+	query := fmt.Sprintf(`INSERT INTO %s (code_hash, clients_id, users_id, redirect_uri, scope, nonce,
+		code_challenge, code_challenge_method, auth_time, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`, tableConstants.OIDC_CODES)
+
+	if _, err = r.db.Exec(query,
+		hashToken(code), client.Id, user.UserId, data.RedirectUri, data.Scope, data.Nonce,
+		data.CodeChallenge, data.CodeChallengeMethod, currentDate, currentDate.Add(oidcConstants.CODE_TTL),
+	); err != nil {
+		return "", err
+	}
+
+	// Истёкшие коды больше не нужны
+	query = fmt.Sprintf("DELETE FROM %s WHERE expires_at < $1", tableConstants.OIDC_CODES)
+	if _, err = r.db.Exec(query, currentDate); err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+/*
+* Обмен кода авторизации на токен доступа и ID-токен. Сессия не создаётся: токен доступа привязан
+* к клиенту и ограничен областями доступа из кода (как токен client_credentials)
+ */
+func (r *OidcPostgres) ExchangeCode(data oidcModel.TokenRequestModel) (*oidcModel.TokenResponseModel, error) {
+	client, err := r.AuthenticateClient(data.ClientId, data.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+
+	var codes []oidcModel.CodeModel
+	query := fmt.Sprintf("SELECT * FROM %s WHERE code_hash = $1 LIMIT 1 FOR UPDATE", tableConstants.OIDC_CODES)
+	if err = tx.Select(&codes, query, hashToken(data.Code)); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if len(codes) <= 0 {
+		tx.Rollback()
+		return nil, oidcModel.NewError(oidcConstants.ERROR_INVALID_GRANT, "Код авторизации не найден или уже использован")
+	}
+
+	code := codes[0]
+
+	// Код авторизации одноразовый
+	query = fmt.Sprintf("DELETE FROM %s WHERE id = $1", tableConstants.OIDC_CODES)
+	if _, err = tx.Exec(query, code.Id); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Неудачная попытка обмена также аннулирует код
+	if err = checkCode(&code, client, data); err != nil {
+		tx.Commit()
+		return nil, err
+	}
+
+	user, err := r.user.Get("id", code.UsersId, true)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Токен выдан данным сервером, а не внешним провайдером, поэтому используется локальный тип авторизации
+	authType, err := r.authType.Get("value", authConstants.AUTH_TYPE_LOCAL, true)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	accessToken, err := GenerateServiceToken(user.Uuid, authType.Uuid, client.ClientId, code.Scope)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	idToken, err := r.generateIdToken(user, client, &code)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &oidcModel.TokenResponseModel{
+		AccessToken: accessToken,
+		TokenType:   oidcConstants.TOKEN_TYPE_BEARER,
+		ExpiresIn:   int64(authConstants.TOKEN_TLL_ACCESS.Seconds()),
+		IdToken:     idToken,
+		Scope:       code.Scope,
+	}, nil
+}
+
+/*
+* Выдача токена доступа клиенту от имени его сервисного аккаунта (client_credentials).
+* Сессия не создаётся, токен содержит идентификатор клиента и выданные области доступа
+ */
+func (r *OidcPostgres) ClientCredentials(data oidcModel.TokenRequestModel) (*oidcModel.TokenResponseModel, error) {
+	client, err := r.AuthenticateClient(data.ClientId, data.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.SecretHash == nil || client.ServiceAccountsId == nil {
+		return nil, oidcModel.NewError(oidcConstants.ERROR_UNAUTHORIZED_CLIENT, "Клиенту не разрешено получение токенов по client_credentials")
+	}
+
+	// По умолчанию выдаются все разрешённые клиенту области доступа
+	scopes := strings.Fields(data.Scope)
+	if len(scopes) <= 0 {
+		scopes = client.Scopes
+	}
+
+	for _, item := range scopes {
+		if exists, _ := util.InArray(item, []string(client.Scopes)); !exists {
+			return nil, oidcModel.NewError(oidcConstants.ERROR_INVALID_SCOPE, "Область доступа "+item+" не разрешена клиенту")
+		}
+	}
+
+	var usersId int
+	var usersUuid string
+	query := fmt.Sprintf(`
+		SELECT tu.id, tu.uuid FROM %s ts INNER JOIN %s tu ON tu.id = ts.users_id
+		WHERE ts.id = $1 AND ts.deleted_at IS NULL
+	`, tableConstants.U_SERVICE_ACCOUNTS, tableConstants.U_USERS)
+	err = r.db.QueryRow(query, *client.ServiceAccountsId).Scan(&usersId, &usersUuid)
+	if err == sql.ErrNoRows {
+		return nil, oidcModel.NewError(oidcConstants.ERROR_UNAUTHORIZED_CLIENT, "Сервисный аккаунт клиента удалён")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkBan(r.db, usersId); err != nil {
+		if _, ok := err.(*userModel.BanError); ok {
+			return nil, oidcModel.NewError(oidcConstants.ERROR_UNAUTHORIZED_CLIENT, err.Error())
+		}
+
+		return nil, err
+	}
+
+	authType, err := r.authType.Get("value", authConstants.AUTH_TYPE_CLIENT_CREDENTIALS, true)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := strings.Join(scopes, " ")
+	accessToken, err := GenerateServiceToken(usersUuid, authType.Uuid, client.ClientId, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oidcModel.TokenResponseModel{
+		AccessToken: accessToken,
+		TokenType:   oidcConstants.TOKEN_TYPE_BEARER,
+		ExpiresIn:   int64(authConstants.TOKEN_TLL_ACCESS.Seconds()),
+		Scope:       scope,
+	}, nil
+}
+
+/* Проверка подлинности клиента (конфиденциальные клиенты обязаны подтвердить её секретом) */
+func (r *OidcPostgres) AuthenticateClient(clientId, secret string) (*oidcModel.ClientModel, error) {
+	client, err := r.GetClient("client_id", clientId, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if client == nil {
+		return nil, oidcModel.NewError(oidcConstants.ERROR_INVALID_CLIENT, "Клиент не зарегистрирован")
+	}
+
+	if client.SecretHash != nil &&
+		subtle.ConstantTimeCompare([]byte(hashToken(secret)), []byte(*client.SecretHash)) != 1 {
+		return nil, oidcModel.NewError(oidcConstants.ERROR_INVALID_CLIENT, "Некорректный секрет клиента")
+	}
+
+	return client, nil
+}
+
+/*
+* Получение сведений о токене для интроспекции. Токен сессии активен, пока сессия существует
+* и он является её текущим токеном; отозванный токен и токен заблокированного пользователя неактивны
+ */
+func (r *OidcPostgres) Introspect(data userModel.TokenOutputParse, column, token string) (*oidcModel.IntrospectionModel, error) {
+	inactive := &oidcModel.IntrospectionModel{Active: false}
+
+	revoked, err := r.denylist.Contains(data.TokenId, time.Now())
+	if err != nil || revoked {
+		return inactive, err
+	}
+
+	if data.SessionUuid != "" {
+		active, err := r.hasSessionToken(data.SessionUuid, column, token)
+		if err != nil || !active {
+			return inactive, err
+		}
+	}
+
+	if err := checkBan(r.db, data.UsersId); err != nil {
+		if _, ok := err.(*userModel.BanError); ok {
+			return inactive, nil
+		}
+
+		return nil, err
+	}
+
+	roles, err := r.getRoles(data.UsersId, data.UsersUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &oidcModel.IntrospectionModel{
+		Active:      true,
+		Sub:         data.UsersUuid,
+		Exp:         data.ExpiresAt,
+		Iat:         data.IssuedAt,
+		Jti:         data.TokenId,
+		ClientId:    data.ClientId,
+		Scope:       strings.Join(data.Scopes, " "),
+		AuthType:    data.AuthType.Value,
+		SessionUuid: data.SessionUuid,
+		Roles:       roles,
+	}
+
+	if column == oidcConstants.TOKEN_TYPE_HINT_ACCESS {
+		result.TokenType = oidcConstants.TOKEN_TYPE_BEARER
+	}
+
+	return result, nil
+}
+
+/*
+* Отзыв токена. Токен сессии отзывается вместе с сессией и обоими её токенами,
+* токен без сессии (client_credentials) заносится в список отозванных.
+* Возвращается false, если токен не является текущим токеном сессии
+ */
+func (r *OidcPostgres) Revoke(data userModel.TokenOutputParse, column, token string) (bool, error) {
+	if data.SessionUuid == "" {
+		if err := r.denylist.Add(data.TokenId, time.Unix(data.ExpiresAt, 0)); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	tokens, err := deleteSessions(r.db, fmt.Sprintf("uuid = $1 AND %s = $2", column), data.SessionUuid, token)
+	if err != nil {
+		return false, err
+	}
+
+	if err = denyAccessTokens(r.denylist, tokens); err != nil {
+		return false, err
+	}
+
+	return len(tokens) > 0, nil
+}
+
+/* Проверка, является ли токен текущим токеном сессии (column - access_token или refresh_token) */
+func (r *OidcPostgres) hasSessionToken(sessionUuid, column, token string) (bool, error) {
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE uuid = $1 AND %s = $2", tableConstants.U_TOKENS, column)
+	if err := r.db.Get(&count, query, sessionUuid, token); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+/* Получение ролей пользователя в текущем домене */
+func (r *OidcPostgres) getRoles(usersId int, usersUuid string) ([]userModel.RoleContextModel, error) {
+	domain, err := r.domain.Get("value", viper.GetString("domain"), true)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := r.user.GetAllRoles(userModel.UserIdentityModel{
+		UserId:     usersId,
+		UserUuid:   usersUuid,
+		DomainId:   domain.Id,
+		DomainUuid: domain.Uuid,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return roles.Roles, nil
+}
+
+/* Проверка кода авторизации: клиент, адрес перенаправления, срок действия и PKCE */
+func checkCode(code *oidcModel.CodeModel, client *oidcModel.ClientModel, data oidcModel.TokenRequestModel) error {
+	if code.ClientsId != client.Id || code.RedirectUri != data.RedirectUri {
+		return oidcModel.NewError(oidcConstants.ERROR_INVALID_GRANT, "Код авторизации выдан другому клиенту")
+	}
+
+	if time.Now().After(code.ExpiresAt) {
+		return oidcModel.NewError(oidcConstants.ERROR_INVALID_GRANT, "Срок действия кода авторизации истёк")
+	}
+
+	hash := sha256.Sum256([]byte(data.CodeVerifier))
+	challenge := base64.RawURLEncoding.EncodeToString(hash[:])
+
+	if data.CodeVerifier == "" || subtle.ConstantTimeCompare([]byte(challenge), []byte(code.CodeChallenge)) != 1 {
+		return oidcModel.NewError(oidcConstants.ERROR_INVALID_GRANT, "Некорректный code_verifier")
+	}
+
+	return nil
+}
+
+/* Структура полезных данных ID-токена */
+type idTokenClaims struct {
+	jwt.StandardClaims
+	AuthTime int64                        `json:"auth_time"`
+	Nonce    *string                      `json:"nonce,omitempty"`
+	Email    string                       `json:"email"`
+	Roles    []userModel.RoleContextModel `json:"roles"`
+}
+
+/* Генерация ID-токена (подписывается только асимметричным ключом, чтобы клиенты могли проверить его по JWKS) */
+func (r *OidcPostgres) generateIdToken(user *userModel.UserModel, client *oidcModel.ClientModel, code *oidcModel.CodeModel) (string, error) {
+	if !config.AppJWTKeys.Enabled() {
+		return "", errors.New("Ошибка: для выдачи ID-токенов необходимо настроить ключи подписи token.keys")
+	}
+
+	key, err := config.AppJWTKeys.SigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	roles, err := r.getRoles(user.Id, user.Uuid)
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.Method, &idTokenClaims{
+		jwt.StandardClaims{
+			Issuer:    viper.GetString("oidc.issuer"),
+			Subject:   user.Uuid,
+			Audience:  client.ClientId,
+			ExpiresAt: time.Now().Add(authConstants.TOKEN_TLL_ACCESS).Unix(),
+			IssuedAt:  time.Now().Unix(),
+		},
+		code.AuthTime.Unix(),
+		code.Nonce,
+		user.Email,
+		roles,
+	})
+	token.Header["kid"] = key.Kid
+
+	return token.SignedString(key.Private)
+}
+
+/* Генерация случайного токена (кода авторизации или секрета клиента) */
+func randomToken() (string, error) {
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	authConstants "main-server/pkg/constant/auth"
+	oidcConstants "main-server/pkg/constant/oidc"
+	scopeConstants "main-server/pkg/constant/scope"
+	oidcModel "main-server/pkg/model/oidc"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/spf13/viper"
+)
+
+const (
+	testClientId     = "client"
+	testClientSecret = "secret"
+	testServiceUuid  = "00000000-0000-0000-0000-000000000030"
+)
+
+var testClientColumns = []string{
+	"id", "client_id", "secret_hash", "name", "redirect_uris", "created_at", "scopes", "service_accounts_id",
+}
+
+func newTestOidc(t *testing.T) (*OidcPostgres, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock := newTestDB(t)
+
+	return NewOidcPostgres(db, nil, nil, NewAuthTypePostgres(db), nil), mock
+}
+
+/* Строка клиента: secretHash и serviceAccountsId могут отсутствовать (nil) */
+func testClientRow(secretHash *string, serviceAccountsId *int) *sqlmock.Rows {
+	return sqlmock.NewRows(testClientColumns).AddRow(
+		1, testClientId, secretHash, "Client", "{}", time.Now(),
+		"{"+scopeConstants.SERVICE_VERIFY+","+scopeConstants.SERVICE_OBJECT+"}", serviceAccountsId,
+	)
+}
+
+func strPtr(value string) *string {
+	return &value
+}
+
+func TestClientCredentials(t *testing.T) {
+	viper.Set("token.signing_key_access", "access-secret")
+
+	secretHash := strPtr(hashToken(testClientSecret))
+	banColumns := []string{"id", "uuid", "users_id", "kind", "reason", "created_at", "expires_at"}
+
+	tests := []struct {
+		name     string
+		secret   string
+		scope    string
+		client   *sqlmock.Rows // nil - клиент не зарегистрирован
+		account  *sqlmock.Rows // nil - запрос сервисного аккаунта не выполняется
+		banned   bool
+		wantCode string // Код ошибки OAuth 2.0 (пустой - токен выдаётся)
+		wantScp  string
+	}{
+		{
+			name:    "all client scopes by default",
+			secret:  testClientSecret,
+			client:  testClientRow(secretHash, intPtr(3)),
+			account: sqlmock.NewRows([]string{"id", "uuid"}).AddRow(10, testServiceUuid),
+			wantScp: scopeConstants.SERVICE_VERIFY + " " + scopeConstants.SERVICE_OBJECT,
+		},
+		{
+			name:    "requested scope",
+			secret:  testClientSecret,
+			scope:   scopeConstants.SERVICE_OBJECT,
+			client:  testClientRow(secretHash, intPtr(3)),
+			account: sqlmock.NewRows([]string{"id", "uuid"}).AddRow(10, testServiceUuid),
+			wantScp: scopeConstants.SERVICE_OBJECT,
+		},
+		{
+			name:     "scope is not allowed",
+			secret:   testClientSecret,
+			scope:    scopeConstants.SERVICE_VERIFY + " " + scopeConstants.PROFILE,
+			client:   testClientRow(secretHash, intPtr(3)),
+			wantCode: oidcConstants.ERROR_INVALID_SCOPE,
+		},
+		{
+			name:     "unknown client",
+			secret:   testClientSecret,
+			wantCode: oidcConstants.ERROR_INVALID_CLIENT,
+		},
+		{
+			name:     "wrong secret",
+			secret:   "other",
+			client:   testClientRow(secretHash, intPtr(3)),
+			wantCode: oidcConstants.ERROR_INVALID_CLIENT,
+		},
+		{
+			name:     "public client",
+			client:   testClientRow(nil, intPtr(3)),
+			wantCode: oidcConstants.ERROR_UNAUTHORIZED_CLIENT,
+		},
+		{
+			name:     "client without service account",
+			secret:   testClientSecret,
+			client:   testClientRow(secretHash, nil),
+			wantCode: oidcConstants.ERROR_UNAUTHORIZED_CLIENT,
+		},
+		{
+			name:     "service account is deleted",
+			secret:   testClientSecret,
+			client:   testClientRow(secretHash, intPtr(3)),
+			account:  sqlmock.NewRows([]string{"id", "uuid"}),
+			wantCode: oidcConstants.ERROR_UNAUTHORIZED_CLIENT,
+		},
+		{
+			name:     "service account is banned",
+			secret:   testClientSecret,
+			client:   testClientRow(secretHash, intPtr(3)),
+			account:  sqlmock.NewRows([]string{"id", "uuid"}).AddRow(10, testServiceUuid),
+			banned:   true,
+			wantCode: oidcConstants.ERROR_UNAUTHORIZED_CLIENT,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock := newTestOidc(t)
+
+			clientRows := tt.client
+			if clientRows == nil {
+				clientRows = sqlmock.NewRows(testClientColumns)
+			}
+			mock.ExpectQuery("SELECT \\* FROM .* WHERE client_id=\\$1").WithArgs(testClientId).WillReturnRows(clientRows)
+
+			if tt.account != nil {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT tu.id, tu.uuid")).WithArgs(3).WillReturnRows(tt.account)
+			}
+
+			if tt.wantCode == "" || tt.banned {
+				bans := sqlmock.NewRows(banColumns)
+				if tt.banned {
+					bans.AddRow(1, "ban-uuid", 10, "ban", "", time.Now(), nil)
+				}
+				mock.ExpectQuery("SELECT id, uuid, users_id, kind").WithArgs(10, sqlmock.AnyArg()).WillReturnRows(bans)
+			}
+
+			if tt.wantCode == "" {
+				mock.ExpectQuery("SELECT \\* FROM .* WHERE value=\\$1").
+					WithArgs(authConstants.AUTH_TYPE_CLIENT_CREDENTIALS).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "uuid", "value"}).
+						AddRow(5, "auth-type-uuid", authConstants.AUTH_TYPE_CLIENT_CREDENTIALS))
+			}
+
+			data, err := r.ClientCredentials(oidcModel.TokenRequestModel{
+				GrantType:    oidcConstants.GRANT_TYPE_CLIENT_CREDENTIALS,
+				ClientId:     testClientId,
+				ClientSecret: tt.secret,
+				Scope:        tt.scope,
+			})
+
+			if tt.wantCode != "" {
+				oauthErr, ok := err.(*oidcModel.ErrorModel)
+				if !ok || oauthErr.Code != tt.wantCode {
+					t.Fatalf("err = %v, want %s", err, tt.wantCode)
+				}
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if data.Scope != tt.wantScp || data.TokenType != oidcConstants.TOKEN_TYPE_BEARER {
+					t.Fatalf("unexpected response %+v", data)
+				}
+
+				// Токен привязан к клиенту и не относится ни к одной сессии
+				claims := &tokenClaims{}
+				if _, err = jwt.ParseWithClaims(data.AccessToken, claims, func(token *jwt.Token) (interface{}, error) {
+					return []byte("access-secret"), nil
+				}); err != nil {
+					t.Fatal(err)
+				}
+
+				if claims.UsersId != testServiceUuid || claims.ClientId != testClientId ||
+					claims.Scope != tt.wantScp || claims.SessionId != "" {
+					t.Fatalf("unexpected claims %+v", claims)
+				}
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestAuthenticateClient(t *testing.T) {
+	secretHash := strPtr(hashToken(testClientSecret))
+
+	tests := []struct {
+		name     string
+		secret   string
+		client   *sqlmock.Rows
+		wantCode string
+	}{
+		{name: "valid secret", secret: testClientSecret, client: testClientRow(secretHash, nil)},
+		{name: "wrong secret", secret: "other", client: testClientRow(secretHash, nil), wantCode: oidcConstants.ERROR_INVALID_CLIENT},
+		{name: "empty secret", client: testClientRow(secretHash, nil), wantCode: oidcConstants.ERROR_INVALID_CLIENT},
+		{name: "public client", client: testClientRow(nil, nil)},
+		{name: "unknown client", secret: testClientSecret, client: sqlmock.NewRows(testClientColumns), wantCode: oidcConstants.ERROR_INVALID_CLIENT},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock := newTestOidc(t)
+			mock.ExpectQuery("SELECT \\* FROM .* WHERE client_id=\\$1").WithArgs(testClientId).WillReturnRows(tt.client)
+
+			client, err := r.AuthenticateClient(testClientId, tt.secret)
+			if tt.wantCode == "" {
+				if err != nil || client.ClientId != testClientId {
+					t.Fatalf("client = %+v, err = %v", client, err)
+				}
+				return
+			}
+
+			oauthErr, ok := err.(*oidcModel.ErrorModel)
+			if !ok || oauthErr.Code != tt.wantCode {
+				t.Fatalf("err = %v, want %s", err, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	tableConstants "main-server/pkg/constant/table"
+	userModel "main-server/pkg/model/user"
+	passwordService "main-server/pkg/service/password"
+)
+
+/* Персональные данные пользователя для проверки пароля */
+func passwordPersonal(email string, data userModel.UserDataDbModel) passwordService.Personal {
+	return passwordService.Personal{
+		Email:  email,
+		Values: []string{data.Name, data.Surname, data.Nickname, data.Patronymic},
+	}
+}
+
+/* Получение персональных данных существующего пользователя для проверки пароля */
+func getPasswordPersonal(tx *sql.Tx, userId int) (passwordService.Personal, error) {
+	var email string
+	var data []byte
+
+	query := fmt.Sprintf(`
+		SELECT tu.email, COALESCE(td.data, '{}') FROM %s tu
+		LEFT JOIN %s td ON td.users_id = tu.id
+		WHERE tu.id = $1 LIMIT 1
+	`, tableConstants.U_USERS, tableConstants.U_USERS_DATA)
+	if err := tx.QueryRow(query, userId).Scan(&email, &data); err != nil {
+		return passwordService.Personal{}, err
+	}
+
+	var userData userModel.UserDataDbModel
+	if err := json.Unmarshal(data, &userData); err != nil {
+		return passwordService.Personal{}, err
+	}
+
+	return passwordPersonal(email, userData), nil
+}
+
+/* Сохранение хэша пароля в истории паролей пользователя */
+func savePasswordHistory(tx *sql.Tx, userId int, hashedPassword string) error {
+	limit := passwordService.GetPolicy().History
+	if limit <= 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (users_id, password_hash, created_at) VALUES ($1, $2, $3)", tableConstants.U_PASSWORD_HISTORY)
+	if _, err := tx.Exec(query, userId, hashedPassword, time.Now()); err != nil {
+		return err
+	}
+
+	// В истории хранятся только последние N паролей
+	query = fmt.Sprintf(`
+		DELETE FROM %[1]s WHERE users_id = $1 AND id NOT IN (
+			SELECT id FROM %[1]s WHERE users_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2
+		)
+	`, tableConstants.U_PASSWORD_HISTORY)
+	_, err := tx.Exec(query, userId, limit)
+
+	return err
+}
+
+/*
+* Смена пароля существующего пользователя: проверка политики паролей и истории
+* (текущий пароль и последние N паролей), хэширование и сохранение
+ */
+func changePassword(tx *sql.Tx, userId int, password string, personal *passwordService.Personal) error {
+	if personal == nil {
+		value, err := getPasswordPersonal(tx, userId)
+		if err != nil {
+			return err
+		}
+
+		personal = &value
+	}
+
+	if err := passwordService.Validate(password, *personal); err != nil {
+		return err
+	}
+
+	if limit := passwordService.GetPolicy().History; limit > 0 {
+		query := fmt.Sprintf(`
+			SELECT password FROM %s WHERE id = $1
+			UNION ALL
+			(SELECT password_hash FROM %s WHERE users_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2)
+		`, tableConstants.U_USERS, tableConstants.U_PASSWORD_HISTORY)
+
+		rows, err := tx.Query(query, userId, limit)
+		if err != nil {
+			return err
+		}
+
+		hashes := make([]string, 0)
+		for rows.Next() {
+			var hash string
+			if err := rows.Scan(&hash); err != nil {
+				rows.Close()
+				return err
+			}
+
+			hashes = append(hashes, hash)
+		}
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, hash := range hashes {
+			if ok, _, _ := passwordService.Verify(hash, password); ok {
+				return passwordService.NewReuseError()
+			}
+		}
+	}
+
+	hashedPassword, err := passwordService.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	if err = savePasswordHistory(tx, userId, hashedPassword); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET password = $1 WHERE id = $2", tableConstants.U_USERS)
+	_, err = tx.Exec(query, hashedPassword, userId)
+
+	return err
+}
@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	authConstants "main-server/pkg/constant/auth"
+	tableConstants "main-server/pkg/constant/table"
+	"main-server/pkg/model/email"
+	userModel "main-server/pkg/model/user"
+	smtpService "main-server/pkg/service/smtp"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+/*
+* Отправка письма со ссылкой и кодом для входа без пароля. Ответ не зависит от того,
+* существует ли пользователь с данным email-адресом
+ */
+func (r *AuthPostgres) RequestLoginCode(userEmail string) (bool, error) {
+	user, err := r.userPostgres.Get("email", userEmail, false)
+	if err != nil {
+		return false, err
+	}
+
+	if user == nil {
+		return true, nil
+	}
+
+	signingKey := viper.GetString("token.signing_key_login")
+	if signingKey == "" {
+		return false, errors.New("Ошибка: не задан ключ подписи token.signing_key_login")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	// Ограничение частоты отправки писем одному пользователю (повторный запрос не выдаёт существование email-адреса)
+	var lastCreatedAt time.Time
+	query := fmt.Sprintf("SELECT created_at FROM %s WHERE users_id = $1 FOR UPDATE", tableConstants.U_LOGIN_CODES)
+	err = tx.QueryRow(query, user.Id).Scan(&lastCreatedAt)
+	if err != nil && err != sql.ErrNoRows {
+		tx.Rollback()
+		return false, err
+	}
+
+	currentDate := time.Now()
+	if err == nil && currentDate.Sub(lastCreatedAt) < authConstants.LOGIN_CODE_RESEND_INTERVAL {
+		tx.Rollback()
+		return true, nil
+	}
+
+	token, err := GenerateResetToken(user.Uuid, user.Email, authConstants.TOKEN_TLL_RESET, signingKey)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	code, err := generateLoginCode()
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	// Новое письмо аннулирует ранее отправленные ссылку и код
+	query = fmt.Sprintf(`
+		INSERT INTO %s (users_id, token_hash, code_hash, attempts, created_at, expires_at) VALUES ($1, $2, $3, 0, $4, $5)
+		ON CONFLICT (users_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, code_hash = EXCLUDED.code_hash,
+		attempts = 0, created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+	`, tableConstants.U_LOGIN_CODES)
+	if _, err = tx.Exec(query, user.Id, hashToken(token), hashToken(code), currentDate, currentDate.Add(authConstants.TOKEN_TLL_RESET)); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	err = smtpService.SendMessage(user.Email, smtpService.BuildMessage(email.Mail{
+		Sender:  viper.GetString("smtp.email"),
+		To:      []string{user.Email},
+		Subject: "Вход в \"МИСУ Мирный\"",
+		Body: fmt.Sprintf(`<html>
+		<head>
+			<meta charset="utf-8" />
+			<title></title>
+		</head>
+		<style>
+			body {background-color: #FEFEF9;}
+			h2   {color: #181511;}
+			button {
+				color: rgb(0, 0, 0);
+				outline: none;
+				border: none;
+				border-radius: 30px;
+				background-color: #B19472;
+				padding: 8px 16px;
+				margin-top: 16px;
+				cursor: pointer;
+			}
+		</style>
+		<body>
+			<h2>Вход без пароля</h2>
+			<br><text>Вы получили это письмо, так как для Вашего почтового адреса был запрошен вход в приложение "МИСУ Мирный".</text>
+			</br><text>Код для входа: <b>%s</b></text></br>
+			</br><text>Или перейдите по указанной ссылке: </text></br>
+			<a href="%s">
+			<button>Войти</button>
+			</a>
+			<br><br><br>
+			<text>Код и ссылка действуют %d минут и могут быть использованы только один раз.</text>
+			</br><text>Если Вы не запрашивали вход в приложение "МИСУ Мирный", то не отвечайте на данное сообщение.</text>
+		</body>
+	</html>`, code, viper.GetString("crm_url")+"/auth/passwordless/"+token, int(authConstants.TOKEN_TLL_RESET.Minutes())),
+	}))
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* Вход по одноразовой ссылке из письма */
+func (r *AuthPostgres) LoginByLink(token string, tokenData userModel.ResetTokenOutputParse, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	user, err := r.userPostgres.Get("id", tokenData.UsersId, true)
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	// Ссылка одноразовая: запись удаляется вместе с кодом из того же письма
+	var id int
+	query := fmt.Sprintf(`
+		DELETE FROM %s WHERE users_id = $1 AND token_hash = $2 AND expires_at >= $3 RETURNING id
+	`, tableConstants.U_LOGIN_CODES)
+	if err = tx.QueryRow(query, user.Id, hashToken(token), time.Now()).Scan(&id); err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, errors.New("Ошибка: ссылка для входа недействительна или уже была использована")
+	}
+
+	authData, err := r.passwordlessSession(tx, user, session)
+	if err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	return authData, tx.Commit()
+}
+
+/* Вход по одноразовому коду из письма */
+func (r *AuthPostgres) LoginByCode(data userModel.PasswordlessCodeModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	invalidErr := errors.New("Ошибка: неверный или просроченный код для входа")
+
+	user, err := r.userPostgres.Get("email", data.Email, false)
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	if user == nil {
+		return userModel.UserAuthDataModel{}, invalidErr
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	var loginCode userModel.LoginCodeModel
+	query := fmt.Sprintf(`
+		SELECT id, code_hash, attempts, expires_at FROM %s WHERE users_id = $1 FOR UPDATE
+	`, tableConstants.U_LOGIN_CODES)
+	err = tx.QueryRow(query, user.Id).Scan(&loginCode.Id, &loginCode.CodeHash, &loginCode.Attempts, &loginCode.ExpiresAt)
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return userModel.UserAuthDataModel{}, invalidErr
+		}
+
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	if time.Now().After(loginCode.ExpiresAt) {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, invalidErr
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashToken(data.Code)), []byte(loginCode.CodeHash)) != 1 {
+		// Неудачная попытка учитывается, после исчерпания попыток код и ссылка аннулируются
+		if loginCode.Attempts+1 >= authConstants.LOGIN_CODE_MAX_ATTEMPTS {
+			query = fmt.Sprintf("DELETE FROM %s WHERE id = $1", tableConstants.U_LOGIN_CODES)
+
+			logrus.WithFields(logrus.Fields{
+				"users_id": user.Id,
+			}).Warn("login_code_attempts_exceeded")
+		} else {
+			query = fmt.Sprintf("UPDATE %s SET attempts = attempts + 1 WHERE id = $1", tableConstants.U_LOGIN_CODES)
+		}
+
+		if _, err = tx.Exec(query, loginCode.Id); err != nil {
+			tx.Rollback()
+			return userModel.UserAuthDataModel{}, err
+		}
+
+		if err = tx.Commit(); err != nil {
+			return userModel.UserAuthDataModel{}, err
+		}
+
+		return userModel.UserAuthDataModel{}, invalidErr
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE id = $1", tableConstants.U_LOGIN_CODES)
+	if _, err = tx.Exec(query, loginCode.Id); err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	authData, err := r.passwordlessSession(tx, user, session)
+	if err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	return authData, tx.Commit()
+}
+
+/*
+* Создание сессии после входа без пароля. Письмо подтверждает только владение email-адресом,
+* поэтому при подключённом втором факторе вместо пары токенов возвращается токен ожидания
+ */
+func (r *AuthPostgres) passwordlessSession(tx *sql.Tx, user *userModel.UserModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	if err := checkBan(tx, user.Id); err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	// Вход по письму подтверждает владение email-адресом, поэтому аккаунт считается подтверждённым
+	if err := activateUser(tx, user.Id); err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	pending, err := r.mfa.Challenge(user.Id, user.Uuid, authConstants.AUTH_TYPE_LOCAL, nil)
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	if pending != nil {
+		return userModel.UserAuthDataModel{Mfa: pending}, nil
+	}
+
+	authType, err := getAuthType(r.db, authConstants.AUTH_TYPE_LOCAL)
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	return createSession(tx, user.Id, user.Uuid, authType.Uuid, nil, nil, session)
+}
+
+/* Генерация числового кода для входа */
+func generateLoginCode() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < authConstants.LOGIN_CODE_DIGITS; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", authConstants.LOGIN_CODE_DIGITS, n.Int64()), nil
+}
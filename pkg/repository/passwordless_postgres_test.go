@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/spf13/viper"
+)
+
+func TestRequestLoginCodeResendInterval(t *testing.T) {
+	viper.Set("token.signing_key_login", "login-secret")
+
+	db, mock := newTestDB(t)
+	r := NewAuthPostgres(db, nil, UserPostgres{db: db}, nil, nil)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM u_users WHERE email=$1")).WithArgs("user@example.com").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "uuid", "email", "password"}).AddRow(10, testRootUuid, "user@example.com", ""),
+	)
+	mock.ExpectBegin()
+	mock.ExpectQuery("FOR UPDATE").WithArgs(10).WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+	mock.ExpectRollback()
+
+	// Повторный запрос отвечает так же, как для неизвестного адреса, но письмо не отправляется
+	ok, err := r.RequestLoginCode("user@example.com")
+	if err != nil || !ok {
+		t.Fatalf("ok = %v, err = %v", ok, err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,10 +1,15 @@
 package repository
 
 import (
+	config "main-server/config"
+	authConstants "main-server/pkg/constant/auth"
 	emailModel "main-server/pkg/model/email"
+	oidcModel "main-server/pkg/model/oidc"
 	rbacModel "main-server/pkg/model/rbac"
 	resourceModel "main-server/pkg/model/resource"
 	userModel "main-server/pkg/model/user"
+	"main-server/pkg/service/denylist"
+	"main-server/pkg/service/throttle"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
@@ -13,11 +18,11 @@ import (
 )
 
 type Authorization interface {
-	CreateUser(user userModel.UserSignUpModel) (userModel.UserAuthDataModel, error)
+	CreateUser(user userModel.UserSignUpModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
 	UploadProfileImage(c *gin.Context, filepath string) (bool, error)
-	LoginUser(user userModel.UserSignInModel) (userModel.UserAuthDataModel, error)
-	LoginUserOAuth2(code string) (userModel.UserAuthDataModel, error)
-	CreateUserOAuth2(user userModel.UserRegisterOAuth2Model, token *oauth2.Token) (userModel.UserAuthDataModel, error)
+	LoginUser(user userModel.UserSignInModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+	LoginUserOAuth2(provider, code string, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+	CreateUserOAuth2(provider string, user userModel.UserRegisterOAuth2Model, token *oauth2.Token, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
 	Refresh(data userModel.TokenLogoutDataModel, refreshToken string, token userModel.TokenOutputParse) (userModel.UserAuthDataModel, error)
 	Logout(tokens userModel.TokenLogoutDataModel) (bool, error)
 	Activate(link string) (bool, error)
@@ -25,16 +30,109 @@ type Authorization interface {
 	GetRole(column, value string) (rbacModel.RoleModel, error)
 	RecoveryPassword(email string) (bool, error)
 	ResetPassword(data userModel.ResetPasswordModel, token userModel.ResetTokenOutputParse) (bool, error)
+	ResendActivation(email string) (bool, error)
+	IsActivated(userId int) (bool, error)
+	RequestLoginCode(email string) (bool, error)
+	LoginByLink(token string, tokenData userModel.ResetTokenOutputParse, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+	LoginByCode(data userModel.PasswordlessCodeModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+}
+
+type Session interface {
+	GetAll(userId int, currentUuid string) (*userModel.SessionsModel, error)
+	Delete(userId int, sessionUuid string) (bool, error)
+	DeleteOthers(userId int, currentUuid string) (bool, error)
+}
+
+type Mfa interface {
+	GetStatus(userId int) (*userModel.MfaStatusModel, error)
+	SetupTotp(userId int) (*userModel.MfaTotpSetupModel, error)
+	EnableTotp(userId int, code string) (*userModel.MfaRecoveryCodesModel, error)
+	DisableTotp(userId int, code string) (bool, error)
+	RegenerateRecoveryCodes(userId int, code string) (*userModel.MfaRecoveryCodesModel, error)
+	Login(token userModel.MfaTokenOutputParse, code string, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+	EnrollLogin(token userModel.MfaTokenOutputParse, code string, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, *userModel.MfaRecoveryCodesModel, error)
+}
+
+type WebAuthn interface {
+	GetAll(userId int) (*userModel.WebAuthnCredentialsModel, error)
+	BeginRegistration(userId int) (*userModel.WebAuthnBeginModel, error)
+	FinishRegistration(userId int, data userModel.WebAuthnRegisterFinishModel) (*userModel.WebAuthnCredentialModel, error)
+	BeginLogin(email string) (*userModel.WebAuthnBeginModel, error)
+	FinishLogin(data userModel.WebAuthnLoginFinishModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+	Delete(userId int, credentialUuid string) (bool, error)
+}
+
+type Identity interface {
+	GetAll(userId int) (*userModel.IdentitiesModel, error)
+	Link(userId int, provider, code string) (*userModel.IdentityModel, error)
+	Unlink(userId int, identityUuid string) (bool, error)
+}
+
+type EmailChange interface {
+	Request(userId int, sessionUuid string, data userModel.EmailChangeRequestModel) (*userModel.EmailChangeModel, error)
+	Confirm(link string) (bool, error)
+	Cancel(link string) (bool, error)
+}
+
+type ApiKey interface {
+	Create(actor *userModel.UserIdentityModel, userId int, data userModel.ApiKeyCreateModel) (*userModel.ApiKeyCreatedModel, error)
+	GetAll(userId int) (*userModel.ApiKeysModel, error)
+	Revoke(userId int, apiKeyUuid string) (bool, error)
+	Authenticate(key string) (*userModel.ApiKeyIdentityModel, error)
+}
+
+type ServiceAccount interface {
+	Create(actor *userModel.UserIdentityModel, data userModel.ServiceAccountCreateModel) (*userModel.ServiceAccountModel, error)
+	GetAll() (*userModel.ServiceAccountsModel, error)
+	Delete(accountUuid string) (bool, error)
+	CreateKey(actor *userModel.UserIdentityModel, data userModel.ServiceAccountApiKeyCreateModel) (*userModel.ApiKeyCreatedModel, error)
+	GetKeys(accountUuid string) (*userModel.ApiKeysModel, error)
+	RevokeKey(data userModel.ServiceAccountApiKeyModel) (bool, error)
+}
+
+type UserAdmin interface {
+	Search(actor *userModel.UserIdentityModel, data userModel.UserSearchModel) (*userModel.UsersListModel, error)
+	Get(actor *userModel.UserIdentityModel, usersUuid string) (*userModel.UserDetailModel, error)
+}
+
+type Ban interface {
+	GetActive(userId int) (*userModel.BanModel, error)
+	GetAll(usersUuid string) (*userModel.BansModel, error)
+	Create(actor *userModel.UserIdentityModel, data userModel.BanCreateModel) (*userModel.BanModel, error)
+	Lift(actor *userModel.UserIdentityModel, data userModel.BanUserModel) (bool, error)
 }
 
 type Role interface {
 	HasRole(usersId, domainsId int, roleValue string) (bool, error)
 	HasRoleWithSubject(userId, domainId int, roleValue, subjectId string) (bool, error)
+	AddRoleForUser(user *userModel.UserIdentityModel, data rbacModel.AccessRoleModel) (bool, error)
+	DeleteRoleForUser(user *userModel.UserIdentityModel, data rbacModel.AccessRoleModel) (bool, error)
+
+	// CRUD
+	Create(user *userModel.UserIdentityModel, data rbacModel.RoleCreateModel) (*rbacModel.RoleModel, error)
 	Get(column string, value interface{}, check bool) (*rbacModel.RoleModel, error)
+	GetAll(user *userModel.UserIdentityModel, domainUuid *string) (*rbacModel.RolesModel, error)
+	Update(data rbacModel.RoleUpdateModel) (*rbacModel.RoleModel, error)
+	Delete(roleUuid string) (bool, error)
 }
 
 type Domain interface {
+	// CRUD
+	Create(user *userModel.UserIdentityModel, data rbacModel.DomainCreateModel) (*rbacModel.DomainModel, error)
 	Get(column string, value interface{}, check bool) (*rbacModel.DomainModel, error)
+	GetAll() (*rbacModel.DomainsModel, error)
+	Update(data rbacModel.DomainUpdateModel) (*rbacModel.DomainModel, error)
+	Delete(domainUuid string) (bool, error)
+}
+
+type Object interface {
+	Enforce(userId, domainId int, objectUuid, action string) (bool, error)
+	GetAncestors(objectUuid string) ([]rbacModel.ObjectDbModel, error)
+
+	// CRUD
+	Create(user *userModel.UserIdentityModel, data rbacModel.ResourceModel) (*rbacModel.ObjectDbModel, error)
+	Get(column string, value interface{}, check bool) (*rbacModel.ObjectDbModel, error)
+	Delete(objectUuid string) (bool, error)
 }
 
 type User interface {
@@ -46,6 +144,19 @@ type User interface {
 	Get(column string, value interface{}, check bool) (*userModel.UserModel, error)
 }
 
+type Oidc interface {
+	CreateClient(data oidcModel.ClientCreateModel) (*oidcModel.ClientCreatedModel, error)
+	GetClient(column string, value interface{}, check bool) (*oidcModel.ClientModel, error)
+	GetClients() (*oidcModel.ClientsModel, error)
+	DeleteClient(clientId string) (bool, error)
+	CreateCode(user *userModel.UserIdentityModel, client *oidcModel.ClientModel, data oidcModel.AuthorizeModel) (string, error)
+	ExchangeCode(data oidcModel.TokenRequestModel) (*oidcModel.TokenResponseModel, error)
+	ClientCredentials(data oidcModel.TokenRequestModel) (*oidcModel.TokenResponseModel, error)
+	AuthenticateClient(clientId, secret string) (*oidcModel.ClientModel, error)
+	Introspect(data userModel.TokenOutputParse, column, token string) (*oidcModel.IntrospectionModel, error)
+	Revoke(data userModel.TokenOutputParse, column, token string) (bool, error)
+}
+
 type AuthType interface {
 	Get(column string, value interface{}, check bool) (*userModel.AuthTypeModel, error)
 }
@@ -56,27 +167,73 @@ type ServiceMain interface {
 
 type Repository struct {
 	Authorization
+	Session
+	Mfa
+	WebAuthn
+	Identity
+	EmailChange
+	ApiKey
+	ServiceAccount
+	Ban
+	UserAdmin
 	Role
 	Domain
+	Object
 	User
 	AuthType
+	Oidc
 	ServiceMain
+
+	Throttle throttle.Store // Хранилище счётчиков неудачных попыток входа
+	Denylist denylist.Store // Список отозванных токенов доступа
 }
 
 /* Создание нового экземпляра глобального репозитория */
 func NewRepository(db *sqlx.DB, enforcer *casbin.Enforcer) *Repository {
 
-	role := NewRolePostgres(db, enforcer)
-	domain := NewDomainPostgres(db)
+	domain := NewDomainPostgres(db, enforcer)
+	object := NewObjectPostgres(db, enforcer)
+	role := NewRolePostgres(db, enforcer, domain, object)
 	user := NewUserPostgres(db, enforcer, domain, role)
+	authType := NewAuthTypePostgres(db)
 	serviceMain := NewServiceMainRepository(db, enforcer, user)
+	mfa := NewMfaPostgres(db, enforcer, user, domain, authType)
+	apiKey := NewApiKeyPostgres(db)
+
+	var throttleStore throttle.Store = throttle.NewMemoryStore()
+	if config.AppLockoutPolicy.Store == authConstants.LOCKOUT_STORE_POSTGRES {
+		throttleStore = NewThrottlePostgres(db)
+	}
+
+	var denylistStore denylist.Store
+	if config.AppDenylistPolicy.Store == authConstants.DENYLIST_STORE_POSTGRES {
+		denylistStore = NewDenylistPostgres(db)
+	} else {
+		denylistStore = denylist.NewMemoryStore(config.AppDenylistPolicy.SweepInterval)
+	}
+
+	session := NewSessionPostgres(db, denylistStore)
+	identity := NewIdentityPostgres(db)
 
 	return &Repository{
-		Authorization: NewAuthPostgres(db, enforcer, *user),
-		Role:          role,
-		Domain:        domain,
-		User:          user,
-		AuthType:      NewAuthTypePostgres(db),
-		ServiceMain:   serviceMain,
+		Authorization:  NewAuthPostgres(db, enforcer, *user, mfa, denylistStore),
+		Session:        session,
+		Mfa:            mfa,
+		WebAuthn:       NewWebAuthnPostgres(db, user, authType),
+		Identity:       identity,
+		EmailChange:    NewEmailChangePostgres(db, user, denylistStore),
+		ApiKey:         apiKey,
+		ServiceAccount: NewServiceAccountPostgres(db, apiKey),
+		Ban:            NewBanPostgres(db, user, denylistStore),
+		UserAdmin:      NewUserAdminPostgres(db, enforcer, user, role, session, identity),
+		Role:           role,
+		Domain:         domain,
+		Object:         object,
+		User:           user,
+		AuthType:       authType,
+		Oidc:           NewOidcPostgres(db, user, domain, authType, denylistStore),
+		ServiceMain:    serviceMain,
+		Throttle:       throttleStore,
+		Denylist:       denylistStore,
 	}
 }
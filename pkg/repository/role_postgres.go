@@ -1,26 +1,35 @@
 package repository
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
+	roleConstant "main-server/pkg/constant/role"
 	tableConstant "main-server/pkg/constant/table"
 	rbacModel "main-server/pkg/model/rbac"
+	userModel "main-server/pkg/model/user"
 	"strconv"
+	"strings"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/jmoiron/sqlx"
+	uuid "github.com/satori/go.uuid"
 )
 
 type RolePostgres struct {
 	db       *sqlx.DB
 	enforcer *casbin.Enforcer
+	domain   *DomainPostgres
+	object   *ObjectPostgres
 }
 
 /* Создание нового экземпляра структуры RolePostgres */
-func NewRolePostgres(db *sqlx.DB, enforcer *casbin.Enforcer) *RolePostgres {
+func NewRolePostgres(db *sqlx.DB, enforcer *casbin.Enforcer, domain *DomainPostgres, object *ObjectPostgres) *RolePostgres {
 	return &RolePostgres{
 		db:       db,
 		enforcer: enforcer,
+		domain:   domain,
+		object:   object,
 	}
 }
 
@@ -69,25 +78,297 @@ func (r *RolePostgres) HasRole(usersId, domainsId int, roleValue string) (bool,
 	return has, err
 }
 
-/* Проверка присутствия у пользователя определённой роли (принадлежность к группе пользователей), в рамках определённого субъекта */
+/*
+* Проверка присутствия у пользователя определённой роли (принадлежность к группе пользователей), в рамках определённого субъекта.
+* Роль, выданная в контексте родительского объекта, действует и в контексте всех его дочерних объектов
+ */
 func (r *RolePostgres) HasRoleWithSubject(userId, domainId int, roleValue, subjectId string) (bool, error) {
 	data, err := r.Get("value", roleValue, true)
 	if err != nil {
 		return false, err
 	}
 
-	// Модель, для формирования строки роли пользователя в рамках конкретного объекта
-	role := rbacModel.GPSubjectModel{
-		RoleId:     data.Id,
-		ObjectUuid: subjectId,
+	// Субъекты, в контексте которых проверяется роль (сам субъект и, если он зарегистрирован как объект, его предки)
+	subjects := []string{subjectId}
+
+	object, err := r.object.Get("value", subjectId, false)
+	if err != nil {
+		return false, err
+	}
+
+	if object != nil {
+		chain, err := r.object.GetAncestors(subjectId)
+		if err != nil {
+			return false, err
+		}
+
+		for _, item := range chain[1:] {
+			subjects = append(subjects, item.Value)
+		}
 	}
 
 	r.enforcer.LoadPolicy()
-	has, err := r.enforcer.HasRoleForUser(
-		strconv.Itoa(userId),
-		role.ToString(),
-		strconv.Itoa(domainId),
-	)
 
-	return has, err
+	for _, item := range subjects {
+		// Модель, для формирования строки роли пользователя в рамках конкретного объекта
+		role := rbacModel.GPSubjectModel{
+			RoleId:     data.Id,
+			ObjectUuid: item,
+		}
+
+		has, err := r.enforcer.HasRoleForUser(
+			strconv.Itoa(userId),
+			role.ToString(),
+			strconv.Itoa(domainId),
+		)
+		if err != nil {
+			return false, err
+		}
+
+		if has {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+/* Получение домена по его UUID (nil - текущий домен пользователя) */
+func (r *RolePostgres) getDomainId(user *userModel.UserIdentityModel, domainUuid *string) (int, error) {
+	if domainUuid == nil {
+		return user.DomainId, nil
+	}
+
+	domain, err := r.domain.Get("uuid", *domainUuid, true)
+	if err != nil {
+		return 0, err
+	}
+
+	return domain.Id, nil
+}
+
+/* Создание новой роли в рамках домена */
+func (r *RolePostgres) Create(user *userModel.UserIdentityModel, data rbacModel.RoleCreateModel) (*rbacModel.RoleModel, error) {
+	domainId, err := r.getDomainId(user, data.DomainUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	var role rbacModel.RoleModel
+	query := fmt.Sprintf(`
+		SELECT * FROM %s WHERE value = $1 AND domains_id = $2 LIMIT 1
+	`, tableConstant.AC_ROLES)
+
+	err = r.db.Get(&role, query, data.Value, domainId)
+	if err == nil {
+		return nil, rbacModel.ErrRoleExists
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	query = fmt.Sprintf(`
+		INSERT INTO %s (uuid, value, description, users_id, domains_id)
+		VALUES ($1, $2, $3, $4, $5) RETURNING *
+	`, tableConstant.AC_ROLES)
+
+	if err = r.db.Get(&role, query, uuid.NewV4().String(), data.Value, data.Description, user.UserId, domainId); err != nil {
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+/* Получение всех ролей домена */
+func (r *RolePostgres) GetAll(user *userModel.UserIdentityModel, domainUuid *string) (*rbacModel.RolesModel, error) {
+	domainId, err := r.getDomainId(user, domainUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	var roles []rbacModel.RoleModel
+	query := fmt.Sprintf("SELECT * FROM %s WHERE domains_id = $1 ORDER BY id", tableConstant.AC_ROLES)
+
+	if err = r.db.Select(&roles, query, domainId); err != nil {
+		return nil, err
+	}
+
+	return &rbacModel.RolesModel{
+		Roles: roles,
+	}, nil
+}
+
+/* Обновление данных о роли (встроенные роли системы не изменяются) */
+func (r *RolePostgres) Update(data rbacModel.RoleUpdateModel) (*rbacModel.RoleModel, error) {
+	current, err := r.Get("uuid", data.Uuid, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if roleConstant.IsBuiltin(current.Value) {
+		return nil, rbacModel.ErrRoleBuiltin
+	}
+
+	var exists bool
+	query := fmt.Sprintf(`
+		SELECT EXISTS (SELECT 1 FROM %s WHERE value = $1 AND domains_id IS NOT DISTINCT FROM $2 AND id <> $3)
+	`, tableConstant.AC_ROLES)
+
+	if err = r.db.Get(&exists, query, data.Value, current.DomainsId, current.Id); err != nil {
+		return nil, err
+	}
+
+	if exists {
+		return nil, rbacModel.ErrRoleExists
+	}
+
+	var role rbacModel.RoleModel
+	query = fmt.Sprintf(`
+		UPDATE %s SET value = $1, description = $2 WHERE id = $3 RETURNING *
+	`, tableConstant.AC_ROLES)
+
+	if err = r.db.Get(&role, query, data.Value, data.Description, current.Id); err != nil {
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+/* Удаление роли (вместе со всеми её выдачами пользователям). Встроенные роли системы не удаляются */
+func (r *RolePostgres) Delete(roleUuid string) (bool, error) {
+	role, err := r.Get("uuid", roleUuid, true)
+	if err != nil {
+		return false, err
+	}
+
+	if roleConstant.IsBuiltin(role.Value) {
+		return false, rbacModel.ErrRoleBuiltin
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", tableConstant.AC_ROLES)
+	if _, err = tx.Exec(query, role.Id); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	// Правила группировки удаляются после фиксации транзакции, чтобы её откат не оставил роль без выдач
+	r.enforcer.LoadPolicy()
+
+	roleId := strconv.Itoa(role.Id)
+	rules := make([][]string, 0)
+
+	for _, item := range r.enforcer.GetGroupingPolicy() {
+		if len(item) < 2 {
+			continue
+		}
+
+		// Учитываются и выдачи роли в контексте объектов
+		if item[1] == roleId || strings.HasPrefix(item[1], roleId+rbacModel.Separator) {
+			rules = append(rules, item)
+		}
+	}
+
+	if len(rules) > 0 {
+		if _, err = r.enforcer.RemoveGroupingPolicies(rules); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+/* Формирование правила группировки для выдачи (или отзыва) роли пользователю */
+func (r *RolePostgres) accessRule(user *userModel.UserIdentityModel, data rbacModel.AccessRoleModel) ([]string, error) {
+	domainId, err := r.getDomainId(user, data.DomainUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := r.Get("uuid", data.RoleUuid, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if (role.DomainsId != nil) && (*role.DomainsId != domainId) {
+		return nil, errors.New("Ошибка: роль не принадлежит указанному домену!")
+	}
+
+	var usersId []int
+	query := fmt.Sprintf("SELECT id FROM %s WHERE uuid = $1 LIMIT 1", tableConstant.U_USERS)
+
+	if err = r.db.Select(&usersId, query, data.UserUuid); err != nil {
+		return nil, err
+	}
+
+	if len(usersId) <= 0 {
+		return nil, errors.New(fmt.Sprintf("Ошибка: пользователя по запросу uuid:%s не найдено!", data.UserUuid))
+	}
+
+	// Привилегированные роли и роли в других доменах выдаёт и отзывает только супер-администратор
+	if (role.Value == roleConstant.ROLE_ADMIN) || (role.Value == roleConstant.ROLE_SUPER_ADMIN) || (domainId != user.DomainId) {
+		has, err := r.HasRole(user.UserId, user.DomainId, roleConstant.ROLE_SUPER_ADMIN)
+		if err != nil {
+			return nil, err
+		}
+
+		if !has {
+			return nil, rbacModel.ErrRoleDenied
+		}
+	}
+
+	subject := strconv.Itoa(role.Id)
+	if data.ObjectUuid != nil {
+		gpsm := rbacModel.GPSubjectModel{
+			RoleId:     role.Id,
+			ObjectUuid: *data.ObjectUuid,
+		}
+
+		// Проверка корректности формируемой строки
+		if _, err = rbacModel.NewGPSubjectModel(gpsm.ToString()); err != nil {
+			return nil, err
+		}
+
+		subject = gpsm.ToString()
+	}
+
+	return []string{strconv.Itoa(usersId[0]), subject, strconv.Itoa(domainId)}, nil
+}
+
+/* Выдача пользователю роли в домене (в том числе в контексте объекта) */
+func (r *RolePostgres) AddRoleForUser(user *userModel.UserIdentityModel, data rbacModel.AccessRoleModel) (bool, error) {
+	rule, err := r.accessRule(user, data)
+	if err != nil {
+		return false, err
+	}
+
+	// Запрет на повышение собственных привилегий
+	if rule[0] == strconv.Itoa(user.UserId) {
+		return false, rbacModel.ErrRoleSelf
+	}
+
+	r.enforcer.LoadPolicy()
+
+	return r.enforcer.AddRoleForUserInDomain(rule[0], rule[1], rule[2])
+}
+
+/* Отзыв у пользователя роли в домене (в том числе в контексте объекта) */
+func (r *RolePostgres) DeleteRoleForUser(user *userModel.UserIdentityModel, data rbacModel.AccessRoleModel) (bool, error) {
+	rule, err := r.accessRule(user, data)
+	if err != nil {
+		return false, err
+	}
+
+	r.enforcer.LoadPolicy()
+
+	return r.enforcer.DeleteRoleForUserInDomain(rule[0], rule[1], rule[2])
 }
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	roleConstant "main-server/pkg/constant/role"
+	rbacModel "main-server/pkg/model/rbac"
+	userModel "main-server/pkg/model/user"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var testRoleColumns = []string{"id", "uuid", "value", "description", "users_id", "domains_id"}
+
+func TestAccessRoleForUser(t *testing.T) {
+	otherDomain := "domain-uuid"
+
+	tests := []struct {
+		name       string
+		superAdmin bool    // Выдаёт ли роль супер-администратор (иначе - администратор)
+		role       string  // Значение выдаваемой роли
+		userId     int     // Пользователь, которому выдаётся роль
+		domainUuid *string // Домен выдачи (nil - текущий)
+		wantErr    error
+	}{
+		{name: "admin grants role", role: roleConstant.ROLE_MANAGER, userId: 10},
+		{name: "admin grants admin", role: roleConstant.ROLE_ADMIN, userId: 10, wantErr: rbacModel.ErrRoleDenied},
+		{name: "admin grants super admin", role: roleConstant.ROLE_SUPER_ADMIN, userId: 10, wantErr: rbacModel.ErrRoleDenied},
+		{name: "admin grants role in other domain", role: roleConstant.ROLE_MANAGER, userId: 10, domainUuid: &otherDomain, wantErr: rbacModel.ErrRoleDenied},
+		{name: "admin grants role to self", role: roleConstant.ROLE_MANAGER, userId: 1, wantErr: rbacModel.ErrRoleSelf},
+		{name: "super admin grants admin", superAdmin: true, role: roleConstant.ROLE_ADMIN, userId: 10},
+		{name: "super admin grants role in other domain", superAdmin: true, role: roleConstant.ROLE_MANAGER, userId: 10, domainUuid: &otherDomain},
+		{name: "super admin grants admin to self", superAdmin: true, role: roleConstant.ROLE_ADMIN, userId: 1, wantErr: rbacModel.ErrRoleSelf},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newTestDB(t)
+
+			// Пользователь 1 - администратор (роль 2) или супер-администратор (роль 3) домена 1
+			rules := "g, 1, 2, 1\n"
+			if tt.superAdmin {
+				rules = "g, 1, 3, 1\n"
+			}
+
+			enforcer := newTestEnforcer(t, rules)
+			r := NewRolePostgres(db, enforcer, NewDomainPostgres(db, enforcer), nil)
+
+			if tt.domainUuid != nil {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM ac_domains WHERE uuid=$1")).WithArgs(*tt.domainUuid).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "uuid", "value"}).AddRow(2, *tt.domainUuid, "other"))
+			}
+
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM ac_roles WHERE uuid=$1")).WithArgs("role-uuid").
+				WillReturnRows(sqlmock.NewRows(testRoleColumns).AddRow(4, "role-uuid", tt.role, "", nil, nil))
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM u_users WHERE uuid = $1")).WithArgs("user-uuid").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(tt.userId))
+
+			if tt.role != roleConstant.ROLE_MANAGER || tt.domainUuid != nil {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM ac_roles WHERE value=$1")).WithArgs(roleConstant.ROLE_SUPER_ADMIN).
+					WillReturnRows(sqlmock.NewRows(testRoleColumns).AddRow(3, "super-uuid", roleConstant.ROLE_SUPER_ADMIN, "", nil, nil))
+			}
+
+			_, err := r.AddRoleForUser(
+				&userModel.UserIdentityModel{UserId: 1, DomainId: 1},
+				rbacModel.AccessRoleModel{UserUuid: "user-uuid", RoleUuid: "role-uuid", DomainUuid: tt.domainUuid},
+			)
+			if err != tt.wantErr {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestRoleCreateLookupError(t *testing.T) {
+	db, mock := newTestDB(t)
+	r := NewRolePostgres(db, nil, nil, nil)
+
+	lookupErr := errors.New("connection lost")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM ac_roles WHERE value = $1 AND domains_id = $2")).
+		WithArgs("manager", 1).WillReturnError(lookupErr)
+
+	// Ошибка проверки на дубликат не должна приводить к созданию роли
+	_, err := r.Create(&userModel.UserIdentityModel{UserId: 1, DomainId: 1}, rbacModel.RoleCreateModel{Value: "manager"})
+	if !errors.Is(err, lookupErr) {
+		t.Fatalf("err = %v, want %v", err, lookupErr)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
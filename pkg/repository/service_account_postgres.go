@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	authConstants "main-server/pkg/constant/auth"
+	tableConstants "main-server/pkg/constant/table"
+	userModel "main-server/pkg/model/user"
+
+	"github.com/jmoiron/sqlx"
+	uuid "github.com/satori/go.uuid"
+)
+
+type ServiceAccountPostgres struct {
+	db     *sqlx.DB
+	apiKey *ApiKeyPostgres
+}
+
+/* Создание нового экземпляра структуры ServiceAccountPostgres */
+func NewServiceAccountPostgres(db *sqlx.DB, apiKey *ApiKeyPostgres) *ServiceAccountPostgres {
+	return &ServiceAccountPostgres{
+		db:     db,
+		apiKey: apiKey,
+	}
+}
+
+/*
+* Создание сервисного аккаунта. Для аккаунта создаётся пользователь без пароля и с
+* недоставляемым email-адресом, поэтому войти в систему можно только по API-ключу
+ */
+func (r *ServiceAccountPostgres) Create(actor *userModel.UserIdentityModel, data userModel.ServiceAccountCreateModel) (*userModel.ServiceAccountModel, error) {
+	name := strings.TrimSpace(data.Name)
+	if name == "" {
+		return nil, errors.New("Ошибка: название сервисного аккаунта не может быть пустым")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	account := userModel.ServiceAccountModel{
+		Uuid:        uuid.NewV4().String(),
+		UsersUuid:   uuid.NewV4().String(),
+		Name:        name,
+		Description: data.Description,
+		CreatedAt:   time.Now(),
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (email, password, uuid) VALUES ($1, '', $2) RETURNING id", tableConstants.U_USERS)
+	email := fmt.Sprintf("%s@%s", account.UsersUuid, authConstants.SERVICE_ACCOUNT_EMAIL_DOMAIN)
+	if err = tx.QueryRow(query, email, account.UsersUuid).Scan(&account.UsersId); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	query = fmt.Sprintf(`
+		INSERT INTO %s (uuid, users_id, name, description, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id
+	`, tableConstants.U_SERVICE_ACCOUNTS)
+	err = tx.QueryRow(query,
+		account.Uuid, account.UsersId, account.Name, account.Description, actor.UserId, account.CreatedAt,
+	).Scan(&account.Id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+/* Получение сервисного аккаунта по UUID */
+func (r *ServiceAccountPostgres) Get(accountUuid string) (*userModel.ServiceAccountModel, error) {
+	var accounts []userModel.ServiceAccountModel
+	query := fmt.Sprintf(`
+		SELECT ts.id, ts.uuid, ts.users_id, tu.uuid AS users_uuid, ts.name, ts.description, ts.created_at
+		FROM %s ts INNER JOIN %s tu ON tu.id = ts.users_id
+		WHERE ts.uuid = $1 AND ts.deleted_at IS NULL LIMIT 1
+	`, tableConstants.U_SERVICE_ACCOUNTS, tableConstants.U_USERS)
+
+	if err := r.db.Select(&accounts, query, accountUuid); err != nil {
+		return nil, err
+	}
+
+	if len(accounts) <= 0 {
+		return nil, userModel.ErrServiceAccountMissing
+	}
+
+	return &accounts[0], nil
+}
+
+/* Получение всех сервисных аккаунтов */
+func (r *ServiceAccountPostgres) GetAll() (*userModel.ServiceAccountsModel, error) {
+	var accounts []userModel.ServiceAccountModel
+	query := fmt.Sprintf(`
+		SELECT ts.id, ts.uuid, ts.users_id, tu.uuid AS users_uuid, ts.name, ts.description, ts.created_at
+		FROM %s ts INNER JOIN %s tu ON tu.id = ts.users_id
+		WHERE ts.deleted_at IS NULL ORDER BY ts.created_at
+	`, tableConstants.U_SERVICE_ACCOUNTS, tableConstants.U_USERS)
+
+	if err := r.db.Select(&accounts, query); err != nil {
+		return nil, err
+	}
+
+	return &userModel.ServiceAccountsModel{
+		ServiceAccounts: accounts,
+	}, nil
+}
+
+/* Удаление сервисного аккаунта (запись остаётся в истории, все его API-ключи отзываются) */
+func (r *ServiceAccountPostgres) Delete(accountUuid string) (bool, error) {
+	account, err := r.Get(accountUuid)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET deleted_at = NOW() WHERE id = $1", tableConstants.U_SERVICE_ACCOUNTS)
+	if _, err = tx.Exec(query, account.Id); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET revoked_at = NOW() WHERE users_id = $1 AND revoked_at IS NULL", tableConstants.U_API_KEYS)
+	if _, err = tx.Exec(query, account.UsersId); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* Создание API-ключа сервисного аккаунта */
+func (r *ServiceAccountPostgres) CreateKey(actor *userModel.UserIdentityModel, data userModel.ServiceAccountApiKeyCreateModel) (*userModel.ApiKeyCreatedModel, error) {
+	account, err := r.Get(data.ServiceAccountUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.apiKey.Create(actor, account.UsersId, data.ApiKeyCreateModel)
+}
+
+/* Получение всех API-ключей сервисного аккаунта */
+func (r *ServiceAccountPostgres) GetKeys(accountUuid string) (*userModel.ApiKeysModel, error) {
+	account, err := r.Get(accountUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.apiKey.GetAll(account.UsersId)
+}
+
+/* Отзыв API-ключа сервисного аккаунта */
+func (r *ServiceAccountPostgres) RevokeKey(data userModel.ServiceAccountApiKeyModel) (bool, error) {
+	account, err := r.Get(data.ServiceAccountUuid)
+	if err != nil {
+		return false, err
+	}
+
+	return r.apiKey.Revoke(account.UsersId, data.Uuid)
+}
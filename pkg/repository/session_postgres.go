@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	authConstants "main-server/pkg/constant/auth"
+	tableConstants "main-server/pkg/constant/table"
+	userModel "main-server/pkg/model/user"
+	"main-server/pkg/service/denylist"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/jmoiron/sqlx"
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type SessionPostgres struct {
+	db       *sqlx.DB
+	denylist denylist.Store
+}
+
+/* Создание нового экземпляра структуры SessionPostgres */
+func NewSessionPostgres(db *sqlx.DB, store denylist.Store) *SessionPostgres {
+	return &SessionPostgres{
+		db:       db,
+		denylist: store,
+	}
+}
+
+/* Получение всех активных сессий пользователя */
+func (r *SessionPostgres) GetAll(userId int, currentUuid string) (*userModel.SessionsModel, error) {
+	var sessions []userModel.SessionModel
+	query := fmt.Sprintf(`
+		SELECT uuid, user_agent, ip, created_at, refreshed_at FROM %s
+		WHERE users_id = $1 ORDER BY refreshed_at DESC
+	`, tableConstants.U_TOKENS)
+
+	if err := r.db.Select(&sessions, query, userId); err != nil {
+		return nil, err
+	}
+
+	for index := range sessions {
+		sessions[index].IsCurrent = sessions[index].Uuid == currentUuid
+	}
+
+	return &userModel.SessionsModel{
+		Sessions: sessions,
+	}, nil
+}
+
+/* Завершение определённой сессии пользователя */
+func (r *SessionPostgres) Delete(userId int, sessionUuid string) (bool, error) {
+	tokens, err := deleteSessions(r.db, "users_id = $1 AND uuid = $2", userId, sessionUuid)
+	if err != nil {
+		return false, err
+	}
+
+	if len(tokens) <= 0 {
+		return false, errors.New("Ошибка: сессии пользователя с данным идентификатором не существует!")
+	}
+
+	if err = denyAccessTokens(r.denylist, tokens); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* Завершение всех сессий пользователя, кроме текущей */
+func (r *SessionPostgres) DeleteOthers(userId int, currentUuid string) (bool, error) {
+	tokens, err := deleteSessions(r.db, "users_id = $1 AND uuid <> $2", userId, currentUuid)
+	if err != nil {
+		return false, err
+	}
+
+	if err = denyAccessTokens(r.denylist, tokens); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+/*
+* Завершение сессий пользователя по условию (condition - выражение WHERE для таблицы сессий).
+* Возвращаются токены доступа завершённых сессий: их нужно занести в список отозванных (denyAccessTokens)
+* после фиксации транзакции, чтобы при её откате не были отозваны токены сохранившихся сессий
+ */
+func deleteSessions(q queryer, condition string, args ...interface{}) ([]string, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s RETURNING access_token", tableConstants.U_TOKENS, condition)
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []string
+	for rows.Next() {
+		var token string
+		if err := rows.Scan(&token); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+/* Занесение токенов доступа завершённых сессий в список отозванных, чтобы они отклонялись сразу */
+func denyAccessTokens(store denylist.Store, tokens []string) error {
+	for _, token := range tokens {
+		if err := denyAccessToken(store, token); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/* Занесение токена доступа в список отозванных до истечения его срока действия */
+func denyAccessToken(store denylist.Store, token string) error {
+	// Токен выдан данным сервером и хранится в базе данных, поэтому подпись не проверяется
+	claims := &tokenClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(token, claims); err != nil {
+		logrus.Warnf("failed to parse access token of deleted session: %s", err.Error())
+		return nil
+	}
+
+	expiresAt := time.Unix(claims.ExpiresAt, 0)
+	if claims.Id == "" || !expiresAt.After(time.Now()) {
+		return nil
+	}
+
+	return store.Add(claims.Id, expiresAt)
+}
+
+/* Хэширование токена для хранения в базе данных */
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+/*
+* Создание новой сессии пользователя (пары токенов для конкретного устройства)
+* в рамках транзакции. Заблокированному пользователю сессия не выдаётся
+ */
+func createSession(
+	tx *sql.Tx,
+	usersId int, usersUuid, authTypesUuid string,
+	accessApi, refreshApi *string,
+	info userModel.SessionInfoModel,
+) (userModel.UserAuthDataModel, error) {
+	if err := checkBan(tx, usersId); err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	sessionUuid := uuid.NewV4().String()
+
+	// Генерация пары токенов (токен доступа и токен обновления)
+	accessToken, err := GenerateAccessToken(usersUuid, authTypesUuid, sessionUuid, accessApi)
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	refreshToken, err := GenerateToken(usersUuid, authTypesUuid, sessionUuid, refreshApi, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_refresh"))
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	currentDate := time.Now()
+	query := fmt.Sprintf(`
+		INSERT INTO %s (uuid, users_id, access_token, refresh_token, user_agent, ip, created_at, refreshed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, tableConstants.U_TOKENS)
+
+	if _, err = tx.Exec(query, sessionUuid, usersId, accessToken, refreshToken, info.UserAgent, info.Ip, currentDate, currentDate); err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	return userModel.UserAuthDataModel{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	tableConstants "main-server/pkg/constant/table"
+	"main-server/pkg/service/throttle"
+
+	"github.com/jmoiron/sqlx"
+)
+
+/* Хранилище счётчиков неудачных попыток в PostgreSQL (общее для всех экземпляров сервера) */
+type ThrottlePostgres struct {
+	db *sqlx.DB
+}
+
+/* Создание нового экземпляра структуры ThrottlePostgres */
+func NewThrottlePostgres(db *sqlx.DB) *ThrottlePostgres {
+	return &ThrottlePostgres{db: db}
+}
+
+func (r *ThrottlePostgres) Get(key string, now time.Time, window time.Duration) (throttle.Entry, error) {
+	var entry throttle.Entry
+	var lockedUntil sql.NullTime
+
+	query := fmt.Sprintf("SELECT failures, last_failure, locked_until FROM %s WHERE attempt_key = $1", tableConstants.U_LOGIN_ATTEMPTS)
+	err := r.db.QueryRow(query, key).Scan(&entry.Failures, &entry.LastFailure, &lockedUntil)
+	if err == sql.ErrNoRows {
+		return throttle.Entry{}, nil
+	}
+
+	if err != nil {
+		return throttle.Entry{}, err
+	}
+
+	entry.LockedUntil = lockedUntil.Time
+	if throttle.IsExpired(entry, now, window) {
+		return throttle.Entry{}, nil
+	}
+
+	return entry, nil
+}
+
+func (r *ThrottlePostgres) Fail(key string, now time.Time, window time.Duration, backoff throttle.Backoff) (throttle.Entry, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return throttle.Entry{}, err
+	}
+
+	// Устаревший счётчик начинается заново, иначе увеличивается (строка блокируется до конца транзакции)
+	var entry throttle.Entry
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s (attempt_key, failures, last_failure) VALUES ($1, 1, $2)
+		ON CONFLICT (attempt_key) DO UPDATE SET
+			failures = CASE
+				WHEN %[1]s.last_failure < $3 AND (%[1]s.locked_until IS NULL OR %[1]s.locked_until <= $2) THEN 1
+				ELSE %[1]s.failures + 1
+			END,
+			last_failure = $2
+		RETURNING failures, last_failure
+	`, tableConstants.U_LOGIN_ATTEMPTS)
+	if err = tx.QueryRow(query, key, now, now.Add(-window)).Scan(&entry.Failures, &entry.LastFailure); err != nil {
+		tx.Rollback()
+		return throttle.Entry{}, err
+	}
+
+	if delay := backoff(entry.Failures); delay > 0 {
+		entry.LockedUntil = now.Add(delay)
+
+		query = fmt.Sprintf("UPDATE %s SET locked_until = $1 WHERE attempt_key = $2", tableConstants.U_LOGIN_ATTEMPTS)
+		if _, err = tx.Exec(query, entry.LockedUntil, key); err != nil {
+			tx.Rollback()
+			return throttle.Entry{}, err
+		}
+	}
+
+	// Удаление устаревших счётчиков
+	query = fmt.Sprintf(`
+		DELETE FROM %s WHERE last_failure < $1 AND (locked_until IS NULL OR locked_until <= $2)
+	`, tableConstants.U_LOGIN_ATTEMPTS)
+	if _, err = tx.Exec(query, now.Add(-window), now); err != nil {
+		tx.Rollback()
+		return throttle.Entry{}, err
+	}
+
+	return entry, tx.Commit()
+}
+
+func (r *ThrottlePostgres) Reset(key string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE attempt_key = $1", tableConstants.U_LOGIN_ATTEMPTS)
+	_, err := r.db.Exec(query, key)
+
+	return err
+}
@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"main-server/pkg/constant"
+	tableConstants "main-server/pkg/constant/table"
+	rbacModel "main-server/pkg/model/rbac"
+	userModel "main-server/pkg/model/user"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type UserAdminPostgres struct {
+	db       *sqlx.DB
+	enforcer *casbin.Enforcer
+	user     *UserPostgres
+	role     *RolePostgres
+	session  *SessionPostgres
+	identity *IdentityPostgres
+}
+
+/* Создание нового экземпляра структуры UserAdminPostgres */
+func NewUserAdminPostgres(
+	db *sqlx.DB, enforcer *casbin.Enforcer,
+	user *UserPostgres, role *RolePostgres, session *SessionPostgres, identity *IdentityPostgres,
+) *UserAdminPostgres {
+	return &UserAdminPostgres{
+		db:       db,
+		enforcer: enforcer,
+		user:     user,
+		role:     role,
+		session:  session,
+		identity: identity,
+	}
+}
+
+/* Запрос данных пользователя для списка (условие подставляется вместо %[6]s) */
+const userListQuery = `
+	SELECT tu.id, tu.uuid, tu.email, COALESCE(td.data, '{}'::jsonb) AS data,
+		COALESCE((SELECT tv.is_activated FROM %[2]s tv WHERE tv.users_id = tu.id LIMIT 1), true) AS is_activated,
+		EXISTS (
+			SELECT 1 FROM %[3]s tb WHERE tb.users_id = tu.id AND tb.lifted_at IS NULL
+			AND (tb.expires_at IS NULL OR tb.expires_at > NOW())
+		) AS is_banned,
+		ARRAY(
+			SELECT ta.value FROM %[4]s tua INNER JOIN %[5]s ta ON ta.id = tua.auth_types_id
+			WHERE tua.users_id = tu.id ORDER BY ta.value
+		) AS auth_types
+	FROM %[1]s tu LEFT JOIN %[7]s td ON td.users_id = tu.id
+	WHERE %[6]s
+`
+
+/*
+* Поиск пользователей с фильтрами и постраничным выводом. Сервисные аккаунты в результаты
+* не попадают. Пользователи упорядочены по идентификатору, курсор - последний пользователь страницы
+ */
+func (r *UserAdminPostgres) Search(actor *userModel.UserIdentityModel, data userModel.UserSearchModel) (*userModel.UsersListModel, error) {
+	limit := data.Limit
+	if limit <= 0 {
+		limit = constant.PAGE_LIMIT_DEFAULT
+	}
+
+	if limit > constant.PAGE_LIMIT_MAX {
+		limit = constant.PAGE_LIMIT_MAX
+	}
+
+	result := &userModel.UsersListModel{
+		Users:  []userModel.UserListItemModel{},
+		Offset: data.Offset,
+	}
+	result.Limit = limit
+
+	conditions := []string{
+		fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %s ts WHERE ts.users_id = tu.id)", tableConstants.U_SERVICE_ACCOUNTS),
+	}
+	args := []interface{}{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if search := strings.TrimSpace(data.Search); search != "" {
+		conditions = append(conditions, fmt.Sprintf(`(
+			tu.email ILIKE %[1]s OR td.data->>'name' ILIKE %[1]s OR
+			td.data->>'surname' ILIKE %[1]s OR td.data->>'nickname' ILIKE %[1]s
+		)`, arg("%"+escapeLike(search)+"%")))
+	}
+
+	if data.Role != "" {
+		usersIds, err := r.getRoleUsers(actor.DomainId, data.Role)
+		if err != nil {
+			return nil, err
+		}
+
+		conditions = append(conditions, fmt.Sprintf("tu.id = ANY(%s)", arg(pq.Array(usersIds))))
+	}
+
+	if data.AuthType != "" {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM %s tua INNER JOIN %s ta ON ta.id = tua.auth_types_id
+			WHERE tua.users_id = tu.id AND ta.value = %s
+		)`, tableConstants.U_USERS_AUTH_TYPES, tableConstants.U_AUTH_TYPES, arg(data.AuthType)))
+	}
+
+	// Пользователь без записи о подтверждении считается подтверждённым (см. isActivated)
+	if data.Activated != nil {
+		conditions = append(conditions, fmt.Sprintf(
+			"COALESCE((SELECT tv.is_activated FROM %s tv WHERE tv.users_id = tu.id LIMIT 1), true) = %s",
+			tableConstants.U_ACTIVATIONS, arg(*data.Activated),
+		))
+	}
+
+	if data.Banned != nil {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM %s tb WHERE tb.users_id = tu.id AND tb.lifted_at IS NULL
+			AND (tb.expires_at IS NULL OR tb.expires_at > NOW())
+		) = %s`, tableConstants.U_BANS, arg(*data.Banned)))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT COUNT(*) FROM %s tu LEFT JOIN %s td ON td.users_id = tu.id WHERE %s
+	`, tableConstants.U_USERS, tableConstants.U_USERS_DATA, strings.Join(conditions, " AND "))
+	if err := r.db.Get(&result.Count, query, args...); err != nil {
+		return nil, err
+	}
+
+	// Курсор сужает выборку, но не влияет на общее количество найденных пользователей
+	if data.Cursor != "" {
+		cursor, err := decodeCursor(data.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		conditions = append(conditions, fmt.Sprintf("tu.id > %s", arg(cursor)))
+	}
+
+	query = userListSelect(strings.Join(conditions, " AND ")) + fmt.Sprintf(" ORDER BY tu.id LIMIT %s", arg(limit+1))
+	if data.Cursor == "" && data.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET %s", arg(data.Offset))
+	}
+
+	if err := r.db.Select(&result.Users, query, args...); err != nil {
+		return nil, err
+	}
+
+	// Лишняя запись показывает, что за текущей страницей есть следующая
+	if len(result.Users) > limit {
+		result.Users = result.Users[:limit]
+
+		cursor := encodeCursor(result.Users[limit-1].Id)
+		result.NextCursor = &cursor
+	}
+
+	return result, nil
+}
+
+/* Получение полных сведений о пользователе: профиль, роли, типы авторизации, блокировка и сессии */
+func (r *UserAdminPostgres) Get(actor *userModel.UserIdentityModel, usersUuid string) (*userModel.UserDetailModel, error) {
+	var users []userModel.UserListItemModel
+	if err := r.db.Select(&users, userListSelect("tu.uuid = $1"), usersUuid); err != nil {
+		return nil, err
+	}
+
+	if len(users) <= 0 {
+		return nil, fmt.Errorf("Ошибка: пользователя по запросу uuid:%s не найдено!", usersUuid)
+	}
+
+	detail := &userModel.UserDetailModel{
+		UserListItemModel: users[0],
+	}
+
+	ban, err := getActiveBan(r.db, detail.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	detail.Ban = ban
+
+	roles, err := r.user.GetAllRoles(userModel.UserIdentityModel{
+		UserId:     detail.Id,
+		UserUuid:   detail.Uuid,
+		DomainId:   actor.DomainId,
+		DomainUuid: actor.DomainUuid,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	detail.Roles = roles.Roles
+
+	identities, err := r.identity.GetAll(detail.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	detail.Identities = identities.Identities
+
+	sessions, err := r.session.GetAll(detail.Id, "")
+	if err != nil {
+		return nil, err
+	}
+
+	detail.Sessions = sessions.Sessions
+
+	return detail, nil
+}
+
+/* Получение идентификаторов пользователей, которым роль выдана в домене (в том числе в контексте объектов) */
+func (r *UserAdminPostgres) getRoleUsers(domainId int, roleValue string) ([]int, error) {
+	role, err := r.role.Get("value", roleValue, true)
+	if err != nil {
+		return nil, err
+	}
+
+	roleId := strconv.Itoa(role.Id)
+	usersIds := []int{}
+
+	for _, rule := range r.enforcer.GetFilteredGroupingPolicy(2, strconv.Itoa(domainId)) {
+		if len(rule) < 2 || strings.Split(rule[1], rbacModel.Separator)[0] != roleId {
+			continue
+		}
+
+		if id, err := strconv.Atoi(rule[0]); err == nil {
+			usersIds = append(usersIds, id)
+		}
+	}
+
+	return usersIds, nil
+}
+
+/* Формирование запроса данных пользователей с условием */
+func userListSelect(condition string) string {
+	return fmt.Sprintf(userListQuery,
+		tableConstants.U_USERS, tableConstants.U_ACTIVATIONS, tableConstants.U_BANS,
+		tableConstants.U_USERS_AUTH_TYPES, tableConstants.U_AUTH_TYPES, condition, tableConstants.U_USERS_DATA,
+	)
+}
+
+/* Экранирование специальных символов шаблона LIKE */
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+/* Курсор постраничного вывода - идентификатор последнего пользователя страницы */
+func encodeCursor(id int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(id)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	value, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, userModel.ErrInvalidCursor
+	}
+
+	id, err := strconv.Atoi(string(value))
+	if err != nil {
+		return 0, userModel.ErrInvalidCursor
+	}
+
+	return id, nil
+}
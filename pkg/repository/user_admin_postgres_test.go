@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"regexp"
+	"testing"
+
+	"main-server/pkg/constant"
+	userModel "main-server/pkg/model/user"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var testUserListColumns = []string{"id", "uuid", "email", "data", "is_activated", "is_banned", "auth_types"}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+/* Строки результатов поиска с идентификаторами ids */
+func testUserListRows(ids ...int) *sqlmock.Rows {
+	rows := sqlmock.NewRows(testUserListColumns)
+	for _, id := range ids {
+		rows.AddRow(id, "user-uuid", "user@example.com", `{"name": "User"}`, true, false, "{local}")
+	}
+
+	return rows
+}
+
+func TestCursor(t *testing.T) {
+	for _, id := range []int{0, 1, 42, 1 << 30} {
+		got, err := decodeCursor(encodeCursor(id))
+		if err != nil || got != id {
+			t.Fatalf("decodeCursor(encodeCursor(%d)) = %d, %v", id, got, err)
+		}
+	}
+
+	for _, cursor := range []string{"!!", "YWJj" /* abc */, ""} {
+		if _, err := decodeCursor(cursor); err != userModel.ErrInvalidCursor {
+			t.Fatalf("decodeCursor(%q) err = %v, want %v", cursor, err, userModel.ErrInvalidCursor)
+		}
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := map[string]string{
+		"user":      "user",
+		"50%":       `50\%`,
+		"first_one": `first\_one`,
+		`a\b`:       `a\\b`,
+		`%_\`:       `\%\_\\`,
+	}
+
+	for value, want := range tests {
+		if got := escapeLike(value); got != want {
+			t.Fatalf("escapeLike(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestUserAdminSearchFilters(t *testing.T) {
+	limit := constant.PAGE_LIMIT_DEFAULT + 1
+
+	tests := []struct {
+		name       string
+		data       userModel.UserSearchModel
+		condition  string         // Фрагмент условия, который должен быть в обоих запросах
+		countArgs  []driver.Value // Аргументы запроса количества
+		selectArgs []driver.Value // Аргументы запроса страницы
+		paging     string         // Фрагмент постраничного вывода в запросе страницы
+	}{
+		{
+			name:       "no filters",
+			condition:  "NOT EXISTS (SELECT 1 FROM u_service_accounts",
+			selectArgs: []driver.Value{limit},
+			paging:     "ORDER BY tu.id LIMIT $1",
+		},
+		{
+			name:       "search is escaped",
+			data:       userModel.UserSearchModel{Search: " 50%_off "},
+			condition:  "tu.email ILIKE $1",
+			countArgs:  []driver.Value{`%50\%\_off%`},
+			selectArgs: []driver.Value{`%50\%\_off%`, limit},
+			paging:     "LIMIT $2",
+		},
+		{
+			name:       "auth type",
+			data:       userModel.UserSearchModel{AuthType: "google"},
+			condition:  "ta.value = $1",
+			countArgs:  []driver.Value{"google"},
+			selectArgs: []driver.Value{"google", limit},
+		},
+		{
+			name:       "not activated",
+			data:       userModel.UserSearchModel{Activated: boolPtr(false)},
+			condition:  "LIMIT 1), true) = $1",
+			countArgs:  []driver.Value{false},
+			selectArgs: []driver.Value{false, limit},
+		},
+		{
+			name:       "banned",
+			data:       userModel.UserSearchModel{Banned: boolPtr(true)},
+			condition:  "(tb.expires_at IS NULL OR tb.expires_at > NOW())",
+			countArgs:  []driver.Value{true},
+			selectArgs: []driver.Value{true, limit},
+		},
+		{
+			name:       "combined filters",
+			data:       userModel.UserSearchModel{Search: "user", AuthType: "local", Banned: boolPtr(false)},
+			condition:  "ta.value = $2",
+			countArgs:  []driver.Value{"%user%", "local", false},
+			selectArgs: []driver.Value{"%user%", "local", false, limit},
+			paging:     "LIMIT $4",
+		},
+		{
+			name:       "limit is clamped",
+			data:       userModel.UserSearchModel{Limit: 1000},
+			selectArgs: []driver.Value{constant.PAGE_LIMIT_MAX + 1},
+		},
+		{
+			name:       "offset",
+			data:       userModel.UserSearchModel{Limit: 10, Offset: 40},
+			selectArgs: []driver.Value{11, 40},
+			paging:     "LIMIT $1 OFFSET $2",
+		},
+		{
+			name:       "cursor replaces offset",
+			data:       userModel.UserSearchModel{Limit: 10, Offset: 40, Cursor: encodeCursor(15), Banned: boolPtr(true)},
+			countArgs:  []driver.Value{true},
+			selectArgs: []driver.Value{true, 15, 11},
+			paging:     "AND tu.id > $2 ORDER BY tu.id LIMIT $3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newTestDB(t)
+			r := NewUserAdminPostgres(db, nil, nil, nil, nil, nil)
+
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*)") + ".*" + regexp.QuoteMeta(tt.condition)).
+				WithArgs(tt.countArgs...).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+			mock.ExpectQuery(regexp.QuoteMeta(tt.condition) + ".*" + regexp.QuoteMeta(tt.paging)).
+				WithArgs(tt.selectArgs...).
+				WillReturnRows(testUserListRows(1, 2, 3))
+
+			result, err := r.Search(&userModel.UserIdentityModel{DomainId: 1}, tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Count != 3 || len(result.Users) != 3 {
+				t.Fatalf("count = %d, users = %d", result.Count, len(result.Users))
+			}
+
+			if tt.paging == "LIMIT $1 OFFSET $2" && result.Offset != tt.data.Offset {
+				t.Fatalf("offset = %d, want %d", result.Offset, tt.data.Offset)
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestUserAdminSearchRole(t *testing.T) {
+	db, mock := newTestDB(t)
+
+	// Роль 3 выдана пользователю 10 в домене, пользователю 11 - в контексте объекта, пользователю 13 - в другом домене
+	enforcer := newTestEnforcer(t, "g, 10, 3, 1\ng, 11, 3;"+testRootUuid+", 1\ng, 12, 4, 1\ng, 13, 3, 2\n")
+	r := NewUserAdminPostgres(db, enforcer, nil, NewRolePostgres(db, enforcer, nil, nil), nil, nil)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM ac_roles WHERE value=$1")).WithArgs("manager").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "uuid", "value", "description", "users_id", "domains_id"}).
+			AddRow(3, "role-uuid", "manager", "", nil, nil),
+	)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*)") + ".*" + regexp.QuoteMeta("tu.id = ANY($1)")).
+		WithArgs("{10,11}").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta("tu.id = ANY($1)")).
+		WithArgs("{10,11}", constant.PAGE_LIMIT_DEFAULT+1).
+		WillReturnRows(testUserListRows(10, 11))
+
+	result, err := r.Search(&userModel.UserIdentityModel{DomainId: 1}, userModel.UserSearchModel{Role: "manager"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Count != 2 || len(result.Users) != 2 {
+		t.Fatalf("count = %d, users = %d", result.Count, len(result.Users))
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUserAdminSearchPages(t *testing.T) {
+	tests := []struct {
+		name       string
+		rows       []int // Идентификаторы, возвращённые запросом страницы (limit + 1 записей - есть следующая страница)
+		users      int
+		nextCursor *int
+	}{
+		{name: "next page", rows: []int{5, 7, 9}, users: 2, nextCursor: intPtr(7)},
+		{name: "last page", rows: []int{5, 7}, users: 2},
+		{name: "empty page", rows: []int{}, users: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newTestDB(t)
+			r := NewUserAdminPostgres(db, nil, nil, nil, nil, nil)
+
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*)")).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(10))
+			mock.ExpectQuery(regexp.QuoteMeta("ORDER BY tu.id")).WillReturnRows(testUserListRows(tt.rows...))
+
+			result, err := r.Search(&userModel.UserIdentityModel{DomainId: 1}, userModel.UserSearchModel{Limit: 2})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(result.Users) != tt.users {
+				t.Fatalf("users = %d, want %d", len(result.Users), tt.users)
+			}
+
+			if tt.nextCursor == nil {
+				if result.NextCursor != nil {
+					t.Fatalf("unexpected next cursor %s", *result.NextCursor)
+				}
+				return
+			}
+
+			if result.NextCursor == nil {
+				t.Fatal("next cursor is missing")
+			}
+
+			// Следующая страница начинается после последнего пользователя текущей
+			if id, err := decodeCursor(*result.NextCursor); err != nil || id != *tt.nextCursor {
+				t.Fatalf("next cursor = %d, %v, want %d", id, err, *tt.nextCursor)
+			}
+		})
+	}
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		db, mock := newTestDB(t)
+		r := NewUserAdminPostgres(db, nil, nil, nil, nil, nil)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*)")).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(10))
+
+		_, err := r.Search(&userModel.UserIdentityModel{DomainId: 1}, userModel.UserSearchModel{Cursor: "!!"})
+		if err != userModel.ErrInvalidCursor {
+			t.Fatalf("err = %v, want %v", err, userModel.ErrInvalidCursor)
+		}
+	})
+}
@@ -15,8 +15,6 @@ import (
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
-	"github.com/spf13/viper"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type UserPostgres struct {
@@ -98,7 +96,11 @@ func (r *UserPostgres) GetProfile(c *gin.Context) (userModel.UserProfileModel, e
 func (r *UserPostgres) UpdateProfile(c *gin.Context, data userModel.UserProfileUpdateDataModel) (userModel.UserDataDbModel, error) {
 	usersId, _ := c.Get(middlewareConstant.USER_CTX)
 
-	userJsonb, err := json.Marshal(data)
+	// Пароль не должен попасть в данные профиля
+	profile := data
+	profile.Password = nil
+
+	userJsonb, err := json.Marshal(profile)
 	if err != nil {
 		return userModel.UserDataDbModel{}, err
 	}
@@ -140,18 +142,9 @@ func (r *UserPostgres) UpdateProfile(c *gin.Context, data userModel.UserProfileU
 		return userModel.UserDataDbModel{}, err
 	}
 
-	// Change password
+	// Change password (policy and history are checked against the updated profile data)
 	if data.Password != nil {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(*data.Password), viper.GetInt("crypt.cost"))
-		if err != nil {
-			tx.Rollback()
-			return userModel.UserDataDbModel{}, err
-		}
-
-		query := fmt.Sprintf("UPDATE %s SET password=$1 WHERE id=$2", tableConstant.U_USERS)
-		_, err = r.db.Exec(query, string(hashedPassword), usersId)
-
-		if err != nil {
+		if err = changePassword(tx, usersId.(int), *data.Password, nil); err != nil {
 			tx.Rollback()
 			return userModel.UserDataDbModel{}, err
 		}
@@ -176,12 +169,12 @@ func (r *UserPostgres) UpdateProfileImage(userIdentity *userModel.UserIdentityMo
 
 	// Запрос на обновление информации о изображении пользователя
 	query := fmt.Sprintf(
-		`UPDATE %s u SET data = jsonb_set(data, '{avatar}', to_jsonb($1::text), true) WHERE u.id = $2`,
+		`UPDATE %s u SET data = jsonb_set(data, '{avatar}', to_jsonb($1::text), true) WHERE u.users_id = $2`,
 		tableConstant.U_USERS_DATA,
 	)
 
 	// Выполнение запроса на обновление
-	if _, err = r.db.Exec(query, resource.Filepath, userIdentity.UserId); err != nil {
+	if _, err = tx.Exec(query, resource.Filepath, userIdentity.UserId); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -0,0 +1,459 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	config "main-server/config"
+	authConstants "main-server/pkg/constant/auth"
+	tableConstants "main-server/pkg/constant/table"
+	userModel "main-server/pkg/model/user"
+
+	"github.com/duo-labs/webauthn/protocol"
+	"github.com/duo-labs/webauthn/webauthn"
+	"github.com/jmoiron/sqlx"
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+)
+
+/* Назначение данных церемонии WebAuthn */
+const (
+	webAuthnPurposeRegister = "register"
+	webAuthnPurposeLogin    = "login"
+)
+
+type WebAuthnPostgres struct {
+	db       *sqlx.DB
+	user     *UserPostgres
+	authType *AuthTypePostgres
+}
+
+/* Создание нового экземпляра структуры WebAuthnPostgres */
+func NewWebAuthnPostgres(db *sqlx.DB, user *UserPostgres, authType *AuthTypePostgres) *WebAuthnPostgres {
+	return &WebAuthnPostgres{
+		db:       db,
+		user:     user,
+		authType: authType,
+	}
+}
+
+/* Пользователь с его ключами доступа (реализация интерфейса webauthn.User) */
+type webAuthnUser struct {
+	user        *userModel.UserModel
+	credentials []userModel.WebAuthnCredentialModel
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return []byte(u.user.Uuid)
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	return u.user.Email
+}
+
+func (u *webAuthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.credentials))
+	for _, item := range u.credentials {
+		credentials = append(credentials, webauthn.Credential{
+			ID:              item.CredentialId,
+			PublicKey:       item.PublicKey,
+			AttestationType: item.AttestationType,
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    item.Aaguid,
+				SignCount: uint32(item.SignCount),
+			},
+		})
+	}
+
+	return credentials
+}
+
+/* Получение всех ключей доступа пользователя */
+func (r *WebAuthnPostgres) GetAll(userId int) (*userModel.WebAuthnCredentialsModel, error) {
+	credentials, err := r.getCredentials(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userModel.WebAuthnCredentialsModel{
+		Credentials: credentials,
+	}, nil
+}
+
+/* Начало регистрации нового ключа доступа */
+func (r *WebAuthnPostgres) BeginRegistration(userId int) (*userModel.WebAuthnBeginModel, error) {
+	user, err := r.getUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Повторная регистрация уже существующих ключей запрещается
+	var exclusions []protocol.CredentialDescriptor
+	for _, item := range user.WebAuthnCredentials() {
+		exclusions = append(exclusions, protocol.CredentialDescriptor{
+			Type:         protocol.PublicKeyCredentialType,
+			CredentialID: item.ID,
+		})
+	}
+
+	options, session, err := config.AppWebAuthn.BeginRegistration(user, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		return nil, err
+	}
+
+	sessionUuid, err := r.saveSession(userId, webAuthnPurposeRegister, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userModel.WebAuthnBeginModel{
+		Session: sessionUuid,
+		Options: options,
+	}, nil
+}
+
+/* Завершение регистрации ключа доступа */
+func (r *WebAuthnPostgres) FinishRegistration(userId int, data userModel.WebAuthnRegisterFinishModel) (*userModel.WebAuthnCredentialModel, error) {
+	if config.AppWebAuthn == nil {
+		return nil, errors.New("Ошибка: вход по ключам доступа не настроен")
+	}
+
+	session, sessionUserId, err := r.takeSession(data.Session, webAuthnPurposeRegister)
+	if err != nil {
+		return nil, err
+	}
+
+	if sessionUserId != userId {
+		return nil, errors.New("Ошибка: церемония регистрации принадлежит другому пользователю")
+	}
+
+	user, err := r.getUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(data.Credential))
+	if err != nil {
+		return nil, webAuthnError(err)
+	}
+
+	credential, err := config.AppWebAuthn.CreateCredential(user, *session, parsed)
+	if err != nil {
+		return nil, webAuthnError(err)
+	}
+
+	authType, err := r.authType.Get("value", authConstants.AUTH_TYPE_WEBAUTHN, true)
+	if err != nil {
+		return nil, err
+	}
+
+	name := data.Name
+	if name == "" {
+		name = fmt.Sprintf("Ключ доступа %d", len(user.credentials)+1)
+	}
+
+	result := userModel.WebAuthnCredentialModel{
+		Uuid:            uuid.NewV4().String(),
+		UsersId:         userId,
+		CredentialId:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Aaguid:          credential.Authenticator.AAGUID,
+		SignCount:       int64(credential.Authenticator.SignCount),
+		Name:            name,
+		CreatedAt:       time.Now(),
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (uuid, users_id, credential_id, public_key, attestation_type, aaguid, sign_count, name, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id
+	`, tableConstants.U_WEBAUTHN_CREDENTIALS)
+
+	err = tx.QueryRow(query, result.Uuid, userId, result.CredentialId, result.PublicKey, result.AttestationType,
+		result.Aaguid, result.SignCount, result.Name, result.CreatedAt).Scan(&result.Id)
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.New("Ошибка: данный ключ доступа уже зарегистрирован")
+	}
+
+	// Связь пользователя с типом авторизации (если её ещё нет)
+	query = fmt.Sprintf(`INSERT INTO %s (users_id, auth_types_id)
+		SELECT $1, $2 WHERE NOT EXISTS (SELECT 1 FROM %s WHERE users_id = $1 AND auth_types_id = $2)`,
+		tableConstants.U_USERS_AUTH_TYPES, tableConstants.U_USERS_AUTH_TYPES)
+	if _, err = tx.Exec(query, userId, authType.Id); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+/* Начало входа по ключу доступа */
+func (r *WebAuthnPostgres) BeginLogin(email string) (*userModel.WebAuthnBeginModel, error) {
+	if config.AppWebAuthn == nil {
+		return nil, errors.New("Ошибка: вход по ключам доступа не настроен")
+	}
+
+	findUser, err := r.user.Get("email", email, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if findUser == nil {
+		return nil, errors.New("Ошибка: для данного пользователя не зарегистрировано ключей доступа")
+	}
+
+	user, err := r.getUser(findUser.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(user.credentials) <= 0 {
+		return nil, errors.New("Ошибка: для данного пользователя не зарегистрировано ключей доступа")
+	}
+
+	options, session, err := config.AppWebAuthn.BeginLogin(user)
+	if err != nil {
+		return nil, webAuthnError(err)
+	}
+
+	sessionUuid, err := r.saveSession(findUser.Id, webAuthnPurposeLogin, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userModel.WebAuthnBeginModel{
+		Session: sessionUuid,
+		Options: options,
+	}, nil
+}
+
+/* Завершение входа по ключу доступа (выдаётся такая же пара токенов, как при входе по паролю) */
+func (r *WebAuthnPostgres) FinishLogin(data userModel.WebAuthnLoginFinishModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	if config.AppWebAuthn == nil {
+		return userModel.UserAuthDataModel{}, errors.New("Ошибка: вход по ключам доступа не настроен")
+	}
+
+	sessionData, userId, err := r.takeSession(data.Session, webAuthnPurposeLogin)
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	user, err := r.getUser(userId)
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(data.Credential))
+	if err != nil {
+		return userModel.UserAuthDataModel{}, webAuthnError(err)
+	}
+
+	credential, err := config.AppWebAuthn.ValidateLogin(user, *sessionData, parsed)
+	if err != nil {
+		return userModel.UserAuthDataModel{}, webAuthnError(err)
+	}
+
+	// Счётчик подписей не увеличился - ключ мог быть скопирован
+	if credential.Authenticator.CloneWarning {
+		logrus.WithFields(logrus.Fields{
+			"users_id": userId,
+		}).Warn("webauthn_sign_count_regression")
+
+		return userModel.UserAuthDataModel{}, errors.New("Ошибка: ключ доступа не прошёл проверку, обратитесь к администратору")
+	}
+
+	authType, err := r.authType.Get("value", authConstants.AUTH_TYPE_WEBAUTHN, true)
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s SET sign_count = $1, last_used_at = $2 WHERE users_id = $3 AND credential_id = $4
+	`, tableConstants.U_WEBAUTHN_CREDENTIALS)
+	if _, err = tx.Exec(query, int64(credential.Authenticator.SignCount), time.Now(), userId, credential.ID); err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	authData, err := createSession(tx, user.user.Id, user.user.Uuid, authType.Uuid, nil, nil, session)
+	if err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	return authData, tx.Commit()
+}
+
+/* Удаление ключа доступа (у пользователя должен остаться хотя бы один способ входа) */
+func (r *WebAuthnPostgres) Delete(userId int, credentialUuid string) (bool, error) {
+	credentials, err := r.getCredentials(userId)
+	if err != nil {
+		return false, err
+	}
+
+	var credential *userModel.WebAuthnCredentialModel
+	for index, item := range credentials {
+		if item.Uuid == credentialUuid {
+			credential = &credentials[index]
+		}
+	}
+
+	if credential == nil {
+		return false, errors.New("Ошибка: ключа доступа с данным идентификатором не существует!")
+	}
+
+	methods, err := countLoginMethods(r.db, userId)
+	if err != nil {
+		return false, err
+	}
+
+	if methods <= 1 {
+		return false, errors.New("Ошибка: нельзя удалить единственный способ входа в систему")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", tableConstants.U_WEBAUTHN_CREDENTIALS)
+	if _, err = tx.Exec(query, credential.Id); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	// Тип авторизации отвязывается вместе с последним ключом доступа
+	if len(credentials) <= 1 {
+		query = fmt.Sprintf(`
+			DELETE FROM %s WHERE users_id = $1 AND auth_types_id = (SELECT id FROM %s WHERE value = $2)
+		`, tableConstants.U_USERS_AUTH_TYPES, tableConstants.U_AUTH_TYPES)
+		if _, err = tx.Exec(query, userId, authConstants.AUTH_TYPE_WEBAUTHN); err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* Получение пользователя вместе с его ключами доступа */
+func (r *WebAuthnPostgres) getUser(userId int) (*webAuthnUser, error) {
+	if config.AppWebAuthn == nil {
+		return nil, errors.New("Ошибка: вход по ключам доступа не настроен")
+	}
+
+	user, err := r.user.Get("id", userId, true)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := r.getCredentials(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webAuthnUser{
+		user:        user,
+		credentials: credentials,
+	}, nil
+}
+
+/* Получение ключей доступа пользователя */
+func (r *WebAuthnPostgres) getCredentials(userId int) ([]userModel.WebAuthnCredentialModel, error) {
+	var credentials []userModel.WebAuthnCredentialModel
+	query := fmt.Sprintf("SELECT * FROM %s WHERE users_id = $1 ORDER BY created_at", tableConstants.U_WEBAUTHN_CREDENTIALS)
+
+	if err := r.db.Select(&credentials, query, userId); err != nil {
+		return nil, err
+	}
+
+	return credentials, nil
+}
+
+/* Сохранение данных церемонии до её завершения */
+func (r *WebAuthnPostgres) saveSession(userId int, purpose string, session *webauthn.SessionData) (string, error) {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+
+	currentDate := time.Now()
+
+	// Попутно удаляются данные незавершённых церемоний
+	query := fmt.Sprintf("DELETE FROM %s WHERE expires_at < $1", tableConstants.U_WEBAUTHN_SESSIONS)
+	if _, err := r.db.Exec(query, currentDate); err != nil {
+		return "", err
+	}
+
+	sessionUuid := uuid.NewV4().String()
+	query = fmt.Sprintf(`
+		INSERT INTO %s (uuid, users_id, purpose, data, expires_at) VALUES ($1, $2, $3, $4, $5)
+	`, tableConstants.U_WEBAUTHN_SESSIONS)
+	if _, err := r.db.Exec(query, sessionUuid, userId, purpose, data, currentDate.Add(authConstants.TOKEN_TLL_WEBAUTHN)); err != nil {
+		return "", err
+	}
+
+	return sessionUuid, nil
+}
+
+/* Получение данных церемонии (данные удаляются - повторно использовать их нельзя) */
+func (r *WebAuthnPostgres) takeSession(sessionUuid, purpose string) (*webauthn.SessionData, int, error) {
+	var row struct {
+		UsersId int    `db:"users_id"`
+		Data    []byte `db:"data"`
+	}
+
+	query := fmt.Sprintf(`
+		DELETE FROM %s WHERE uuid = $1 AND purpose = $2 AND expires_at >= $3 RETURNING users_id, data
+	`, tableConstants.U_WEBAUTHN_SESSIONS)
+	if err := r.db.Get(&row, query, sessionUuid, purpose, time.Now()); err != nil {
+		return nil, 0, errors.New("Ошибка: церемония не найдена или истекла, повторите попытку")
+	}
+
+	var session webauthn.SessionData
+	if err := json.Unmarshal(row.Data, &session); err != nil {
+		return nil, 0, err
+	}
+
+	return &session, row.UsersId, nil
+}
+
+/* Преобразование ошибки библиотеки WebAuthn (подробности содержатся в поле Details) */
+func webAuthnError(err error) error {
+	var value *protocol.Error
+	if errors.As(err, &value) && value.Details != "" {
+		return errors.New("Ошибка WebAuthn: " + value.Details)
+	}
+
+	return err
+}
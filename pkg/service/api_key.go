@@ -0,0 +1,38 @@
+package service
+
+import (
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+)
+
+/* Структура сервиса API-ключей */
+type ApiKeyService struct {
+	repo repository.ApiKey
+}
+
+/* Функция для создания нового сервиса API-ключей */
+func NewApiKeyService(repo repository.ApiKey) *ApiKeyService {
+	return &ApiKeyService{
+		repo: repo,
+	}
+}
+
+/* Создание API-ключа пользователя */
+func (s *ApiKeyService) Create(actor *userModel.UserIdentityModel, userId int, data userModel.ApiKeyCreateModel) (*userModel.ApiKeyCreatedModel, error) {
+	return s.repo.Create(actor, userId, data)
+}
+
+/* Получение всех API-ключей пользователя */
+func (s *ApiKeyService) GetAll(userId int) (*userModel.ApiKeysModel, error) {
+	return s.repo.GetAll(userId)
+}
+
+/* Отзыв API-ключа пользователя */
+func (s *ApiKeyService) Revoke(userId int, apiKeyUuid string) (bool, error) {
+	return s.repo.Revoke(userId, apiKeyUuid)
+}
+
+/* Проверка API-ключа */
+func (s *ApiKeyService) Authenticate(key string) (*userModel.ApiKeyIdentityModel, error) {
+	return s.repo.Authenticate(key)
+}
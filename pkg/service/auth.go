@@ -2,10 +2,15 @@ package service
 
 import (
 	"errors"
+	config "main-server/config"
 	userModel "main-server/pkg/model/user"
 	repository "main-server/pkg/repository"
+	authService "main-server/pkg/service/auth"
+	"main-server/pkg/service/throttle"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
@@ -13,19 +18,43 @@ import (
 type AuthService struct {
 	repo         repository.Authorization
 	tokenService TokenService
+	guard        *throttle.Guard
 }
 
 /* Function for create a new repository */
-func NewAuthService(repo repository.Authorization, tokenService TokenService) *AuthService {
+func NewAuthService(repo repository.Authorization, tokenService TokenService, guard *throttle.Guard) *AuthService {
 	return &AuthService{
 		repo:         repo,
 		tokenService: tokenService,
+		guard:        guard,
+	}
+}
+
+/* Create brute-force guard using the configured lockout policy */
+func NewLockoutGuard(store throttle.Store) *throttle.Guard {
+	policy := config.AppLockoutPolicy
+	return throttle.NewGuard(store, policy.BaseDelay, policy.MaxDelay, policy.Window)
+}
+
+/* Throttle keys for sign-in attempts (per account and per IP address) */
+func loginKeys(email, ip string) []throttle.Key {
+	return []throttle.Key{
+		{Value: "login:account:" + strings.ToLower(strings.TrimSpace(email)), Threshold: config.AppLockoutPolicy.AccountThreshold},
+		{Value: "login:ip:" + ip, Threshold: config.AppLockoutPolicy.IpThreshold},
+	}
+}
+
+/* Throttle keys for password recovery emails (per mailbox and per IP address) */
+func recoveryKeys(email, ip string) []throttle.Key {
+	return []throttle.Key{
+		{Value: "recovery:account:" + strings.ToLower(strings.TrimSpace(email)), Threshold: config.AppLockoutPolicy.RecoveryThreshold},
+		{Value: "recovery:ip:" + ip, Threshold: config.AppLockoutPolicy.IpThreshold},
 	}
 }
 
 /* Create user */
-func (s *AuthService) CreateUser(user userModel.UserSignUpModel) (userModel.UserAuthDataModel, error) {
-	return s.repo.CreateUser(user)
+func (s *AuthService) CreateUser(user userModel.UserSignUpModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	return s.repo.CreateUser(user, session)
 }
 
 /* Upload profile image */
@@ -33,14 +62,48 @@ func (s *AuthService) UploadProfileImage(c *gin.Context, filepath string) (bool,
 	return s.repo.UploadProfileImage(c, filepath)
 }
 
-/* Login user */
-func (s *AuthService) LoginUser(user userModel.UserSignInModel) (userModel.UserAuthDataModel, error) {
-	return s.repo.LoginUser(user)
+/* Login user (failed attempts are counted per account and per IP address) */
+func (s *AuthService) LoginUser(user userModel.UserSignInModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	keys := loginKeys(user.Email, session.Ip)
+	if err := s.guard.Check(keys...); err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	data, err := s.repo.LoginUser(user, session)
+	if err == userModel.ErrInvalidCredentials {
+		if failErr := s.guard.Fail(keys...); failErr != nil {
+			logrus.Error(failErr)
+		}
+
+		return data, err
+	}
+
+	// Only the account counter is reset: a valid account must not unlock the IP address.
+	// With a pending second factor the counter is reset after the code is verified (MfaService)
+	if err == nil && data.Mfa == nil {
+		if resetErr := s.guard.Reset(keys[0]); resetErr != nil {
+			logrus.Error(resetErr)
+		}
+	}
+
+	return data, err
 }
 
-/* Login user with Google OAuth2 */
-func (s *AuthService) LoginUserOAuth2(code string) (userModel.UserAuthDataModel, error) {
-	return s.repo.LoginUserOAuth2(code)
+/* Login user with external OAuth2 provider */
+func (s *AuthService) LoginUserOAuth2(provider, code string, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	return s.repo.LoginUserOAuth2(provider, code, session)
+}
+
+/* Get the authorization page address of external OAuth2 provider */
+func (s *AuthService) GetOAuth2Url(provider, state string) (userModel.OAuth2UrlModel, error) {
+	value, err := authService.GetProvider(provider)
+	if err != nil {
+		return userModel.OAuth2UrlModel{}, err
+	}
+
+	return userModel.OAuth2UrlModel{
+		Url: value.AuthCodeURL(state),
+	}, nil
 }
 
 /**
@@ -69,8 +132,27 @@ func (s *AuthService) Activate(link string) (bool, error) {
 	return s.repo.Activate(link)
 }
 
-/* Recover password */
-func (s *AuthService) RecoveryPassword(email string) (bool, error) {
+/* Resend account activation email */
+func (s *AuthService) ResendActivation(email string) (bool, error) {
+	return s.repo.ResendActivation(email)
+}
+
+/* Check whether the user's account is activated */
+func (s *AuthService) IsActivated(userId int) (bool, error) {
+	return s.repo.IsActivated(userId)
+}
+
+/* Recover password (every request is counted to limit the number of emails) */
+func (s *AuthService) RecoveryPassword(email string, session userModel.SessionInfoModel) (bool, error) {
+	keys := recoveryKeys(email, session.Ip)
+	if err := s.guard.Check(keys...); err != nil {
+		return false, err
+	}
+
+	if err := s.guard.Fail(keys...); err != nil {
+		return false, err
+	}
+
 	return s.repo.RecoveryPassword(email)
 }
 
@@ -84,3 +166,24 @@ func (s *AuthService) ResetPassword(data userModel.ResetPasswordModel) (bool, er
 
 	return s.repo.ResetPassword(data, token)
 }
+
+/* Request passwordless login email */
+func (s *AuthService) RequestLoginCode(email string) (bool, error) {
+	return s.repo.RequestLoginCode(email)
+}
+
+/* Passwordless login with link from email */
+func (s *AuthService) LoginByLink(data userModel.PasswordlessLinkModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	token, err := s.tokenService.ParseResetToken(data.Token, viper.GetString("token.signing_key_login"))
+
+	if err != nil {
+		return userModel.UserAuthDataModel{}, errors.New("Ошибка: ссылка для входа недействительна или уже была использована")
+	}
+
+	return s.repo.LoginByLink(data.Token, token, session)
+}
+
+/* Passwordless login with code from email */
+func (s *AuthService) LoginByCode(data userModel.PasswordlessCodeModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	return s.repo.LoginByCode(data, session)
+}
@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	route "main-server/pkg/constant/route"
+	userModel "main-server/pkg/model/user"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+)
+
+/* Провайдер GitHub */
+type GitHubProvider struct {
+	name   string
+	config oauth2.Config
+}
+
+/* Ответ метода /user */
+type githubUserModel struct {
+	Id    int64  `json:"id"`
+	Login string `json:"login"`
+	Name  string `json:"name"`
+}
+
+/* Элемент ответа метода /user/emails */
+type githubEmailModel struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+/* Создание провайдера GitHub */
+func NewGitHubProvider(name string, config ProviderConfig) (Provider, error) {
+	scopes := config.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"read:user", "user:email"}
+	}
+
+	return &GitHubProvider{
+		name: name,
+		config: oauth2.Config{
+			ClientID:     config.ClientId,
+			ClientSecret: config.ClientSecret,
+			Endpoint:     github.Endpoint,
+			RedirectURL:  config.RedirectUrl,
+			Scopes:       scopes,
+		},
+	}, nil
+}
+
+func (p *GitHubProvider) Name() string {
+	return p.name
+}
+
+func (p *GitHubProvider) AuthCodeURL(state string) string {
+	return p.config.AuthCodeURL(state)
+}
+
+func (p *GitHubProvider) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	return p.config.Exchange(withClient(ctx), code)
+}
+
+/* Получение сведений о пользователе (email - основной подтверждённый адрес) */
+func (p *GitHubProvider) GetUserInfo(ctx context.Context, token *oauth2.Token) (userModel.UserRegisterOAuth2Model, error) {
+	var user githubUserModel
+	status, err := getJSON(ctx, route.GITHUB_USER_INFO, token.AccessToken, &user)
+	if err != nil {
+		return userModel.UserRegisterOAuth2Model{}, err
+	}
+
+	if status != http.StatusOK {
+		return userModel.UserRegisterOAuth2Model{}, fmt.Errorf("Ошибка: GitHub вернул статус %d", status)
+	}
+
+	var emails []githubEmailModel
+	status, err = getJSON(ctx, route.GITHUB_USER_EMAILS, token.AccessToken, &emails)
+	if err != nil {
+		return userModel.UserRegisterOAuth2Model{}, err
+	}
+
+	if status != http.StatusOK {
+		return userModel.UserRegisterOAuth2Model{}, fmt.Errorf("Ошибка: GitHub вернул статус %d", status)
+	}
+
+	data := userModel.UserRegisterOAuth2Model{
+		Subject:   strconv.FormatInt(user.Id, 10),
+		Name:      user.Name,
+		GivenName: user.Login,
+	}
+
+	for _, item := range emails {
+		if item.Primary {
+			data.Email = item.Email
+			data.EmailVerified = item.Verified
+			break
+		}
+	}
+
+	if data.Email == "" {
+		return userModel.UserRegisterOAuth2Model{}, errors.New("Ошибка: GitHub не предоставил email-адрес пользователя")
+	}
+
+	// GitHub хранит имя одной строкой
+	if parts := strings.Fields(user.Name); len(parts) > 0 {
+		data.GivenName = parts[0]
+		data.FamilyName = strings.Join(parts[1:], " ")
+	} else {
+		data.Name = user.Login
+	}
+
+	return data, nil
+}
+
+func (p *GitHubProvider) Verify(ctx context.Context, accessToken string) (bool, error) {
+	status, err := getJSON(ctx, route.GITHUB_USER_INFO, accessToken, nil)
+	if err != nil {
+		return false, err
+	}
+
+	return status == http.StatusOK, nil
+}
+
+/* Токены OAuth-приложений GitHub бессрочные, токены GitHub App обновляются стандартно */
+func (p *GitHubProvider) Refresh(ctx context.Context, token *oauth2.Token) (*oauth2.Token, error) {
+	return refreshToken(ctx, &p.config, token)
+}
+
+/* Отзыв токена доступа (DELETE /applications/{client_id}/token) */
+func (p *GitHubProvider) Revoke(ctx context.Context, token *oauth2.Token) error {
+	body, err := json.Marshal(map[string]string{
+		"access_token": token.AccessToken,
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete,
+		fmt.Sprintf(route.GITHUB_REVOKE_TOKEN, p.config.ClientID), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.SetBasicAuth(p.config.ClientID, p.config.ClientSecret)
+	request.Header.Set("Accept", "application/vnd.github+json")
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("Ошибка: GitHub вернул статус %d при отзыве токена", response.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package auth
+
+import (
+	route "main-server/pkg/constant/route"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+/* Создание провайдера Google (OpenID Connect с известными адресами) */
+func NewGoogleProvider(name string, config ProviderConfig) (Provider, error) {
+	provider := newOIDCProvider(name, config, google.Endpoint, route.GOOGLE_USER_INFO, route.GOOGLE_REVOKE_TOKEN)
+
+	// Токен обновления Google выдаёт только при запросе офлайн-доступа
+	provider.options = []oauth2.AuthCodeOption{oauth2.AccessTypeOffline}
+
+	return provider, nil
+}
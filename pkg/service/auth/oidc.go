@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	route "main-server/pkg/constant/route"
+	userModel "main-server/pkg/model/user"
+
+	"golang.org/x/oauth2"
+)
+
+/* Провайдер OpenID Connect (в том числе основа для Google) */
+type OIDCProvider struct {
+	name        string
+	config      oauth2.Config
+	userInfoUrl string
+	revokeUrl   string
+	options     []oauth2.AuthCodeOption
+}
+
+/* Документ обнаружения провайдера OpenID Connect (используемые поля) */
+type oidcDiscoveryModel struct {
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	RevocationEndpoint    string `json:"revocation_endpoint"`
+}
+
+/* Создание провайдера OpenID Connect по документу обнаружения издателя */
+func NewOIDCProvider(name string, config ProviderConfig) (Provider, error) {
+	if config.Issuer == "" {
+		return nil, errors.New("issuer is empty")
+	}
+
+	var discovery oidcDiscoveryModel
+	status, err := getJSON(context.Background(), strings.TrimSuffix(config.Issuer, "/")+route.OIDC_DISCOVERY, "", &discovery)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK || discovery.TokenEndpoint == "" || discovery.UserinfoEndpoint == "" {
+		return nil, errors.New("invalid discovery document")
+	}
+
+	return newOIDCProvider(name, config, oauth2.Endpoint{
+		AuthURL:  discovery.AuthorizationEndpoint,
+		TokenURL: discovery.TokenEndpoint,
+	}, discovery.UserinfoEndpoint, discovery.RevocationEndpoint), nil
+}
+
+func newOIDCProvider(name string, config ProviderConfig, endpoint oauth2.Endpoint, userInfoUrl, revokeUrl string) *OIDCProvider {
+	scopes := config.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &OIDCProvider{
+		name: name,
+		config: oauth2.Config{
+			ClientID:     config.ClientId,
+			ClientSecret: config.ClientSecret,
+			Endpoint:     endpoint,
+			RedirectURL:  config.RedirectUrl,
+			Scopes:       scopes,
+		},
+		userInfoUrl: userInfoUrl,
+		revokeUrl:   revokeUrl,
+	}
+}
+
+func (p *OIDCProvider) Name() string {
+	return p.name
+}
+
+func (p *OIDCProvider) AuthCodeURL(state string) string {
+	return p.config.AuthCodeURL(state, p.options...)
+}
+
+func (p *OIDCProvider) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	return p.config.Exchange(withClient(ctx), code)
+}
+
+/* Получение сведений о пользователе (стандартные утверждения OpenID Connect) */
+func (p *OIDCProvider) GetUserInfo(ctx context.Context, token *oauth2.Token) (userModel.UserRegisterOAuth2Model, error) {
+	var data userModel.UserRegisterOAuth2Model
+
+	status, err := getJSON(ctx, p.userInfoUrl, token.AccessToken, &data)
+	if err != nil {
+		return userModel.UserRegisterOAuth2Model{}, err
+	}
+
+	if status != http.StatusOK || data.Subject == "" {
+		return userModel.UserRegisterOAuth2Model{}, errors.New("Ошибка: не удалось получить сведения о пользователе от " + p.name)
+	}
+
+	return data, nil
+}
+
+/* Токен доступа действителен, если провайдер по нему выдаёт сведения о пользователе */
+func (p *OIDCProvider) Verify(ctx context.Context, accessToken string) (bool, error) {
+	status, err := getJSON(ctx, p.userInfoUrl, accessToken, nil)
+	if err != nil {
+		return false, err
+	}
+
+	return status == http.StatusOK, nil
+}
+
+func (p *OIDCProvider) Refresh(ctx context.Context, token *oauth2.Token) (*oauth2.Token, error) {
+	return refreshToken(ctx, &p.config, token)
+}
+
+/* Отзыв токена (RFC 7009), если провайдер это поддерживает */
+func (p *OIDCProvider) Revoke(ctx context.Context, token *oauth2.Token) error {
+	if p.revokeUrl == "" {
+		return nil
+	}
+
+	value, hint := token.AccessToken, "access_token"
+	if token.RefreshToken != "" {
+		value, hint = token.RefreshToken, "refresh_token"
+	}
+
+	form := url.Values{}
+	form.Set("token", value)
+	form.Set("token_type_hint", hint)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("client_secret", p.config.ClientSecret)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, p.revokeUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return errors.New("Ошибка: не удалось отозвать токен " + p.name)
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	authConstants "main-server/pkg/constant/auth"
+	userModel "main-server/pkg/model/user"
+
+	"github.com/spf13/viper"
+	"golang.org/x/oauth2"
+)
+
+/*
+* Внешний провайдер OAuth2. Провайдер регистрируется под значением типа авторизации
+* из таблицы u_auth_types, это же значение используется как ключ его конфигурации:
+*
+*	oauth2:
+*	  providers:
+*	    google:
+*	      client_id: ...
+*	      client_secret: ...
+*	      redirect_url: ...
+*	    corporate:
+*	      type: oidc
+*	      issuer: https://sso.example.com
+*	      ...
+ */
+type Provider interface {
+	Name() string
+	AuthCodeURL(state string) string
+	Exchange(ctx context.Context, code string) (*oauth2.Token, error)
+	GetUserInfo(ctx context.Context, token *oauth2.Token) (userModel.UserRegisterOAuth2Model, error)
+	Verify(ctx context.Context, accessToken string) (bool, error)
+	Refresh(ctx context.Context, token *oauth2.Token) (*oauth2.Token, error)
+	Revoke(ctx context.Context, token *oauth2.Token) error
+}
+
+/* Конфигурация провайдера */
+type ProviderConfig struct {
+	Type         string   `mapstructure:"type"`
+	ClientId     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	RedirectUrl  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
+	Issuer       string   `mapstructure:"issuer"` // Только для провайдеров OpenID Connect
+}
+
+/* Конструктор провайдера определённого типа */
+type ProviderFactory func(name string, config ProviderConfig) (Provider, error)
+
+var factories = map[string]ProviderFactory{
+	authConstants.AUTH_TYPE_GOOGLE: NewGoogleProvider,
+	authConstants.AUTH_TYPE_VK:     NewVKProvider,
+	authConstants.AUTH_TYPE_GITHUB: NewGitHubProvider,
+	authConstants.AUTH_TYPE_OIDC:   NewOIDCProvider,
+}
+
+var providers = map[string]Provider{}
+
+/* HTTP-клиент для обращения к провайдерам */
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+/* Инициализация всех провайдеров, описанных в файле конфигурации */
+func InitProviders() error {
+	var items map[string]ProviderConfig
+	if err := viper.UnmarshalKey("oauth2.providers", &items); err != nil {
+		return err
+	}
+
+	providers = map[string]Provider{}
+
+	for name, item := range items {
+		if item.Type == "" {
+			item.Type = name
+		}
+
+		factory, ok := factories[item.Type]
+		if !ok {
+			return fmt.Errorf("oauth2 provider %s: unknown type %s", name, item.Type)
+		}
+
+		provider, err := factory(name, item)
+		if err != nil {
+			return fmt.Errorf("oauth2 provider %s: %s", name, err.Error())
+		}
+
+		Register(provider)
+	}
+
+	return nil
+}
+
+/* Регистрация провайдера */
+func Register(provider Provider) {
+	providers[provider.Name()] = provider
+}
+
+/* Получение провайдера по значению типа авторизации */
+func GetProvider(name string) (Provider, error) {
+	provider, ok := providers[name]
+	if !ok {
+		return nil, errors.New("Ошибка: авторизация через " + name + " не поддерживается")
+	}
+
+	return provider, nil
+}
+
+/* Контекст запросов к провайдеру с HTTP-клиентом, ограниченным по времени */
+func withClient(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+}
+
+/* Обновление токена стандартным способом (refresh_token grant) */
+func refreshToken(ctx context.Context, config *oauth2.Config, token *oauth2.Token) (*oauth2.Token, error) {
+	// Провайдер не выдал токен обновления - токен доступа бессрочный
+	if token.RefreshToken == "" {
+		return token, nil
+	}
+
+	return config.TokenSource(withClient(ctx), &oauth2.Token{
+		RefreshToken: token.RefreshToken,
+	}).Token()
+}
+
+/* GET-запрос к API провайдера с токеном доступа и разбором JSON-ответа */
+func getJSON(ctx context.Context, url, accessToken string, out interface{}) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if accessToken != "" {
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	request.Header.Set("Accept", "application/json")
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK || out == nil {
+		return response.StatusCode, nil
+	}
+
+	return response.StatusCode, json.NewDecoder(response.Body).Decode(out)
+}
@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	route "main-server/pkg/constant/route"
+	userModel "main-server/pkg/model/user"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/vk"
+)
+
+/* Провайдер VK */
+type VKProvider struct {
+	name   string
+	config oauth2.Config
+}
+
+/* Ответ метода users.get */
+type vkUsersModel struct {
+	Response []struct {
+		Id        int64  `json:"id"`
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+	} `json:"response"`
+	Error *struct {
+		Code    int    `json:"error_code"`
+		Message string `json:"error_msg"`
+	} `json:"error"`
+}
+
+/* Создание провайдера VK */
+func NewVKProvider(name string, config ProviderConfig) (Provider, error) {
+	scopes := config.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"email"}
+	}
+
+	return &VKProvider{
+		name: name,
+		config: oauth2.Config{
+			ClientID:     config.ClientId,
+			ClientSecret: config.ClientSecret,
+			Endpoint:     vk.Endpoint,
+			RedirectURL:  config.RedirectUrl,
+			Scopes:       scopes,
+		},
+	}, nil
+}
+
+func (p *VKProvider) Name() string {
+	return p.name
+}
+
+func (p *VKProvider) AuthCodeURL(state string) string {
+	return p.config.AuthCodeURL(state)
+}
+
+func (p *VKProvider) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	return p.config.Exchange(withClient(ctx), code)
+}
+
+/* Получение сведений о пользователе (email VK передаёт только вместе с токеном доступа) */
+func (p *VKProvider) GetUserInfo(ctx context.Context, token *oauth2.Token) (userModel.UserRegisterOAuth2Model, error) {
+	users, err := p.getUsers(ctx, token.AccessToken)
+	if err != nil {
+		return userModel.UserRegisterOAuth2Model{}, err
+	}
+
+	email, _ := token.Extra("email").(string)
+	if email == "" {
+		return userModel.UserRegisterOAuth2Model{}, errors.New("Ошибка: VK не предоставил email-адрес пользователя")
+	}
+
+	user := users.Response[0]
+
+	return userModel.UserRegisterOAuth2Model{
+		Subject: strconv.FormatInt(user.Id, 10),
+		Email:   email,
+		// VK передаёт только подтверждённые email-адреса
+		EmailVerified: true,
+		FamilyName:    user.LastName,
+		GivenName:     user.FirstName,
+		Name:          user.FirstName + " " + user.LastName,
+	}, nil
+}
+
+func (p *VKProvider) Verify(ctx context.Context, accessToken string) (bool, error) {
+	if _, err := p.getUsers(ctx, accessToken); err != nil {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+/* Токены VK с офлайн-доступом бессрочные и не обновляются */
+func (p *VKProvider) Refresh(ctx context.Context, token *oauth2.Token) (*oauth2.Token, error) {
+	return refreshToken(ctx, &p.config, token)
+}
+
+/* VK не предоставляет метода отзыва токена для веб-приложений */
+func (p *VKProvider) Revoke(ctx context.Context, token *oauth2.Token) error {
+	return nil
+}
+
+func (p *VKProvider) getUsers(ctx context.Context, accessToken string) (*vkUsersModel, error) {
+	params := url.Values{}
+	params.Set("v", route.VK_API_VERSION)
+	params.Set("access_token", accessToken)
+
+	var users vkUsersModel
+	status, err := getJSON(ctx, route.VK_USER_INFO+"?"+params.Encode(), "", &users)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("Ошибка: VK вернул статус %d", status)
+	}
+
+	if users.Error != nil {
+		return nil, errors.New("Ошибка VK: " + users.Error.Message)
+	}
+
+	if len(users.Response) == 0 {
+		return nil, errors.New("Ошибка: не удалось получить сведения о пользователе от VK")
+	}
+
+	return &users, nil
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+)
+
+/* Структура сервиса блокировки пользователей */
+type BanService struct {
+	repo repository.Ban
+}
+
+/* Функция для создания нового сервиса блокировки пользователей */
+func NewBanService(repo repository.Ban) *BanService {
+	return &BanService{
+		repo: repo,
+	}
+}
+
+/* Получение действующей блокировки пользователя */
+func (s *BanService) GetActive(userId int) (*userModel.BanModel, error) {
+	return s.repo.GetActive(userId)
+}
+
+/* Получение истории блокировок пользователя */
+func (s *BanService) GetAll(usersUuid string) (*userModel.BansModel, error) {
+	return s.repo.GetAll(usersUuid)
+}
+
+/* Блокировка пользователя */
+func (s *BanService) Create(actor *userModel.UserIdentityModel, data userModel.BanCreateModel) (*userModel.BanModel, error) {
+	return s.repo.Create(actor, data)
+}
+
+/* Снятие блокировки пользователя */
+func (s *BanService) Lift(actor *userModel.UserIdentityModel, data userModel.BanUserModel) (bool, error) {
+	return s.repo.Lift(actor, data)
+}
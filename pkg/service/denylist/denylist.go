@@ -0,0 +1,16 @@
+package denylist
+
+import "time"
+
+/*
+* Список отозванных токенов доступа (по идентификатору jti). Запись нужна только до истечения
+* срока действия токена, после чего токен отклоняется и без неё. Реализации: в памяти процесса
+* (NewMemoryStore) и в PostgreSQL (repository.DenylistPostgres) - для нескольких экземпляров сервера
+ */
+type Store interface {
+	// Занесение токена в список до момента expiresAt
+	Add(tokenId string, expiresAt time.Time) error
+
+	// Проверка наличия действующей записи о токене
+	Contains(tokenId string, now time.Time) (bool, error)
+}
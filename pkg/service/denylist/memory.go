@@ -0,0 +1,76 @@
+package denylist
+
+import (
+	"sync"
+	"time"
+)
+
+/* Список отозванных токенов в памяти процесса (подходит для одного экземпляра сервера) */
+type MemoryStore struct {
+	mu      sync.RWMutex
+	entries map[string]time.Time
+	stop    chan struct{}
+}
+
+/* Создание нового списка в памяти с фоновым удалением истёкших записей с интервалом sweepInterval */
+func NewMemoryStore(sweepInterval time.Duration) *MemoryStore {
+	store := &MemoryStore{
+		entries: make(map[string]time.Time),
+		stop:    make(chan struct{}),
+	}
+
+	go store.run(sweepInterval)
+
+	return store
+}
+
+func (s *MemoryStore) Add(tokenId string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.entries[tokenId]; !ok || expiresAt.After(current) {
+		s.entries[tokenId] = expiresAt
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) Contains(tokenId string, now time.Time) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	expiresAt, ok := s.entries[tokenId]
+
+	return ok && now.Before(expiresAt), nil
+}
+
+/* Остановка фонового удаления истёкших записей */
+func (s *MemoryStore) Stop() {
+	close(s.stop)
+}
+
+func (s *MemoryStore) run(sweepInterval time.Duration) {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			s.sweep(now)
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+/* Удаление записей о токенах, срок действия которых истёк */
+func (s *MemoryStore) sweep(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for tokenId, expiresAt := range s.entries {
+		if !now.Before(expiresAt) {
+			delete(s.entries, tokenId)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package denylist
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		entries map[string]time.Time // Отозванные токены и сроки действия записей
+		tokenId string
+		at      time.Time
+		want    bool
+	}{
+		{name: "unknown token", tokenId: "jti", at: now, want: false},
+		{name: "revoked token", entries: map[string]time.Time{"jti": now.Add(time.Minute)}, tokenId: "jti", at: now, want: true},
+		{name: "other token", entries: map[string]time.Time{"jti": now.Add(time.Minute)}, tokenId: "other", at: now, want: false},
+		{name: "entry expired", entries: map[string]time.Time{"jti": now.Add(time.Minute)}, tokenId: "jti", at: now.Add(time.Minute), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryStore(time.Hour)
+			defer store.Stop()
+
+			for tokenId, expiresAt := range tt.entries {
+				if err := store.Add(tokenId, expiresAt); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := store.Contains(tt.tokenId, tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("Contains = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreKeepsLaterExpiry(t *testing.T) {
+	store := NewMemoryStore(time.Hour)
+	defer store.Stop()
+
+	now := time.Now()
+	store.Add("jti", now.Add(time.Hour))
+	store.Add("jti", now.Add(time.Minute))
+
+	// Повторное занесение с более ранним сроком не сокращает запись
+	if got, _ := store.Contains("jti", now.Add(30*time.Minute)); !got {
+		t.Fatal("entry was shortened by the earlier expiry")
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	store := NewMemoryStore(time.Hour)
+	defer store.Stop()
+
+	now := time.Now()
+	store.Add("expired", now.Add(-time.Second))
+	store.Add("active", now.Add(time.Hour))
+
+	store.sweep(now)
+
+	if _, ok := store.entries["expired"]; ok {
+		t.Fatal("expired entry was not removed")
+	}
+
+	if _, ok := store.entries["active"]; !ok {
+		t.Fatal("active entry was removed")
+	}
+}
+
+func TestMemoryStoreSweeper(t *testing.T) {
+	store := NewMemoryStore(10 * time.Millisecond)
+	store.Add("jti", time.Now().Add(20*time.Millisecond))
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		store.mu.RLock()
+		size := len(store.entries)
+		store.mu.RUnlock()
+
+		if size == 0 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("expired entry was not removed by the background sweeper")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// После остановки фоновое удаление больше не выполняется
+	store.Stop()
+	store.Add("late", time.Now().Add(time.Millisecond))
+	time.Sleep(50 * time.Millisecond)
+
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	if _, ok := store.entries["late"]; !ok {
+		t.Fatal("entry was removed after the sweeper was stopped")
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	rbacModel "main-server/pkg/model/rbac"
+	userModel "main-server/pkg/model/user"
 	repository "main-server/pkg/repository"
 )
 
@@ -15,6 +16,22 @@ func NewDomainService(repo repository.Domain) *DomainService {
 	}
 }
 
+func (s *DomainService) Create(user *userModel.UserIdentityModel, data rbacModel.DomainCreateModel) (*rbacModel.DomainModel, error) {
+	return s.repo.Create(user, data)
+}
+
 func (s *DomainService) Get(column string, value interface{}, check bool) (*rbacModel.DomainModel, error) {
 	return s.repo.Get(column, value, check)
 }
+
+func (s *DomainService) GetAll() (*rbacModel.DomainsModel, error) {
+	return s.repo.GetAll()
+}
+
+func (s *DomainService) Update(data rbacModel.DomainUpdateModel) (*rbacModel.DomainModel, error) {
+	return s.repo.Update(data)
+}
+
+func (s *DomainService) Delete(domainUuid string) (bool, error) {
+	return s.repo.Delete(domainUuid)
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+)
+
+/* Структура сервиса смены email-адреса пользователя */
+type EmailChangeService struct {
+	repo repository.EmailChange
+}
+
+/* Функция для создания нового сервиса смены email-адреса */
+func NewEmailChangeService(repo repository.EmailChange) *EmailChangeService {
+	return &EmailChangeService{
+		repo: repo,
+	}
+}
+
+/* Запрос на смену email-адреса пользователя */
+func (s *EmailChangeService) Request(userId int, sessionUuid string, data userModel.EmailChangeRequestModel) (*userModel.EmailChangeModel, error) {
+	return s.repo.Request(userId, sessionUuid, data)
+}
+
+/* Подтверждение смены email-адреса */
+func (s *EmailChangeService) Confirm(link string) (bool, error) {
+	return s.repo.Confirm(link)
+}
+
+/* Отмена смены email-адреса */
+func (s *EmailChangeService) Cancel(link string) (bool, error) {
+	return s.repo.Cancel(link)
+}
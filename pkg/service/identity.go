@@ -0,0 +1,33 @@
+package service
+
+import (
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+)
+
+/* Структура сервиса внешних учётных записей пользователя */
+type IdentityService struct {
+	repo repository.Identity
+}
+
+/* Функция для создания нового сервиса внешних учётных записей */
+func NewIdentityService(repo repository.Identity) *IdentityService {
+	return &IdentityService{
+		repo: repo,
+	}
+}
+
+/* Получение всех привязанных внешних учётных записей пользователя */
+func (s *IdentityService) GetAll(userId int) (*userModel.IdentitiesModel, error) {
+	return s.repo.GetAll(userId)
+}
+
+/* Привязка внешней учётной записи к пользователю */
+func (s *IdentityService) Link(userId int, provider, code string) (*userModel.IdentityModel, error) {
+	return s.repo.Link(userId, provider, code)
+}
+
+/* Отвязка внешней учётной записи от пользователя */
+func (s *IdentityService) Unlink(userId int, identityUuid string) (bool, error) {
+	return s.repo.Unlink(userId, identityUuid)
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	config "main-server/config"
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+	"main-server/pkg/service/throttle"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+/* Структура сервиса двухфакторной аутентификации */
+type MfaService struct {
+	repo         repository.Mfa
+	user         repository.User
+	tokenService TokenService
+	guard        *throttle.Guard
+}
+
+/* Функция для создания нового сервиса двухфакторной аутентификации */
+func NewMfaService(repo repository.Mfa, user repository.User, tokenService TokenService, guard *throttle.Guard) *MfaService {
+	return &MfaService{
+		repo:         repo,
+		user:         user,
+		tokenService: tokenService,
+		guard:        guard,
+	}
+}
+
+/* Ключи ограничения попыток ввода второго фактора (по пользователю и по IP-адресу) */
+func mfaKeys(usersUuid, ip string) []throttle.Key {
+	return []throttle.Key{
+		{Value: "mfa:account:" + usersUuid, Threshold: config.AppLockoutPolicy.AccountThreshold},
+		{Value: "mfa:ip:" + ip, Threshold: config.AppLockoutPolicy.IpThreshold},
+	}
+}
+
+/* Получение состояния двухфакторной аутентификации пользователя */
+func (s *MfaService) GetStatus(userId int) (*userModel.MfaStatusModel, error) {
+	return s.repo.GetStatus(userId)
+}
+
+/* Генерация секрета для приложения-аутентификатора */
+func (s *MfaService) SetupTotp(userId int) (*userModel.MfaTotpSetupModel, error) {
+	return s.repo.SetupTotp(userId)
+}
+
+/* Включение второго фактора */
+func (s *MfaService) EnableTotp(userId int, code string) (*userModel.MfaRecoveryCodesModel, error) {
+	return s.repo.EnableTotp(userId, code)
+}
+
+/* Отключение второго фактора */
+func (s *MfaService) DisableTotp(userId int, code string) (bool, error) {
+	return s.repo.DisableTotp(userId, code)
+}
+
+/* Выпуск нового набора кодов восстановления */
+func (s *MfaService) RegenerateRecoveryCodes(userId int, code string) (*userModel.MfaRecoveryCodesModel, error) {
+	return s.repo.RegenerateRecoveryCodes(userId, code)
+}
+
+/* Генерация секрета во время входа (если второй фактор обязателен, но ещё не подключён) */
+func (s *MfaService) SetupTotpLogin(data userModel.MfaTokenModel) (*userModel.MfaTotpSetupModel, error) {
+	token, err := s.tokenService.ParseMfaToken(data.MfaToken, viper.GetString("token.signing_key_mfa"))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.SetupTotp(token.UsersId)
+}
+
+/* Завершение входа после проверки второго фактора */
+func (s *MfaService) VerifyLogin(data userModel.MfaVerifyModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	token, err := s.tokenService.ParseMfaToken(data.MfaToken, viper.GetString("token.signing_key_mfa"))
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	keys := mfaKeys(token.UsersUuid, session.Ip)
+	if err = s.guard.Check(keys...); err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	authData, err := s.repo.Login(token, data.Code, session)
+	s.complete(token, keys, err)
+
+	return authData, err
+}
+
+/* Подключение второго фактора и завершение входа */
+func (s *MfaService) EnableTotpLogin(data userModel.MfaVerifyModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, *userModel.MfaRecoveryCodesModel, error) {
+	token, err := s.tokenService.ParseMfaToken(data.MfaToken, viper.GetString("token.signing_key_mfa"))
+	if err != nil {
+		return userModel.UserAuthDataModel{}, nil, err
+	}
+
+	keys := mfaKeys(token.UsersUuid, session.Ip)
+	if err = s.guard.Check(keys...); err != nil {
+		return userModel.UserAuthDataModel{}, nil, err
+	}
+
+	authData, codes, err := s.repo.EnrollLogin(token, data.Code, session)
+	s.complete(token, keys, err)
+
+	return authData, codes, err
+}
+
+/*
+* Учёт результата проверки второго фактора: неверный код увеличивает счётчики,
+* после успешного входа сбрасываются счётчики пользователя (в том числе счётчик входа по паролю)
+ */
+func (s *MfaService) complete(token userModel.MfaTokenOutputParse, keys []throttle.Key, err error) {
+	if err == userModel.ErrInvalidMfaCode {
+		if failErr := s.guard.Fail(keys...); failErr != nil {
+			logrus.Error(failErr)
+		}
+		return
+	}
+
+	if err != nil {
+		return
+	}
+
+	resetKeys := []throttle.Key{keys[0]}
+	if user, userErr := s.user.Get("id", token.UsersId, true); userErr == nil {
+		resetKeys = append(resetKeys, loginKeys(user.Email, "")[0])
+	} else {
+		logrus.Error(userErr)
+	}
+
+	if resetErr := s.guard.Reset(resetKeys...); resetErr != nil {
+		logrus.Error(resetErr)
+	}
+}
@@ -0,0 +1,47 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+
+	mfaConstants "main-server/pkg/constant/mfa"
+)
+
+/* Алфавит кодов восстановления (без похожих друг на друга символов) */
+const recoveryAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+/* Генерация набора одноразовых кодов восстановления вида xxxxx-xxxxx */
+func GenerateRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, mfaConstants.RECOVERY_CODES_COUNT)
+	max := big.NewInt(int64(len(recoveryAlphabet)))
+	half := mfaConstants.RECOVERY_CODE_LENGTH / 2
+
+	for len(codes) < mfaConstants.RECOVERY_CODES_COUNT {
+		var builder strings.Builder
+		for i := 0; i < mfaConstants.RECOVERY_CODE_LENGTH; i++ {
+			if i == half {
+				builder.WriteByte('-')
+			}
+
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return nil, err
+			}
+
+			builder.WriteByte(recoveryAlphabet[n.Int64()])
+		}
+
+		codes = append(codes, builder.String())
+	}
+
+	return codes, nil
+}
+
+/* Приведение кода восстановления к виду, в котором хранится его хэш */
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+
+	return strings.ReplaceAll(code, " ", "")
+}
@@ -0,0 +1,83 @@
+package mfa
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	mfaConstants "main-server/pkg/constant/mfa"
+)
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+/* Генерация нового секрета TOTP (base32 без выравнивания) */
+func GenerateSecret() (string, error) {
+	buf := make([]byte, mfaConstants.TOTP_SECRET_SIZE)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return secretEncoding.EncodeToString(buf), nil
+}
+
+/* Формирование URI для QR-кода приложения-аутентификатора (формат otpauth://) */
+func ProvisioningURI(issuer, account, secret string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(mfaConstants.TOTP_DIGITS))
+	params.Set("period", fmt.Sprint(mfaConstants.TOTP_PERIOD))
+
+	label := url.PathEscape(issuer + ":" + account)
+
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+/*
+* Проверка одноразового кода (RFC 6238) с допуском в TOTP_SKEW интервалов в обе стороны.
+* Возвращает номер интервала, которому соответствует код - он используется для защиты от повторного ввода
+ */
+func Validate(secret, code string, now time.Time) (int64, bool) {
+	key, err := secretEncoding.DecodeString(strings.ToUpper(strings.TrimSpace(secret)))
+	if err != nil || len(code) != mfaConstants.TOTP_DIGITS {
+		return 0, false
+	}
+
+	step := now.Unix() / mfaConstants.TOTP_PERIOD
+	for i := -mfaConstants.TOTP_SKEW; i <= mfaConstants.TOTP_SKEW; i++ {
+		current := step + int64(i)
+		if subtle.ConstantTimeCompare([]byte(generateCode(key, current)), []byte(code)) == 1 {
+			return current, true
+		}
+	}
+
+	return 0, false
+}
+
+/* Вычисление кода для определённого интервала (HOTP, RFC 4226) */
+func generateCode(key []byte, counter int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < mfaConstants.TOTP_DIGITS; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", mfaConstants.TOTP_DIGITS, value%mod)
+}
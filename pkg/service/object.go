@@ -0,0 +1,52 @@
+package service
+
+import (
+	"errors"
+	actionConstant "main-server/pkg/constant/action"
+	rbacModel "main-server/pkg/model/rbac"
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+)
+
+/* Структура сервиса объектов */
+type ObjectService struct {
+	repo repository.Object
+}
+
+/* Функция для создания нового сервиса объектов */
+func NewObjectService(repo repository.Object) *ObjectService {
+	return &ObjectService{
+		repo: repo,
+	}
+}
+
+/* Регистрация нового объекта (для дочернего объекта необходимо право create на родителя) */
+func (s *ObjectService) Create(user *userModel.UserIdentityModel, data rbacModel.ResourceModel) (*rbacModel.ObjectDbModel, error) {
+	if data.ParentUuid != nil {
+		access, err := s.repo.Enforce(user.UserId, user.DomainId, *data.ParentUuid, actionConstant.CREATE)
+		if err != nil {
+			return nil, err
+		}
+
+		if !access {
+			return nil, errors.New("Нет доступа!")
+		}
+	}
+
+	return s.repo.Create(user, data)
+}
+
+/* Получение информации об объекте */
+func (s *ObjectService) Get(column string, value interface{}, check bool) (*rbacModel.ObjectDbModel, error) {
+	return s.repo.Get(column, value, check)
+}
+
+/* Удаление объекта */
+func (s *ObjectService) Delete(objectUuid string) (bool, error) {
+	return s.repo.Delete(objectUuid)
+}
+
+/* Проверка возможности выполнения действия над объектом */
+func (s *ObjectService) Enforce(userId, domainId int, objectUuid, action string) (bool, error) {
+	return s.repo.Enforce(userId, domainId, objectUuid, action)
+}
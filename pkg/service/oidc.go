@@ -0,0 +1,352 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+
+	config "main-server/config"
+	oidcConstants "main-server/pkg/constant/oidc"
+	"main-server/pkg/constant/route"
+	oidcModel "main-server/pkg/model/oidc"
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+/* Структура сервиса OpenID Connect */
+type OidcService struct {
+	repo  repository.Oidc
+	user  repository.User
+	token TokenService
+}
+
+/* Функция для создания нового сервиса OpenID Connect */
+func NewOidcService(repo repository.Oidc, user repository.User, token TokenService) *OidcService {
+	return &OidcService{
+		repo:  repo,
+		user:  user,
+		token: token,
+	}
+}
+
+/* Регистрация нового клиента */
+func (s *OidcService) CreateClient(data oidcModel.ClientCreateModel) (*oidcModel.ClientCreatedModel, error) {
+	// Клиенту сервисного аккаунта адреса перенаправления не нужны, но необходим секрет
+	if data.ServiceAccountUuid != nil {
+		if !data.Confidential {
+			return nil, errors.New("Ошибка: клиент сервисного аккаунта должен быть конфиденциальным")
+		}
+	} else if len(data.RedirectUris) <= 0 {
+		return nil, errors.New("Ошибка: необходимо указать хотя бы один адрес перенаправления")
+	}
+
+	for _, item := range data.RedirectUris {
+		value, err := url.Parse(item)
+		if err != nil || !value.IsAbs() || value.Fragment != "" {
+			return nil, errors.New("Ошибка: некорректный адрес перенаправления " + item)
+		}
+	}
+
+	return s.repo.CreateClient(data)
+}
+
+/* Получение всех клиентов */
+func (s *OidcService) GetClients() (*oidcModel.ClientsModel, error) {
+	return s.repo.GetClients()
+}
+
+/* Удаление клиента */
+func (s *OidcService) DeleteClient(clientId string) (bool, error) {
+	return s.repo.DeleteClient(clientId)
+}
+
+/*
+* Проверка параметров запроса авторизации. Если клиент и адрес перенаправления корректны,
+* то клиент возвращается вместе с ошибкой, чтобы её можно было передать на адрес перенаправления
+ */
+func (s *OidcService) ValidateAuthorize(data oidcModel.AuthorizeModel) (*oidcModel.ClientModel, error) {
+	client, err := s.repo.GetClient("client_id", data.ClientId, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if client == nil {
+		return nil, oidcModel.NewError(oidcConstants.ERROR_INVALID_CLIENT, "Клиент не зарегистрирован")
+	}
+
+	registered := false
+	for _, item := range client.RedirectUris {
+		if item == data.RedirectUri {
+			registered = true
+			break
+		}
+	}
+
+	if !registered {
+		return nil, oidcModel.NewError(oidcConstants.ERROR_INVALID_REQUEST, "Адрес перенаправления не зарегистрирован для клиента")
+	}
+
+	if data.ResponseType != oidcConstants.RESPONSE_TYPE_CODE {
+		return client, oidcModel.NewError(oidcConstants.ERROR_UNSUPPORTED_RESPONSE_TYPE, "Поддерживается только response_type=code")
+	}
+
+	if !hasScope(data.Scope, oidcConstants.SCOPE_OPENID) {
+		return client, oidcModel.NewError(oidcConstants.ERROR_INVALID_SCOPE, "Область доступа должна содержать openid")
+	}
+
+	// PKCE обязателен для всех клиентов
+	if data.CodeChallenge == "" || data.CodeChallengeMethod != oidcConstants.CODE_CHALLENGE_METHOD_S256 {
+		return client, oidcModel.NewError(oidcConstants.ERROR_INVALID_REQUEST, "Необходим code_challenge с методом S256")
+	}
+
+	return client, nil
+}
+
+/* Выдача кода авторизации авторизованному пользователю */
+func (s *OidcService) Authorize(user *userModel.UserIdentityModel, data oidcModel.AuthorizeModel) (*oidcModel.AuthorizeRedirectModel, error) {
+	client, err := s.ValidateAuthorize(data)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := s.repo.CreateCode(user, client, data)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("code", code)
+	if data.State != nil {
+		params.Set("state", *data.State)
+	}
+
+	return &oidcModel.AuthorizeRedirectModel{
+		RedirectUri: AppendQuery(data.RedirectUri, params),
+	}, nil
+}
+
+/* Выдача токенов: обмен кода авторизации или client_credentials */
+func (s *OidcService) Token(data oidcModel.TokenRequestModel) (*oidcModel.TokenResponseModel, error) {
+	switch data.GrantType {
+	case oidcConstants.GRANT_TYPE_AUTHORIZATION_CODE:
+		if data.Code == "" || data.ClientId == "" {
+			return nil, oidcModel.NewError(oidcConstants.ERROR_INVALID_REQUEST, "Необходимо указать code и client_id")
+		}
+
+		return s.repo.ExchangeCode(data)
+	case oidcConstants.GRANT_TYPE_CLIENT_CREDENTIALS:
+		if data.ClientId == "" || data.ClientSecret == "" {
+			return nil, oidcModel.NewError(oidcConstants.ERROR_INVALID_CLIENT, "Необходимо указать client_id и client_secret")
+		}
+
+		return s.repo.ClientCredentials(data)
+	}
+
+	return nil, oidcModel.NewError(oidcConstants.ERROR_UNSUPPORTED_GRANT_TYPE, "Поддерживаются только grant_type=authorization_code и client_credentials")
+}
+
+/* Интроспекция токена доступа или обновления (RFC 7662) */
+func (s *OidcService) Introspect(data oidcModel.TokenActionRequestModel) (*oidcModel.IntrospectionModel, error) {
+	if _, err := s.authenticateResourceClient(data.ClientId, data.ClientSecret); err != nil {
+		return nil, err
+	}
+
+	// Если токен не найден как токен указанного типа, он проверяется как токен другого типа
+	for _, kind := range tokenKinds(data.TokenTypeHint) {
+		token := s.parseToken(data.Token, kind)
+		if token == nil {
+			continue
+		}
+
+		result, err := s.repo.Introspect(*token, kind, data.Token)
+		if err != nil {
+			return nil, err
+		}
+
+		if result.Active {
+			return result, nil
+		}
+	}
+
+	return &oidcModel.IntrospectionModel{Active: false}, nil
+}
+
+/*
+* Отзыв токена (RFC 7009). Отзыв токена сессии завершает её; недействительный или уже
+* отозванный токен не считается ошибкой. Токен client_credentials может отозвать только его клиент
+ */
+func (s *OidcService) Revoke(data oidcModel.TokenActionRequestModel) error {
+	client, err := s.authenticateResourceClient(data.ClientId, data.ClientSecret)
+	if err != nil {
+		return err
+	}
+
+	// Если токен не найден как токен указанного типа, он отзывается как токен другого типа (RFC 7009, раздел 2.1)
+	for _, kind := range tokenKinds(data.TokenTypeHint) {
+		token := s.parseToken(data.Token, kind)
+		if token == nil {
+			continue
+		}
+
+		if token.SessionUuid == "" && token.ClientId != client.ClientId {
+			return oidcModel.NewError(oidcConstants.ERROR_UNAUTHORIZED_CLIENT, "Токен выдан другому клиенту")
+		}
+
+		revoked, err := s.repo.Revoke(*token, kind, data.Token)
+		if err != nil {
+			return err
+		}
+
+		if revoked {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+/* Аутентификация клиента (шлюза или сервиса) для интроспекции и отзыва токенов: допускаются только конфиденциальные клиенты */
+func (s *OidcService) authenticateResourceClient(clientId, secret string) (*oidcModel.ClientModel, error) {
+	if clientId == "" || secret == "" {
+		return nil, oidcModel.NewError(oidcConstants.ERROR_INVALID_CLIENT, "Необходимо указать client_id и client_secret")
+	}
+
+	client, err := s.repo.AuthenticateClient(clientId, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.SecretHash == nil {
+		return nil, oidcModel.NewError(oidcConstants.ERROR_INVALID_CLIENT, "Клиент должен быть конфиденциальным")
+	}
+
+	return client, nil
+}
+
+/* Порядок проверки типов токена с учётом подсказки: сначала указанный тип, затем другой */
+func tokenKinds(hint string) []string {
+	if hint == oidcConstants.TOKEN_TYPE_HINT_REFRESH {
+		return []string{oidcConstants.TOKEN_TYPE_HINT_REFRESH, oidcConstants.TOKEN_TYPE_HINT_ACCESS}
+	}
+
+	return []string{oidcConstants.TOKEN_TYPE_HINT_ACCESS, oidcConstants.TOKEN_TYPE_HINT_REFRESH}
+}
+
+/* Разбор токена определённого типа (nil, если токен недействителен или имеет другой тип) */
+func (s *OidcService) parseToken(token, kind string) *userModel.TokenOutputParse {
+	signingKey := viper.GetString("token.signing_key_access")
+	if kind == oidcConstants.TOKEN_TYPE_HINT_REFRESH {
+		// Токены обновления подписываются только общим секретом, токен с kid - всегда токен доступа
+		if jwtToken, _, err := new(jwt.Parser).ParseUnverified(token, &jwt.StandardClaims{}); err != nil || jwtToken.Header["kid"] != nil {
+			return nil
+		}
+
+		signingKey = viper.GetString("token.signing_key_refresh")
+	}
+
+	data, err := s.token.ParseToken(token, signingKey)
+	if err != nil {
+		return nil
+	}
+
+	// Токен обновления всегда привязан к сессии
+	if kind == oidcConstants.TOKEN_TYPE_HINT_REFRESH && data.SessionUuid == "" {
+		return nil
+	}
+
+	return &data
+}
+
+/* Получение сведений о пользователе на основе его профиля */
+func (s *OidcService) GetUserInfo(c *gin.Context, user *userModel.UserIdentityModel) (*oidcModel.UserInfoModel, error) {
+	profile, err := s.user.GetProfile(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var data userModel.UserProfileDataModel
+	if err := json.Unmarshal([]byte(profile.Data), &data); err != nil {
+		return nil, err
+	}
+
+	roles, err := s.user.GetAllRoles(*user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oidcModel.UserInfoModel{
+		Sub:        user.UserUuid,
+		Email:      profile.Email,
+		Name:       data.Name,
+		FamilyName: data.Surname,
+		MiddleName: data.Patronymic,
+		Nickname:   data.Nickname,
+		Picture:    data.Avatar,
+		Roles:      roles.Roles,
+	}, nil
+}
+
+/* Получение документа обнаружения */
+func (s *OidcService) GetDiscovery() oidcModel.DiscoveryModel {
+	issuer := strings.TrimSuffix(viper.GetString("oidc.issuer"), "/")
+
+	algs := make([]string, 0)
+	for _, key := range config.AppJWTKeys.PublicKeys() {
+		if key.Private != nil {
+			algs = append(algs, key.Method.Alg())
+		}
+	}
+
+	return oidcModel.DiscoveryModel{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             issuer + route.OIDC_MAIN_ROUTE + route.AUTHORIZE,
+		TokenEndpoint:                     issuer + route.OIDC_MAIN_ROUTE + route.TOKEN,
+		UserinfoEndpoint:                  issuer + route.OIDC_MAIN_ROUTE + route.USERINFO,
+		JwksUri:                           issuer + route.WELL_KNOWN + route.JWKS,
+		IntrospectionEndpoint:             issuer + route.OAUTH_MAIN_ROUTE + route.INTROSPECT,
+		RevocationEndpoint:                issuer + route.OAUTH_MAIN_ROUTE + route.REVOKE,
+		ResponseTypesSupported:            []string{oidcConstants.RESPONSE_TYPE_CODE},
+		GrantTypesSupported:               []string{oidcConstants.GRANT_TYPE_AUTHORIZATION_CODE, oidcConstants.GRANT_TYPE_CLIENT_CREDENTIALS},
+		SubjectTypesSupported:             []string{"public"},
+		IdTokenSigningAlgValuesSupported:  algs,
+		ScopesSupported:                   []string{oidcConstants.SCOPE_OPENID, oidcConstants.SCOPE_PROFILE, oidcConstants.SCOPE_EMAIL, oidcConstants.SCOPE_ROLES},
+		TokenEndpointAuthMethodsSupported: []string{"none", "client_secret_post", "client_secret_basic"},
+		CodeChallengeMethodsSupported:     []string{oidcConstants.CODE_CHALLENGE_METHOD_S256},
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "email", "name", "family_name", "middle_name", "nickname", "picture", "roles"},
+	}
+}
+
+/* Добавление параметров к адресу (с учётом уже существующих параметров) */
+func AppendQuery(address string, params url.Values) string {
+	value, err := url.Parse(address)
+	if err != nil {
+		return address
+	}
+
+	query := value.Query()
+	for key, items := range params {
+		for _, item := range items {
+			query.Add(key, item)
+		}
+	}
+
+	value.RawQuery = query.Encode()
+
+	return value.String()
+}
+
+/* Проверка наличия области доступа в списке областей (через пробел) */
+func hasScope(scope, value string) bool {
+	for _, item := range strings.Fields(scope) {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
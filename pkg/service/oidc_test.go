@@ -0,0 +1,329 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+	"testing"
+	"time"
+
+	config "main-server/config"
+	oidcConstants "main-server/pkg/constant/oidc"
+	oidcModel "main-server/pkg/model/oidc"
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/spf13/viper"
+)
+
+const testUserUuid = "00000000-0000-0000-0000-000000000010"
+
+type fakeUserRepository struct {
+	repository.User
+}
+
+func (r *fakeUserRepository) Get(column string, value interface{}, check bool) (*userModel.UserModel, error) {
+	return &userModel.UserModel{Id: 10, Uuid: testUserUuid}, nil
+}
+
+type fakeAuthTypeRepository struct{}
+
+func (r *fakeAuthTypeRepository) Get(column string, value interface{}, check bool) (*userModel.AuthTypeModel, error) {
+	return &userModel.AuthTypeModel{Id: 1, Uuid: "auth-type-uuid", Value: "local"}, nil
+}
+
+/*
+* Репозиторий клиентов: секрет клиента хранится в открытом виде, вызовы интроспекции и отзыва запоминаются.
+* Тестовые токены - токены доступа, поэтому поиск токена в столбце refresh_token ничего не находит
+ */
+type fakeOidcRepository struct {
+	repository.Oidc
+	clients    map[string]*oidcModel.ClientModel
+	introspect int
+	columns    []string
+	revoked    []userModel.TokenOutputParse
+}
+
+func (r *fakeOidcRepository) AuthenticateClient(clientId, secret string) (*oidcModel.ClientModel, error) {
+	client, ok := r.clients[clientId]
+	if !ok {
+		return nil, oidcModel.NewError(oidcConstants.ERROR_INVALID_CLIENT, "Клиент не зарегистрирован")
+	}
+
+	if client.SecretHash != nil && *client.SecretHash != secret {
+		return nil, oidcModel.NewError(oidcConstants.ERROR_INVALID_CLIENT, "Некорректный секрет клиента")
+	}
+
+	return client, nil
+}
+
+func (r *fakeOidcRepository) Introspect(data userModel.TokenOutputParse, column, token string) (*oidcModel.IntrospectionModel, error) {
+	r.introspect++
+	r.columns = append(r.columns, column)
+
+	if column != oidcConstants.TOKEN_TYPE_HINT_ACCESS {
+		return &oidcModel.IntrospectionModel{Active: false}, nil
+	}
+
+	return &oidcModel.IntrospectionModel{Active: true, Sub: data.UsersUuid, ClientId: data.ClientId}, nil
+}
+
+func (r *fakeOidcRepository) Revoke(data userModel.TokenOutputParse, column, token string) (bool, error) {
+	r.columns = append(r.columns, column)
+
+	if column != oidcConstants.TOKEN_TYPE_HINT_ACCESS {
+		return false, nil
+	}
+
+	r.revoked = append(r.revoked, data)
+	return true, nil
+}
+
+func newTestOidcService(t *testing.T) (*OidcService, *fakeOidcRepository) {
+	t.Helper()
+
+	viper.Set("token.signing_key_access", "access-secret")
+	viper.Set("token.signing_key_refresh", "refresh-secret")
+	config.AppJWTKeys = config.JWTKeySet{}
+
+	secret := "gateway-secret"
+	repo := &fakeOidcRepository{clients: map[string]*oidcModel.ClientModel{
+		"gateway": {Id: 1, ClientId: "gateway", SecretHash: &secret},
+		"other":   {Id: 2, ClientId: "other", SecretHash: &secret},
+		"public":  {Id: 3, ClientId: "public"},
+	}}
+
+	token := NewTokenService(nil, &fakeUserRepository{}, &fakeAuthTypeRepository{}, nil)
+
+	return NewOidcService(repo, &fakeUserRepository{}, *token), repo
+}
+
+/* Токен доступа: sessionUuid - для токена сессии, clientId - для токена клиента */
+func newTestAccessToken(t *testing.T, sessionUuid, clientId string) string {
+	t.Helper()
+
+	claims := &tokenClaims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        "jti-" + sessionUuid + clientId,
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+			IssuedAt:  time.Now().Unix(),
+		},
+		UsersId:     testUserUuid,
+		AuthTypesId: "auth-type-uuid",
+		SessionId:   sessionUuid,
+		ClientId:    clientId,
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("access-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+/* Проверка кода ошибки OAuth 2.0 (пустой код - ошибки быть не должно) */
+func checkOAuthError(t *testing.T, err error, code string) {
+	t.Helper()
+
+	if code == "" {
+		if err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	oauthErr, ok := err.(*oidcModel.ErrorModel)
+	if !ok || oauthErr.Code != code {
+		t.Fatalf("err = %v, want %s", err, code)
+	}
+}
+
+func TestOidcIntrospectClientAuth(t *testing.T) {
+	tests := []struct {
+		name     string
+		clientId string
+		secret   string
+		token    string
+		wantCode string
+		active   bool
+	}{
+		{name: "confidential client", clientId: "gateway", secret: "gateway-secret", token: "session", active: true},
+		{name: "invalid token", clientId: "gateway", secret: "gateway-secret", token: "invalid"},
+		{name: "no credentials", token: "session", wantCode: oidcConstants.ERROR_INVALID_CLIENT},
+		{name: "no secret", clientId: "gateway", token: "session", wantCode: oidcConstants.ERROR_INVALID_CLIENT},
+		{name: "wrong secret", clientId: "gateway", secret: "other", token: "session", wantCode: oidcConstants.ERROR_INVALID_CLIENT},
+		{name: "unknown client", clientId: "unknown", secret: "secret", token: "session", wantCode: oidcConstants.ERROR_INVALID_CLIENT},
+		{name: "public client", clientId: "public", secret: "secret", token: "session", wantCode: oidcConstants.ERROR_INVALID_CLIENT},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := newTestOidcService(t)
+
+			token := tt.token
+			if token == "session" {
+				token = newTestAccessToken(t, "session-uuid", "")
+			}
+
+			data, err := s.Introspect(oidcModel.TokenActionRequestModel{
+				Token:        token,
+				ClientId:     tt.clientId,
+				ClientSecret: tt.secret,
+			})
+			checkOAuthError(t, err, tt.wantCode)
+
+			if tt.wantCode != "" {
+				if repo.introspect != 0 {
+					t.Fatal("token introspected for unauthenticated client")
+				}
+				return
+			}
+
+			if data.Active != tt.active {
+				t.Fatalf("active = %v, want %v", data.Active, tt.active)
+			}
+		})
+	}
+}
+
+func TestOidcRevokeClientAuth(t *testing.T) {
+	tests := []struct {
+		name     string
+		clientId string
+		secret   string
+		session  string // Сессия токена (пустая - токен клиента)
+		owner    string // Клиент, которому выдан токен
+		invalid  bool
+		wantCode string
+		revoked  bool
+	}{
+		{name: "session token", clientId: "gateway", secret: "gateway-secret", session: "session-uuid", revoked: true},
+		{name: "own client token", clientId: "gateway", secret: "gateway-secret", owner: "gateway", revoked: true},
+		{name: "token of other client", clientId: "gateway", secret: "gateway-secret", owner: "other", wantCode: oidcConstants.ERROR_UNAUTHORIZED_CLIENT},
+		{name: "invalid token", clientId: "gateway", secret: "gateway-secret", invalid: true},
+		{name: "no credentials", session: "session-uuid", wantCode: oidcConstants.ERROR_INVALID_CLIENT},
+		{name: "wrong secret", clientId: "gateway", secret: "other", session: "session-uuid", wantCode: oidcConstants.ERROR_INVALID_CLIENT},
+		{name: "public client", clientId: "public", secret: "secret", session: "session-uuid", wantCode: oidcConstants.ERROR_INVALID_CLIENT},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := newTestOidcService(t)
+
+			token := "invalid"
+			if !tt.invalid {
+				token = newTestAccessToken(t, tt.session, tt.owner)
+			}
+
+			err := s.Revoke(oidcModel.TokenActionRequestModel{
+				Token:        token,
+				ClientId:     tt.clientId,
+				ClientSecret: tt.secret,
+			})
+			checkOAuthError(t, err, tt.wantCode)
+
+			if revoked := len(repo.revoked) > 0; revoked != tt.revoked {
+				t.Fatalf("revoked = %v, want %v", revoked, tt.revoked)
+			}
+		})
+	}
+}
+
+func TestOidcAccessTokenWithRefreshHint(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := &config.JWTKey{Kid: "k1", Method: jwt.SigningMethodRS256, Private: private, Public: &private.PublicKey}
+
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T) string // Настройка ключей и выпуск токена доступа сессии
+		columns []string                  // Столбцы, по которым токен ищется в сессиях
+	}{
+		{
+			name: "signed with key",
+			prepare: func(t *testing.T) string {
+				config.AppJWTKeys = config.JWTKeySet{Keys: []*config.JWTKey{key}}
+				t.Cleanup(func() { config.AppJWTKeys = config.JWTKeySet{} })
+
+				token := jwt.NewWithClaims(jwt.SigningMethodRS256, &tokenClaims{
+					StandardClaims: jwt.StandardClaims{Id: "jti", ExpiresAt: time.Now().Add(time.Hour).Unix()},
+					UsersId:        testUserUuid,
+					AuthTypesId:    "auth-type-uuid",
+					SessionId:      "session-uuid",
+				})
+				token.Header["kid"] = key.Kid
+
+				signed, err := token.SignedString(private)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				return signed
+			},
+			// Токен с kid не принимается за токен обновления
+			columns: []string{oidcConstants.TOKEN_TYPE_HINT_ACCESS},
+		},
+		{
+			name: "shared secret",
+			prepare: func(t *testing.T) string {
+				viper.Set("token.signing_key_refresh", "access-secret")
+				return newTestAccessToken(t, "session-uuid", "")
+			},
+			// Токен проверяется как токен обновления, а после промаха - как токен доступа
+			columns: []string{oidcConstants.TOKEN_TYPE_HINT_REFRESH, oidcConstants.TOKEN_TYPE_HINT_ACCESS},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"/introspect", func(t *testing.T) {
+			s, repo := newTestOidcService(t)
+			token := tt.prepare(t)
+
+			data, err := s.Introspect(oidcModel.TokenActionRequestModel{
+				Token:         token,
+				TokenTypeHint: oidcConstants.TOKEN_TYPE_HINT_REFRESH,
+				ClientId:      "gateway",
+				ClientSecret:  "gateway-secret",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !data.Active {
+				t.Fatal("access token with refresh hint is inactive")
+			}
+
+			if strings.Join(repo.columns, " ") != strings.Join(tt.columns, " ") {
+				t.Fatalf("columns = %v, want %v", repo.columns, tt.columns)
+			}
+		})
+
+		t.Run(tt.name+"/revoke", func(t *testing.T) {
+			s, repo := newTestOidcService(t)
+			token := tt.prepare(t)
+
+			err := s.Revoke(oidcModel.TokenActionRequestModel{
+				Token:         token,
+				TokenTypeHint: oidcConstants.TOKEN_TYPE_HINT_REFRESH,
+				ClientId:      "gateway",
+				ClientSecret:  "gateway-secret",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(repo.revoked) != 1 {
+				t.Fatal("access token with refresh hint was not revoked")
+			}
+
+			if strings.Join(repo.columns, " ") != strings.Join(tt.columns, " ") {
+				t.Fatalf("columns = %v, want %v", repo.columns, tt.columns)
+			}
+		})
+	}
+}
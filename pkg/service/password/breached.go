@@ -0,0 +1,125 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/* Проверка пароля по локальному списку скомпрометированных паролей */
+type Checker interface {
+	IsBreached(password string) (bool, error)
+}
+
+/*
+* Создание проверки по пути из конфигурации (пустой путь - проверка отключена):
+*   - каталог - файлы-диапазоны в формате k-anonymity (Pwned Passwords): файл с именем из первых
+*     5 символов SHA-1 хэша содержит строки "ОСТАТОК_ХЭША:КОЛИЧЕСТВО"; читается только нужный диапазон;
+*   - файл - полные SHA-1 хэши (строки "ХЭШ" или "ХЭШ:КОЛИЧЕСТВО"), загружаются в память
+ */
+func NewChecker(path string) (Checker, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return &rangeChecker{dir: path}, nil
+	}
+
+	return loadHashChecker(path)
+}
+
+/* SHA-1 хэш пароля в верхнем регистре */
+func passwordHash(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+/* Хэш из строки списка (без счётчика) */
+func lineHash(line string) string {
+	if index := strings.IndexByte(line, ':'); index >= 0 {
+		line = line[:index]
+	}
+
+	return strings.ToUpper(strings.TrimSpace(line))
+}
+
+type rangeChecker struct {
+	dir string
+}
+
+func (c *rangeChecker) IsBreached(password string) (bool, error) {
+	hash := passwordHash(password)
+	prefix, suffix := hash[:5], hash[5:]
+
+	file, err := c.open(prefix)
+	if err != nil {
+		return false, err
+	}
+
+	if file == nil {
+		return false, nil
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if lineHash(scanner.Text()) == suffix {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+/* Открытие файла диапазона (допускается расширение .txt), nil - диапазон отсутствует */
+func (c *rangeChecker) open(prefix string) (*os.File, error) {
+	for _, name := range []string{prefix, prefix + ".txt"} {
+		file, err := os.Open(filepath.Join(c.dir, name))
+		if err == nil {
+			return file, nil
+		}
+
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+type hashChecker struct {
+	hashes map[string]struct{}
+}
+
+func loadHashChecker(path string) (*hashChecker, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	checker := &hashChecker{hashes: map[string]struct{}{}}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if hash := lineHash(scanner.Text()); len(hash) == 40 {
+			checker.hashes[hash] = struct{}{}
+		}
+	}
+
+	return checker, scanner.Err()
+}
+
+func (c *hashChecker) IsBreached(password string) (bool, error) {
+	_, ok := c.hashes[passwordHash(password)]
+	return ok, nil
+}
@@ -0,0 +1,246 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+/*
+* Алгоритм хэширования паролей. Хэш самоописываемый: содержит идентификатор алгоритма
+* и параметры, поэтому пароли, захэшированные разными алгоритмами, проверяются одинаково
+ */
+type Hasher interface {
+	Id() string
+	Hash(password string) (string, error)
+	Verify(encoded, password string) (bool, error)
+	Supports(encoded string) bool
+	NeedsRehash(encoded string) bool // Параметры хэша отличаются от текущих
+}
+
+/*
+* Параметры хэширования в файле конфигурации:
+*
+*	crypt:
+*	  algorithm: "argon2id"  # argon2id | bcrypt (по умолчанию bcrypt)
+*	  cost: 10               # стоимость bcrypt
+*	  argon2:
+*	    memory: 65536        # КиБ
+*	    time: 3
+*	    threads: 2
+*
+* После изменения параметров хэш пользователя обновляется при следующем успешном входе
+ */
+const (
+	ALGORITHM_ARGON2ID = "argon2id"
+	ALGORITHM_BCRYPT   = "bcrypt"
+
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+var hashers = map[string]Hasher{}
+var currentHasher Hasher
+
+/* Инициализация алгоритмов хэширования паролей */
+func InitHasher() error {
+	viper.SetDefault("crypt.algorithm", ALGORITHM_BCRYPT)
+	viper.SetDefault("crypt.argon2.memory", 64*1024)
+	viper.SetDefault("crypt.argon2.time", 3)
+	viper.SetDefault("crypt.argon2.threads", 2)
+
+	cost := viper.GetInt("crypt.cost")
+	if cost < bcrypt.MinCost {
+		cost = bcrypt.DefaultCost
+	}
+
+	if cost > bcrypt.MaxCost {
+		return fmt.Errorf("bcrypt cost must not exceed %d", bcrypt.MaxCost)
+	}
+
+	memory, time, threads := viper.GetInt("crypt.argon2.memory"), viper.GetInt("crypt.argon2.time"), viper.GetInt("crypt.argon2.threads")
+	if memory <= 0 || time <= 0 || threads <= 0 || threads > 255 {
+		return fmt.Errorf("invalid argon2 parameters")
+	}
+
+	hashers = map[string]Hasher{
+		ALGORITHM_BCRYPT: &bcryptHasher{cost: cost},
+		ALGORITHM_ARGON2ID: &argon2Hasher{
+			memory:  uint32(memory),
+			time:    uint32(time),
+			threads: uint8(threads),
+		},
+	}
+
+	algorithm := viper.GetString("crypt.algorithm")
+	hasher, ok := hashers[algorithm]
+	if !ok {
+		return fmt.Errorf("unknown password hashing algorithm: %s", algorithm)
+	}
+
+	currentHasher = hasher
+
+	return nil
+}
+
+/* Хэширование пароля текущим алгоритмом */
+func Hash(password string) (string, error) {
+	return currentHasher.Hash(password)
+}
+
+/*
+* Проверка пароля по хэшу любого поддерживаемого алгоритма. rehash - хэш создан другим
+* алгоритмом или с другими параметрами и должен быть обновлён
+ */
+func Verify(encoded, password string) (ok bool, rehash bool, err error) {
+	for _, hasher := range hashers {
+		if !hasher.Supports(encoded) {
+			continue
+		}
+
+		ok, err = hasher.Verify(encoded, password)
+		if err != nil || !ok {
+			return false, false, err
+		}
+
+		return true, hasher != currentHasher || hasher.NeedsRehash(encoded), nil
+	}
+
+	return false, false, nil
+}
+
+/* bcrypt (формат Modular Crypt: $2a$cost$...) */
+type bcryptHasher struct {
+	cost int
+}
+
+func (h *bcryptHasher) Id() string {
+	return ALGORITHM_BCRYPT
+}
+
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	return string(hash), err
+}
+
+func (h *bcryptHasher) Verify(encoded, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (h *bcryptHasher) Supports(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+func (h *bcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != h.cost
+}
+
+/* argon2id (формат PHC: $argon2id$v=19$m=65536,t=3,p=2$соль$хэш) */
+type argon2Hasher struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+}
+
+type argon2Params struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+var argon2Encoding = base64.RawStdEncoding
+
+func (h *argon2Hasher) Id() string {
+	return ALGORITHM_ARGON2ID
+}
+
+func (h *argon2Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.time, h.memory, h.threads, argon2KeyLength)
+
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		ALGORITHM_ARGON2ID, argon2.Version, h.memory, h.time, h.threads,
+		argon2Encoding.EncodeToString(salt), argon2Encoding.EncodeToString(key),
+	), nil
+}
+
+func (h *argon2Hasher) Verify(encoded, password string) (bool, error) {
+	params, err := decodeArgon2(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	key := argon2.IDKey([]byte(password), params.salt, params.time, params.memory, params.threads, uint32(len(params.key)))
+
+	return subtle.ConstantTimeCompare(key, params.key) == 1, nil
+}
+
+func (h *argon2Hasher) Supports(encoded string) bool {
+	return strings.HasPrefix(encoded, "$"+ALGORITHM_ARGON2ID+"$")
+}
+
+func (h *argon2Hasher) NeedsRehash(encoded string) bool {
+	params, err := decodeArgon2(encoded)
+	if err != nil {
+		return true
+	}
+
+	return params.memory != h.memory || params.time != h.time || params.threads != h.threads ||
+		len(params.key) != argon2KeyLength
+}
+
+/* Разбор хэша argon2id в формате PHC */
+func decodeArgon2(encoded string) (*argon2Params, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != ALGORITHM_ARGON2ID {
+		return nil, errors.New("invalid argon2id hash format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, err
+	}
+
+	if version != argon2.Version {
+		return nil, fmt.Errorf("unsupported argon2 version: %d", version)
+	}
+
+	params := &argon2Params{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if params.salt, err = argon2Encoding.DecodeString(parts[4]); err != nil {
+		return nil, err
+	}
+
+	if params.key, err = argon2Encoding.DecodeString(parts[5]); err != nil {
+		return nil, err
+	}
+
+	if len(params.key) == 0 {
+		return nil, errors.New("invalid argon2id hash format")
+	}
+
+	return params, nil
+}
@@ -0,0 +1,228 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/spf13/viper"
+)
+
+/*
+* Политика паролей в файле конфигурации:
+*
+*	password:
+*	  min_length: 8
+*	  max_length: 72                    # в байтах, bcrypt не учитывает более 72 байт
+*	  required_classes: ["digit"]       # lower | upper | digit | special
+*	  min_classes: 2                    # минимальное количество разных классов символов
+*	  ban_personal_data: true           # запрет email-адреса, имени, фамилии и т.д.
+*	  breached_list: "/data/pwned"      # каталог файлов-диапазонов (k-anonymity) или файл SHA-1 хэшей
+*	  history: 5                        # запрет повторного использования последних N паролей
+ */
+type Policy struct {
+	MinLength       int
+	MaxLength       int
+	RequiredClasses []string
+	MinClasses      int
+	BanPersonalData bool
+	BreachedList    string
+	History         int
+}
+
+/* Нарушение политики паролей */
+type Violation struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+/* Ошибка проверки пароля со списком всех нарушений */
+type PolicyError struct {
+	Violations []Violation `json:"violations"`
+}
+
+func (e *PolicyError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, item := range e.Violations {
+		messages = append(messages, item.Message)
+	}
+
+	return "Пароль не соответствует требованиям: " + strings.Join(messages, "; ")
+}
+
+/* Персональные данные пользователя, которые не должны входить в пароль */
+type Personal struct {
+	Email  string
+	Values []string // Имя, фамилия, никнейм и т.д.
+}
+
+const (
+	CLASS_LOWER   = "lower"
+	CLASS_UPPER   = "upper"
+	CLASS_DIGIT   = "digit"
+	CLASS_SPECIAL = "special"
+
+	personalMinLength = 3 // Более короткие значения не проверяются
+)
+
+var classNames = map[string]string{
+	CLASS_LOWER:   "строчную букву",
+	CLASS_UPPER:   "заглавную букву",
+	CLASS_DIGIT:   "цифру",
+	CLASS_SPECIAL: "специальный символ",
+}
+
+var policy Policy
+var breached Checker
+
+/* Текущая политика паролей */
+func GetPolicy() Policy {
+	return policy
+}
+
+/* Инициализация политики паролей и списка скомпрометированных паролей */
+func InitPolicy() error {
+	viper.SetDefault("password.min_length", 8)
+	viper.SetDefault("password.max_length", 72)
+	viper.SetDefault("password.min_classes", 2)
+	viper.SetDefault("password.ban_personal_data", true)
+	viper.SetDefault("password.history", 5)
+
+	value := Policy{
+		MinLength:       viper.GetInt("password.min_length"),
+		MaxLength:       viper.GetInt("password.max_length"),
+		RequiredClasses: viper.GetStringSlice("password.required_classes"),
+		MinClasses:      viper.GetInt("password.min_classes"),
+		BanPersonalData: viper.GetBool("password.ban_personal_data"),
+		BreachedList:    viper.GetString("password.breached_list"),
+		History:         viper.GetInt("password.history"),
+	}
+
+	for _, class := range value.RequiredClasses {
+		if _, ok := classNames[class]; !ok {
+			return fmt.Errorf("unknown password character class: %s", class)
+		}
+	}
+
+	if value.MinLength <= 0 || value.MaxLength < value.MinLength {
+		return fmt.Errorf("invalid password length limits")
+	}
+
+	checker, err := NewChecker(value.BreachedList)
+	if err != nil {
+		return err
+	}
+
+	policy, breached = value, checker
+
+	return nil
+}
+
+/* Проверка пароля на соответствие политике (nil или *PolicyError) */
+func Validate(password string, personal Personal) error {
+	violations := make([]Violation, 0)
+
+	length := utf8.RuneCountInString(password)
+	if length < policy.MinLength {
+		violations = append(violations, Violation{
+			Code:    "too_short",
+			Message: fmt.Sprintf("длина пароля должна быть не менее %d символов", policy.MinLength),
+		})
+	}
+
+	if len(password) > policy.MaxLength {
+		violations = append(violations, Violation{
+			Code:    "too_long",
+			Message: fmt.Sprintf("длина пароля должна быть не более %d байт", policy.MaxLength),
+		})
+	}
+
+	classes := characterClasses(password)
+	for _, class := range policy.RequiredClasses {
+		if !classes[class] {
+			violations = append(violations, Violation{
+				Code:    "missing_" + class,
+				Message: "пароль должен содержать " + classNames[class],
+			})
+		}
+	}
+
+	if len(classes) < policy.MinClasses {
+		violations = append(violations, Violation{
+			Code:    "too_few_classes",
+			Message: fmt.Sprintf("пароль должен содержать символы не менее %d типов (строчные и заглавные буквы, цифры, специальные символы)", policy.MinClasses),
+		})
+	}
+
+	if policy.BanPersonalData && containsPersonal(password, personal) {
+		violations = append(violations, Violation{
+			Code:    "personal_data",
+			Message: "пароль не должен содержать email-адрес или персональные данные",
+		})
+	}
+
+	if length > 0 && breached != nil {
+		found, err := breached.IsBreached(password)
+		if err != nil {
+			return err
+		}
+
+		if found {
+			violations = append(violations, Violation{
+				Code:    "breached",
+				Message: "пароль встречается в утечках данных, выберите другой",
+			})
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+
+	return nil
+}
+
+/* Ошибка повторного использования одного из последних паролей */
+func NewReuseError() error {
+	return &PolicyError{Violations: []Violation{{
+		Code:    "reused",
+		Message: fmt.Sprintf("пароль совпадает с одним из %d последних паролей", policy.History),
+	}}}
+}
+
+func characterClasses(password string) map[string]bool {
+	classes := map[string]bool{}
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			classes[CLASS_LOWER] = true
+		case unicode.IsUpper(r):
+			classes[CLASS_UPPER] = true
+		case unicode.IsDigit(r):
+			classes[CLASS_DIGIT] = true
+		default:
+			classes[CLASS_SPECIAL] = true
+		}
+	}
+
+	return classes
+}
+
+func containsPersonal(password string, personal Personal) bool {
+	lower := strings.ToLower(password)
+
+	values := append([]string{}, personal.Values...)
+	if personal.Email != "" {
+		values = append(values, personal.Email, strings.SplitN(personal.Email, "@", 2)[0])
+	}
+
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if utf8.RuneCountInString(value) >= personalMinLength && strings.Contains(lower, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+/*
+* Политика ограничения: Limit запросов за Period с допустимым всплеском Burst
+* (ёмкость корзины маркеров). Limit = 0 отключает ограничение
+ */
+type Policy struct {
+	Limit  int
+	Period time.Duration
+	Burst  int
+}
+
+/* Результат проверки запроса */
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // Время до полного восстановления корзины
+	RetryAfter time.Duration // Время до появления следующего маркера (для отклонённого запроса)
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+/* Ограничитель запросов по алгоритму корзины маркеров (token bucket), хранит состояние в памяти */
+type Limiter struct {
+	policy    Policy
+	rate      float64 // Маркеров в секунду
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+/* Создание нового ограничителя запросов */
+func NewLimiter(policy Policy) *Limiter {
+	if policy.Burst <= 0 {
+		policy.Burst = policy.Limit
+	}
+
+	rate := 0.0
+	if policy.Period > 0 {
+		rate = float64(policy.Limit) / policy.Period.Seconds()
+	}
+
+	return &Limiter{
+		policy:  policy,
+		rate:    rate,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+/* Признак отключённого ограничения */
+func (l *Limiter) Disabled() bool {
+	return l.policy.Limit <= 0 || l.rate <= 0
+}
+
+/* Политика ограничения */
+func (l *Limiter) Policy() Policy {
+	return l.policy
+}
+
+/* Время полного восстановления корзины (окно, за которое доступно Burst запросов) */
+func (l *Limiter) Window() time.Duration {
+	return l.duration(float64(l.policy.Burst))
+}
+
+/* Проверка и учёт запроса с ключом key */
+func (l *Limiter) Allow(key string, now time.Time) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	capacity := float64(l.policy.Burst)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		l.buckets[key] = b
+	}
+
+	// Пополнение корзины за прошедшее время
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+elapsed*l.rate)
+		b.updated = now
+	}
+
+	result := Result{Limit: l.policy.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = l.duration(1 - b.tokens)
+	}
+
+	result.Remaining = int(math.Floor(b.tokens))
+	result.Reset = l.duration(capacity - b.tokens)
+
+	return result
+}
+
+/* Время накопления заданного количества маркеров */
+func (l *Limiter) duration(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens / l.rate * float64(time.Second)))
+}
+
+/* Удаление заполненных корзин, которые больше не нужно хранить (не чаще одного раза за период) */
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.policy.Period {
+		return
+	}
+
+	capacity := float64(l.policy.Burst)
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.updated).Seconds()*l.rate >= capacity {
+			delete(l.buckets, key)
+		}
+	}
+
+	l.lastSweep = now
+}
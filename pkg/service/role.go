@@ -2,26 +2,51 @@ package service
 
 import (
 	rbacModel "main-server/pkg/model/rbac"
+	userModel "main-server/pkg/model/user"
 	repository "main-server/pkg/repository"
 )
 
 /* Структура сервиса ролей */
 type RoleService struct {
-	repo repository.Role
+	repo   repository.Role
+	user   repository.User
+	domain repository.Domain
 }
 
 /* Функция для создания нового сервиса ролей */
-func NewRoleService(repo repository.Role) *RoleService {
+func NewRoleService(repo repository.Role, user repository.User, domain repository.Domain) *RoleService {
 	return &RoleService{
-		repo: repo,
+		repo:   repo,
+		user:   user,
+		domain: domain,
 	}
 }
 
+/* Метод для создания роли */
+func (s *RoleService) Create(user *userModel.UserIdentityModel, data rbacModel.RoleCreateModel) (*rbacModel.RoleModel, error) {
+	return s.repo.Create(user, data)
+}
+
 /* Метод для получения роли */
 func (s *RoleService) Get(column string, value interface{}, check bool) (*rbacModel.RoleModel, error) {
 	return s.repo.Get(column, value, check)
 }
 
+/* Метод для получения всех ролей домена */
+func (s *RoleService) GetAll(user *userModel.UserIdentityModel, domainUuid *string) (*rbacModel.RolesModel, error) {
+	return s.repo.GetAll(user, domainUuid)
+}
+
+/* Метод для обновления роли */
+func (s *RoleService) Update(data rbacModel.RoleUpdateModel) (*rbacModel.RoleModel, error) {
+	return s.repo.Update(data)
+}
+
+/* Метод для удаления роли */
+func (s *RoleService) Delete(roleUuid string) (bool, error) {
+	return s.repo.Delete(roleUuid)
+}
+
 /* Проверка существования у пользователя конкретной роли */
 func (s *RoleService) HasRole(usersId, domainsId int, roleValue string) (bool, error) {
 	return s.repo.HasRole(usersId, domainsId, roleValue)
@@ -31,3 +56,40 @@ func (s *RoleService) HasRole(usersId, domainsId int, roleValue string) (bool, e
 func (s *RoleService) HasRoleWithSubject(userId, domainId int, roleValue, subjectId string) (bool, error) {
 	return s.repo.HasRoleWithSubject(userId, domainId, roleValue, subjectId)
 }
+
+/* Выдача роли пользователю */
+func (s *RoleService) AddRoleForUser(user *userModel.UserIdentityModel, data rbacModel.AccessRoleModel) (bool, error) {
+	return s.repo.AddRoleForUser(user, data)
+}
+
+/* Отзыв роли у пользователя */
+func (s *RoleService) DeleteRoleForUser(user *userModel.UserIdentityModel, data rbacModel.AccessRoleModel) (bool, error) {
+	return s.repo.DeleteRoleForUser(user, data)
+}
+
+/* Получение всех ролей определённого пользователя в домене */
+func (s *RoleService) GetUserRoles(user *userModel.UserIdentityModel, data rbacModel.AccessUserModel) (*userModel.UserRoleModel, error) {
+	target, err := s.user.Get("uuid", data.UserUuid, true)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := userModel.UserIdentityModel{
+		UserId:     target.Id,
+		UserUuid:   target.Uuid,
+		DomainId:   user.DomainId,
+		DomainUuid: user.DomainUuid,
+	}
+
+	if data.DomainUuid != nil {
+		domain, err := s.domain.Get("uuid", *data.DomainUuid, true)
+		if err != nil {
+			return nil, err
+		}
+
+		identity.DomainId = domain.Id
+		identity.DomainUuid = domain.Uuid
+	}
+
+	return s.user.GetAllRoles(identity)
+}
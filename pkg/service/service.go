@@ -2,8 +2,10 @@ package service
 
 import (
 	emailModel "main-server/pkg/model/email"
+	oidcModel "main-server/pkg/model/oidc"
 	rbacModel "main-server/pkg/model/rbac"
 	resourceModel "main-server/pkg/model/resource"
+	serviceModel "main-server/pkg/model/service"
 	userModel "main-server/pkg/model/user"
 	repository "main-server/pkg/repository"
 
@@ -11,21 +13,96 @@ import (
 )
 
 type Authorization interface {
-	CreateUser(user userModel.UserSignUpModel) (userModel.UserAuthDataModel, error)
+	CreateUser(user userModel.UserSignUpModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
 	UploadProfileImage(c *gin.Context, filepath string) (bool, error)
-	LoginUser(user userModel.UserSignInModel) (userModel.UserAuthDataModel, error)
-	LoginUserOAuth2(code string) (userModel.UserAuthDataModel, error)
+	LoginUser(user userModel.UserSignInModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+	LoginUserOAuth2(provider, code string, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+	GetOAuth2Url(provider, state string) (userModel.OAuth2UrlModel, error)
 	Refresh(data userModel.TokenLogoutDataModel, refreshToken string) (userModel.UserAuthDataModel, error)
 	Logout(tokens userModel.TokenLogoutDataModel) (bool, error)
 	Activate(link string) (bool, error)
-	RecoveryPassword(email string) (bool, error)
+	RecoveryPassword(email string, session userModel.SessionInfoModel) (bool, error)
 	ResetPassword(data userModel.ResetPasswordModel) (bool, error)
+	ResendActivation(email string) (bool, error)
+	IsActivated(userId int) (bool, error)
+	RequestLoginCode(email string) (bool, error)
+	LoginByLink(data userModel.PasswordlessLinkModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+	LoginByCode(data userModel.PasswordlessCodeModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+}
+
+type Session interface {
+	GetAll(userId int, currentUuid string) (*userModel.SessionsModel, error)
+	Delete(userId int, sessionUuid string) (bool, error)
+	DeleteOthers(userId int, currentUuid string) (bool, error)
+}
+
+type Mfa interface {
+	GetStatus(userId int) (*userModel.MfaStatusModel, error)
+	SetupTotp(userId int) (*userModel.MfaTotpSetupModel, error)
+	EnableTotp(userId int, code string) (*userModel.MfaRecoveryCodesModel, error)
+	DisableTotp(userId int, code string) (bool, error)
+	RegenerateRecoveryCodes(userId int, code string) (*userModel.MfaRecoveryCodesModel, error)
+	SetupTotpLogin(data userModel.MfaTokenModel) (*userModel.MfaTotpSetupModel, error)
+	VerifyLogin(data userModel.MfaVerifyModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+	EnableTotpLogin(data userModel.MfaVerifyModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, *userModel.MfaRecoveryCodesModel, error)
+}
+
+type WebAuthn interface {
+	GetAll(userId int) (*userModel.WebAuthnCredentialsModel, error)
+	BeginRegistration(userId int) (*userModel.WebAuthnBeginModel, error)
+	FinishRegistration(userId int, data userModel.WebAuthnRegisterFinishModel) (*userModel.WebAuthnCredentialModel, error)
+	BeginLogin(email string) (*userModel.WebAuthnBeginModel, error)
+	FinishLogin(data userModel.WebAuthnLoginFinishModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+	Delete(userId int, credentialUuid string) (bool, error)
+}
+
+type Identity interface {
+	GetAll(userId int) (*userModel.IdentitiesModel, error)
+	Link(userId int, provider, code string) (*userModel.IdentityModel, error)
+	Unlink(userId int, identityUuid string) (bool, error)
+}
+
+type EmailChange interface {
+	Request(userId int, sessionUuid string, data userModel.EmailChangeRequestModel) (*userModel.EmailChangeModel, error)
+	Confirm(link string) (bool, error)
+	Cancel(link string) (bool, error)
+}
+
+type ApiKey interface {
+	Create(actor *userModel.UserIdentityModel, userId int, data userModel.ApiKeyCreateModel) (*userModel.ApiKeyCreatedModel, error)
+	GetAll(userId int) (*userModel.ApiKeysModel, error)
+	Revoke(userId int, apiKeyUuid string) (bool, error)
+	Authenticate(key string) (*userModel.ApiKeyIdentityModel, error)
+}
+
+type ServiceAccount interface {
+	Create(actor *userModel.UserIdentityModel, data userModel.ServiceAccountCreateModel) (*userModel.ServiceAccountModel, error)
+	GetAll() (*userModel.ServiceAccountsModel, error)
+	Delete(accountUuid string) (bool, error)
+	CreateKey(actor *userModel.UserIdentityModel, data userModel.ServiceAccountApiKeyCreateModel) (*userModel.ApiKeyCreatedModel, error)
+	GetKeys(accountUuid string) (*userModel.ApiKeysModel, error)
+	RevokeKey(data userModel.ServiceAccountApiKeyModel) (bool, error)
+}
+
+type UserAdmin interface {
+	Search(actor *userModel.UserIdentityModel, data userModel.UserSearchModel) (*userModel.UsersListModel, error)
+	Get(actor *userModel.UserIdentityModel, usersUuid string) (*userModel.UserDetailModel, error)
+}
+
+type Ban interface {
+	GetActive(userId int) (*userModel.BanModel, error)
+	GetAll(usersUuid string) (*userModel.BansModel, error)
+	Create(actor *userModel.UserIdentityModel, data userModel.BanCreateModel) (*userModel.BanModel, error)
+	Lift(actor *userModel.UserIdentityModel, data userModel.BanUserModel) (bool, error)
 }
 
 type Token interface {
 	ParseToken(token, signingKey string) (userModel.TokenOutputParse, error)
 	ParseTokenWithoutValid(token, signingKey string) (userModel.TokenOutputParse, error)
+	IsRevoked(tokenId string) (bool, error)
 	ParseResetToken(pToken, signingKey string) (userModel.ResetTokenOutputParse, error)
+	ParseMfaToken(token, signingKey string) (userModel.MfaTokenOutputParse, error)
+	GetJWKS() []serviceModel.JWKModel
 }
 
 type AuthType interface {
@@ -43,15 +120,48 @@ type User interface {
 type Domain interface {
 
 	// CRUD
+	Create(user *userModel.UserIdentityModel, data rbacModel.DomainCreateModel) (*rbacModel.DomainModel, error)
 	Get(column string, value interface{}, check bool) (*rbacModel.DomainModel, error)
+	GetAll() (*rbacModel.DomainsModel, error)
+	Update(data rbacModel.DomainUpdateModel) (*rbacModel.DomainModel, error)
+	Delete(domainUuid string) (bool, error)
 }
 
 type Role interface {
 	HasRole(usersId, domainsId int, roleValue string) (bool, error)
 	HasRoleWithSubject(userId, domainId int, roleValue, subjectId string) (bool, error)
+	AddRoleForUser(user *userModel.UserIdentityModel, data rbacModel.AccessRoleModel) (bool, error)
+	DeleteRoleForUser(user *userModel.UserIdentityModel, data rbacModel.AccessRoleModel) (bool, error)
+	GetUserRoles(user *userModel.UserIdentityModel, data rbacModel.AccessUserModel) (*userModel.UserRoleModel, error)
 
 	// CRUD
+	Create(user *userModel.UserIdentityModel, data rbacModel.RoleCreateModel) (*rbacModel.RoleModel, error)
 	Get(column string, value interface{}, check bool) (*rbacModel.RoleModel, error)
+	GetAll(user *userModel.UserIdentityModel, domainUuid *string) (*rbacModel.RolesModel, error)
+	Update(data rbacModel.RoleUpdateModel) (*rbacModel.RoleModel, error)
+	Delete(roleUuid string) (bool, error)
+}
+
+type Object interface {
+	Enforce(userId, domainId int, objectUuid, action string) (bool, error)
+
+	// CRUD
+	Create(user *userModel.UserIdentityModel, data rbacModel.ResourceModel) (*rbacModel.ObjectDbModel, error)
+	Get(column string, value interface{}, check bool) (*rbacModel.ObjectDbModel, error)
+	Delete(objectUuid string) (bool, error)
+}
+
+type Oidc interface {
+	CreateClient(data oidcModel.ClientCreateModel) (*oidcModel.ClientCreatedModel, error)
+	GetClients() (*oidcModel.ClientsModel, error)
+	DeleteClient(clientId string) (bool, error)
+	ValidateAuthorize(data oidcModel.AuthorizeModel) (*oidcModel.ClientModel, error)
+	Authorize(user *userModel.UserIdentityModel, data oidcModel.AuthorizeModel) (*oidcModel.AuthorizeRedirectModel, error)
+	Token(data oidcModel.TokenRequestModel) (*oidcModel.TokenResponseModel, error)
+	Introspect(data oidcModel.TokenActionRequestModel) (*oidcModel.IntrospectionModel, error)
+	Revoke(data oidcModel.TokenActionRequestModel) error
+	GetUserInfo(c *gin.Context, user *userModel.UserIdentityModel) (*oidcModel.UserInfoModel, error)
+	GetDiscovery() oidcModel.DiscoveryModel
 }
 
 type ServiceMain interface {
@@ -60,22 +170,45 @@ type ServiceMain interface {
 
 type Service struct {
 	Authorization
+	Session
+	Mfa
+	WebAuthn
+	Identity
+	EmailChange
+	ApiKey
+	ServiceAccount
+	Ban
+	UserAdmin
 	Token
 	User
 	Domain
 	Role
+	Object
+	Oidc
 	ServiceMain
 }
 
 func NewService(repos *repository.Repository) *Service {
-	tokenService := NewTokenService(repos.Role, repos.User, repos.AuthType)
+	tokenService := NewTokenService(repos.Role, repos.User, repos.AuthType, repos.Denylist)
+	guard := NewLockoutGuard(repos.Throttle)
 
 	return &Service{
-		Token:         tokenService,
-		Authorization: NewAuthService(repos.Authorization, *tokenService),
-		User:          NewUserService(repos.User),
-		Domain:        NewDomainService(repos.Domain),
-		Role:          NewRoleService(repos.Role),
-		ServiceMain:   NewServiceMainService(repos.ServiceMain),
+		Token:          tokenService,
+		Authorization:  NewAuthService(repos.Authorization, *tokenService, guard),
+		Session:        NewSessionService(repos.Session),
+		Mfa:            NewMfaService(repos.Mfa, repos.User, *tokenService, guard),
+		WebAuthn:       NewWebAuthnService(repos.WebAuthn),
+		Identity:       NewIdentityService(repos.Identity),
+		EmailChange:    NewEmailChangeService(repos.EmailChange),
+		ApiKey:         NewApiKeyService(repos.ApiKey),
+		ServiceAccount: NewServiceAccountService(repos.ServiceAccount),
+		Ban:            NewBanService(repos.Ban),
+		UserAdmin:      NewUserAdminService(repos.UserAdmin),
+		User:           NewUserService(repos.User),
+		Domain:         NewDomainService(repos.Domain),
+		Role:           NewRoleService(repos.Role, repos.User, repos.Domain),
+		Object:         NewObjectService(repos.Object),
+		Oidc:           NewOidcService(repos.Oidc, repos.User, *tokenService),
+		ServiceMain:    NewServiceMainService(repos.ServiceMain),
 	}
 }
@@ -0,0 +1,48 @@
+package service
+
+import (
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+)
+
+/* Структура сервиса сервисных аккаунтов */
+type ServiceAccountService struct {
+	repo repository.ServiceAccount
+}
+
+/* Функция для создания нового сервиса сервисных аккаунтов */
+func NewServiceAccountService(repo repository.ServiceAccount) *ServiceAccountService {
+	return &ServiceAccountService{
+		repo: repo,
+	}
+}
+
+/* Создание сервисного аккаунта */
+func (s *ServiceAccountService) Create(actor *userModel.UserIdentityModel, data userModel.ServiceAccountCreateModel) (*userModel.ServiceAccountModel, error) {
+	return s.repo.Create(actor, data)
+}
+
+/* Получение всех сервисных аккаунтов */
+func (s *ServiceAccountService) GetAll() (*userModel.ServiceAccountsModel, error) {
+	return s.repo.GetAll()
+}
+
+/* Удаление сервисного аккаунта */
+func (s *ServiceAccountService) Delete(accountUuid string) (bool, error) {
+	return s.repo.Delete(accountUuid)
+}
+
+/* Создание API-ключа сервисного аккаунта */
+func (s *ServiceAccountService) CreateKey(actor *userModel.UserIdentityModel, data userModel.ServiceAccountApiKeyCreateModel) (*userModel.ApiKeyCreatedModel, error) {
+	return s.repo.CreateKey(actor, data)
+}
+
+/* Получение всех API-ключей сервисного аккаунта */
+func (s *ServiceAccountService) GetKeys(accountUuid string) (*userModel.ApiKeysModel, error) {
+	return s.repo.GetKeys(accountUuid)
+}
+
+/* Отзыв API-ключа сервисного аккаунта */
+func (s *ServiceAccountService) RevokeKey(data userModel.ServiceAccountApiKeyModel) (bool, error) {
+	return s.repo.RevokeKey(data)
+}
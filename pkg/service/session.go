@@ -0,0 +1,33 @@
+package service
+
+import (
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+)
+
+/* Структура сервиса сессий пользователя */
+type SessionService struct {
+	repo repository.Session
+}
+
+/* Функция для создания нового сервиса сессий */
+func NewSessionService(repo repository.Session) *SessionService {
+	return &SessionService{
+		repo: repo,
+	}
+}
+
+/* Получение всех активных сессий пользователя */
+func (s *SessionService) GetAll(userId int, currentUuid string) (*userModel.SessionsModel, error) {
+	return s.repo.GetAll(userId, currentUuid)
+}
+
+/* Завершение определённой сессии пользователя */
+func (s *SessionService) Delete(userId int, sessionUuid string) (bool, error) {
+	return s.repo.Delete(userId, sessionUuid)
+}
+
+/* Завершение всех сессий пользователя, кроме текущей */
+func (s *SessionService) DeleteOthers(userId int, currentUuid string) (bool, error) {
+	return s.repo.DeleteOthers(userId, currentUuid)
+}
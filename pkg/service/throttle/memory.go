@@ -0,0 +1,83 @@
+package throttle
+
+import (
+	"sync"
+	"time"
+)
+
+/* Хранилище счётчиков в памяти процесса (подходит для одного экземпляра сервера) */
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]Entry
+	lastSweep time.Time
+}
+
+/* Создание нового хранилища счётчиков в памяти */
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]Entry),
+	}
+}
+
+func (s *MemoryStore) Get(key string, now time.Time, window time.Duration) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return Entry{}, nil
+	}
+
+	if IsExpired(entry, now, window) {
+		delete(s.entries, key)
+		return Entry{}, nil
+	}
+
+	return entry, nil
+}
+
+func (s *MemoryStore) Fail(key string, now time.Time, window time.Duration, backoff Backoff) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now, window)
+
+	entry, ok := s.entries[key]
+	if !ok || IsExpired(entry, now, window) {
+		entry = Entry{}
+	}
+
+	entry.Failures++
+	entry.LastFailure = now
+	if delay := backoff(entry.Failures); delay > 0 {
+		entry.LockedUntil = now.Add(delay)
+	}
+
+	s.entries[key] = entry
+
+	return entry, nil
+}
+
+func (s *MemoryStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+
+	return nil
+}
+
+/* Удаление устаревших счётчиков (не чаще одного раза за окно) */
+func (s *MemoryStore) sweep(now time.Time, window time.Duration) {
+	if now.Sub(s.lastSweep) < window {
+		return
+	}
+
+	for key, entry := range s.entries {
+		if IsExpired(entry, now, window) {
+			delete(s.entries, key)
+		}
+	}
+
+	s.lastSweep = now
+}
@@ -0,0 +1,139 @@
+package throttle
+
+import (
+	"time"
+)
+
+/* Состояние счётчика неудачных попыток по одному ключу (аккаунт, IP-адрес и т.д.) */
+type Entry struct {
+	Failures    int
+	LastFailure time.Time
+	LockedUntil time.Time
+}
+
+/* Расчёт времени блокировки по количеству неудачных попыток (0 - блокировка не требуется) */
+type Backoff func(failures int) time.Duration
+
+/*
+* Хранилище счётчиков неудачных попыток. Реализации: в памяти процесса (NewMemoryStore)
+* и в PostgreSQL (repository.ThrottlePostgres) - для нескольких экземпляров сервера
+ */
+type Store interface {
+	// Получение счётчика (нулевое значение, если попыток не было или окно истекло)
+	Get(key string, now time.Time, window time.Duration) (Entry, error)
+
+	// Атомарное увеличение счётчика с установкой времени блокировки
+	Fail(key string, now time.Time, window time.Duration, backoff Backoff) (Entry, error)
+
+	// Сброс счётчика (например, после успешного входа)
+	Reset(key string) error
+}
+
+/* Ключ ограничения с собственным количеством попыток без блокировки */
+type Key struct {
+	Value     string
+	Threshold int
+}
+
+/* Ошибка временной блокировки попыток */
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return "Слишком много попыток! Повторите попытку позже"
+}
+
+/*
+* Защита от перебора: после Threshold неудачных попыток ключ блокируется на BaseDelay,
+* каждая следующая неудача удваивает время блокировки (не более MaxDelay).
+* Счётчик сбрасывается, если в течение Window не было неудачных попыток
+ */
+type Guard struct {
+	store     Store
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	window    time.Duration
+}
+
+/* Создание нового экземпляра защиты от перебора */
+func NewGuard(store Store, baseDelay, maxDelay, window time.Duration) *Guard {
+	return &Guard{
+		store:     store,
+		baseDelay: baseDelay,
+		maxDelay:  maxDelay,
+		window:    window,
+	}
+}
+
+/* Проверка, не заблокирован ли хотя бы один из ключей */
+func (g *Guard) Check(keys ...Key) error {
+	now := time.Now()
+
+	var retryAfter time.Duration
+	for _, key := range keys {
+		entry, err := g.store.Get(key.Value, now, g.window)
+		if err != nil {
+			return err
+		}
+
+		if wait := entry.LockedUntil.Sub(now); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LockedError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+/* Учёт неудачной попытки для всех ключей */
+func (g *Guard) Fail(keys ...Key) error {
+	now := time.Now()
+
+	for _, key := range keys {
+		if _, err := g.store.Fail(key.Value, now, g.window, g.backoff(key.Threshold)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/* Сброс счётчиков после успешной попытки */
+func (g *Guard) Reset(keys ...Key) error {
+	for _, key := range keys {
+		if err := g.store.Reset(key.Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/* Экспоненциальное увеличение времени блокировки после превышения порога */
+func (g *Guard) backoff(threshold int) Backoff {
+	return func(failures int) time.Duration {
+		if failures < threshold {
+			return 0
+		}
+
+		delay := g.baseDelay
+		for i := threshold; i < failures && delay < g.maxDelay; i++ {
+			delay *= 2
+		}
+
+		if delay > g.maxDelay {
+			delay = g.maxDelay
+		}
+
+		return delay
+	}
+}
+
+/* Признак устаревшего счётчика: окно истекло и блокировка не действует */
+func IsExpired(entry Entry, now time.Time, window time.Duration) bool {
+	return now.Sub(entry.LastFailure) > window && !now.Before(entry.LockedUntil)
+}
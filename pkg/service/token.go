@@ -1,11 +1,22 @@
 package service
 
 import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
 	"errors"
+	config "main-server/config"
+	mfaConstants "main-server/pkg/constant/mfa"
+	serviceModel "main-server/pkg/model/service"
 	userModel "main-server/pkg/model/user"
 	repository "main-server/pkg/repository"
+	"main-server/pkg/service/denylist"
+	"math/big"
+	"strings"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/spf13/viper"
 )
 
 /* Структура TokenService */
@@ -13,17 +24,20 @@ type TokenService struct {
 	role     repository.Role
 	user     repository.User
 	authType repository.AuthType
+	denylist denylist.Store
 }
 
 /* Функция создания нового сервиса TokenService */
 func NewTokenService(role repository.Role,
 	user repository.User,
 	authType repository.AuthType,
+	store denylist.Store,
 ) *TokenService {
 	return &TokenService{
 		role:     role,
 		user:     user,
 		authType: authType,
+		denylist: store,
 	}
 }
 
@@ -32,18 +46,40 @@ type tokenClaims struct {
 	jwt.StandardClaims
 	UsersId     string  `json:"users_id"`      // ID for user
 	AuthTypesId string  `json:"auth_types_id"` // Type auth for user
+	SessionId   string  `json:"session_id"`    // Session of user
 	TokenApi    *string `json:"token_api"`     // External token access
+	ClientId    string  `json:"client_id"`     // OAuth2 client (client_credentials only)
+	Scope       string  `json:"scope"`         // Granted scopes separated by spaces
 }
 
-/* Парсинг токена с предварительной валидацией */
-func (s *TokenService) ParseToken(pToken, signingKey string) (userModel.TokenOutputParse, error) {
-	token, err := jwt.ParseWithClaims(pToken, &tokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+/*
+* Выбор ключа проверки подписи: токены доступа с kid в заголовке проверяются открытым ключом
+* из набора ключей, остальные - общим секретом signingKey (если он ещё принимается)
+ */
+func tokenKeyFunc(signingKey string) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		// Ключи из набора подписывают только токены доступа, секрет остальных токенов действует всегда
+		access := signingKey == viper.GetString("token.signing_key_access")
+
+		if kid, ok := token.Header["kid"].(string); ok && access {
+			return config.AppJWTKeys.VerifyKey(kid, token.Method)
+		}
+
+		if token.Method != jwt.SigningMethodHS256 {
 			return nil, errors.New("invalid signing method")
 		}
 
+		if access && !config.AppJWTKeys.AcceptsSecret() {
+			return nil, errors.New("signing key is required")
+		}
+
 		return []byte(signingKey), nil
-	})
+	}
+}
+
+/* Парсинг токена с предварительной валидацией */
+func (s *TokenService) ParseToken(pToken, signingKey string) (userModel.TokenOutputParse, error) {
+	token, err := jwt.ParseWithClaims(pToken, &tokenClaims{}, tokenKeyFunc(signingKey))
 	if err != nil {
 		return userModel.TokenOutputParse{}, err
 	}
@@ -68,22 +104,31 @@ func (s *TokenService) ParseToken(pToken, signingKey string) (userModel.TokenOut
 	}
 
 	return userModel.TokenOutputParse{
-		UsersId:   user.Id,
-		UsersUuid: claims.UsersId,
-		AuthType:  *authType,
-		TokenApi:  claims.TokenApi,
+		UsersId:     user.Id,
+		UsersUuid:   claims.UsersId,
+		SessionUuid: claims.SessionId,
+		AuthType:    *authType,
+		TokenApi:    claims.TokenApi,
+		ClientId:    claims.ClientId,
+		Scopes:      strings.Fields(claims.Scope),
+		TokenId:     claims.Id,
+		IssuedAt:    claims.IssuedAt,
+		ExpiresAt:   claims.ExpiresAt,
 	}, nil
 }
 
+/* Проверка, отозван ли токен доступа до истечения срока действия (tokenId - значение jti) */
+func (s *TokenService) IsRevoked(tokenId string) (bool, error) {
+	if tokenId == "" {
+		return false, nil
+	}
+
+	return s.denylist.Contains(tokenId, time.Now())
+}
+
 /* Parse token without validate check */
 func (s *TokenService) ParseTokenWithoutValid(pToken, signingKey string) (userModel.TokenOutputParse, error) {
-	token, err := jwt.ParseWithClaims(pToken, &tokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
-		}
-
-		return []byte(signingKey), nil
-	})
+	token, err := jwt.ParseWithClaims(pToken, &tokenClaims{}, tokenKeyFunc(signingKey))
 
 	// Получение данных из токена (с преобразованием к указателю на tokenClaims)
 	claims, ok := token.Claims.(*tokenClaims)
@@ -102,9 +147,16 @@ func (s *TokenService) ParseTokenWithoutValid(pToken, signingKey string) (userMo
 	}
 
 	return userModel.TokenOutputParse{
-		UsersId:  user.Id,
-		AuthType: *authType,
-		TokenApi: claims.TokenApi,
+		UsersId:     user.Id,
+		UsersUuid:   claims.UsersId,
+		SessionUuid: claims.SessionId,
+		AuthType:    *authType,
+		TokenApi:    claims.TokenApi,
+		ClientId:    claims.ClientId,
+		Scopes:      strings.Fields(claims.Scope),
+		TokenId:     claims.Id,
+		IssuedAt:    claims.IssuedAt,
+		ExpiresAt:   claims.ExpiresAt,
 	}, nil
 }
 
@@ -152,3 +204,73 @@ func (s *TokenService) ParseResetToken(pToken, signingKey string) (userModel.Res
 		Email:   claims.Email,
 	}, nil
 }
+
+/* Структура тела токена ожидания второго фактора */
+type tokenMfaClaims struct {
+	jwt.StandardClaims
+	UsersId string `json:"users_id"` // ID пользователя
+}
+
+/* Парсинг токена ожидания второго фактора */
+func (s *TokenService) ParseMfaToken(pToken, signingKey string) (userModel.MfaTokenOutputParse, error) {
+	token, err := jwt.ParseWithClaims(pToken, &tokenMfaClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("invalid signing method")
+		}
+
+		if signingKey == "" {
+			return nil, errors.New("signing key is empty")
+		}
+
+		return []byte(signingKey), nil
+	})
+	if err != nil || !token.Valid {
+		return userModel.MfaTokenOutputParse{}, errors.New("Ошибка: некорректный или просроченный токен, повторите вход")
+	}
+
+	claims, ok := token.Claims.(*tokenMfaClaims)
+	if !ok || !claims.VerifyAudience(mfaConstants.AUDIENCE_MFA, true) || claims.Id == "" {
+		return userModel.MfaTokenOutputParse{}, errors.New("Ошибка: некорректный токен")
+	}
+
+	user, err := s.user.Get("uuid", claims.UsersId, true)
+	if err != nil {
+		return userModel.MfaTokenOutputParse{}, err
+	}
+
+	return userModel.MfaTokenOutputParse{
+		UsersId:   user.Id,
+		UsersUuid: user.Uuid,
+		TokenId:   claims.Id,
+	}, nil
+}
+
+/* Получение открытых ключей подписи токенов доступа в формате JWKS */
+func (s *TokenService) GetJWKS() []serviceModel.JWKModel {
+	keys := make([]serviceModel.JWKModel, 0, len(config.AppJWTKeys.Keys))
+
+	for _, key := range config.AppJWTKeys.PublicKeys() {
+		jwk := serviceModel.JWKModel{
+			Kid: key.Kid,
+			Use: "sig",
+			Alg: key.Method.Alg(),
+		}
+
+		switch public := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+
+		keys = append(keys, jwk)
+	}
+
+	return keys
+}
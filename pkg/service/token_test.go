@@ -0,0 +1,105 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	config "main-server/config"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/spf13/viper"
+)
+
+func TestTokenKeyFuncSecret(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	viper.Set("token.signing_key_access", "access-secret")
+	viper.Set("token.signing_key_refresh", "refresh-secret")
+	t.Cleanup(func() { config.AppJWTKeys = config.JWTKeySet{} })
+
+	key := &config.JWTKey{Kid: "k1", Method: jwt.SigningMethodRS256, Private: private, Public: &private.PublicKey}
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		keys   config.JWTKeySet
+		secret string
+		valid  bool
+	}{
+		{
+			name:   "no keys configured",
+			keys:   config.JWTKeySet{},
+			secret: "access-secret",
+			valid:  true,
+		},
+		{
+			name:   "active signing key",
+			keys:   config.JWTKeySet{Keys: []*config.JWTKey{key}},
+			secret: "access-secret",
+			valid:  false,
+		},
+		{
+			name:   "migration window",
+			keys:   config.JWTKeySet{Keys: []*config.JWTKey{key}, SecretBefore: &future},
+			secret: "access-secret",
+			valid:  true,
+		},
+		{
+			name:   "migration window is over",
+			keys:   config.JWTKeySet{Keys: []*config.JWTKey{key}, SecretBefore: &past},
+			secret: "access-secret",
+			valid:  false,
+		},
+		{
+			name:   "refresh token",
+			keys:   config.JWTKeySet{Keys: []*config.JWTKey{key}},
+			secret: "refresh-secret",
+			valid:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.AppJWTKeys = tt.keys
+
+			signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{}).SignedString([]byte(tt.secret))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = jwt.ParseWithClaims(signed, &tokenClaims{}, tokenKeyFunc(tt.secret))
+			if (err == nil) != tt.valid {
+				t.Fatalf("valid = %v, want %v (%v)", err == nil, tt.valid, err)
+			}
+		})
+	}
+
+	t.Run("signed with key", func(t *testing.T) {
+		config.AppJWTKeys = config.JWTKeySet{Keys: []*config.JWTKey{key}}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, &tokenClaims{})
+		token.Header["kid"] = key.Kid
+
+		signed, err := token.SignedString(private)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = jwt.ParseWithClaims(signed, &tokenClaims{}, tokenKeyFunc("access-secret")); err != nil {
+			t.Fatal(err)
+		}
+
+		// Токен доступа, подписанный ключом из набора, не принимается за токен другого вида
+		for _, secret := range []string{"refresh-secret", "reset-secret"} {
+			if _, err = jwt.ParseWithClaims(signed, &tokenClaims{}, tokenKeyFunc(secret)); err == nil {
+				t.Fatalf("token signed with key is accepted for %s", secret)
+			}
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+)
+
+/* Структура сервиса просмотра пользователей администратором */
+type UserAdminService struct {
+	repo repository.UserAdmin
+}
+
+/* Функция для создания нового сервиса просмотра пользователей администратором */
+func NewUserAdminService(repo repository.UserAdmin) *UserAdminService {
+	return &UserAdminService{
+		repo: repo,
+	}
+}
+
+/* Поиск пользователей с фильтрами и постраничным выводом */
+func (s *UserAdminService) Search(actor *userModel.UserIdentityModel, data userModel.UserSearchModel) (*userModel.UsersListModel, error) {
+	return s.repo.Search(actor, data)
+}
+
+/* Получение полных сведений о пользователе */
+func (s *UserAdminService) Get(actor *userModel.UserIdentityModel, usersUuid string) (*userModel.UserDetailModel, error) {
+	return s.repo.Get(actor, usersUuid)
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+)
+
+/* Структура сервиса ключей доступа (WebAuthn) */
+type WebAuthnService struct {
+	repo repository.WebAuthn
+}
+
+/* Функция для создания нового сервиса ключей доступа */
+func NewWebAuthnService(repo repository.WebAuthn) *WebAuthnService {
+	return &WebAuthnService{
+		repo: repo,
+	}
+}
+
+/* Получение всех ключей доступа пользователя */
+func (s *WebAuthnService) GetAll(userId int) (*userModel.WebAuthnCredentialsModel, error) {
+	return s.repo.GetAll(userId)
+}
+
+/* Начало регистрации ключа доступа */
+func (s *WebAuthnService) BeginRegistration(userId int) (*userModel.WebAuthnBeginModel, error) {
+	return s.repo.BeginRegistration(userId)
+}
+
+/* Завершение регистрации ключа доступа */
+func (s *WebAuthnService) FinishRegistration(userId int, data userModel.WebAuthnRegisterFinishModel) (*userModel.WebAuthnCredentialModel, error) {
+	return s.repo.FinishRegistration(userId, data)
+}
+
+/* Начало входа по ключу доступа */
+func (s *WebAuthnService) BeginLogin(email string) (*userModel.WebAuthnBeginModel, error) {
+	return s.repo.BeginLogin(email)
+}
+
+/* Завершение входа по ключу доступа */
+func (s *WebAuthnService) FinishLogin(data userModel.WebAuthnLoginFinishModel, session userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	return s.repo.FinishLogin(data, session)
+}
+
+/* Удаление ключа доступа */
+func (s *WebAuthnService) Delete(userId int, credentialUuid string) (bool, error) {
+	return s.repo.Delete(userId, credentialUuid)
+}
@@ -1,8 +1,7 @@
 package util
 
 func GeneratePolicies(userId, domainId, objectId string, actions []string) [][]string {
-	var policies [][]string
-	policies = make([][]string, len(actions))
+	policies := make([][]string, 0, len(actions))
 
 	for _, item := range actions {
 		policies = append(policies, []string{userId, domainId, objectId, item})
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS u_tokens_users_id_idx;
+
+ALTER TABLE u_tokens
+    DROP CONSTRAINT IF EXISTS u_tokens_uuid_key,
+    DROP COLUMN IF EXISTS refreshed_at,
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS ip,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS uuid;
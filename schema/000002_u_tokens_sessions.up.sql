@@ -0,0 +1,14 @@
+-- Таблица u_tokens становится таблицей сессий: одна строка на каждое устройство пользователя
+ALTER TABLE u_tokens
+    ADD COLUMN uuid         VARCHAR(36),
+    ADD COLUMN user_agent   TEXT      NOT NULL DEFAULT '',
+    ADD COLUMN ip           VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    ADD COLUMN refreshed_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+UPDATE u_tokens SET uuid = gen_random_uuid()::text WHERE uuid IS NULL;
+
+ALTER TABLE u_tokens ALTER COLUMN uuid SET NOT NULL;
+ALTER TABLE u_tokens ADD CONSTRAINT u_tokens_uuid_key UNIQUE (uuid);
+
+CREATE INDEX u_tokens_users_id_idx ON u_tokens (users_id);
@@ -0,0 +1,12 @@
+-- Использованные (ротированные) токены обновления. Повторное предъявление такого токена
+-- означает его кражу, поэтому вся сессия (семейство токенов) отзывается
+CREATE TABLE u_tokens_consumed
+(
+    id            SERIAL PRIMARY KEY,
+    sessions_uuid VARCHAR(36) NOT NULL,
+    users_id      INT         NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    token_hash    VARCHAR(64) NOT NULL UNIQUE,
+    consumed_at   TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX u_tokens_consumed_sessions_uuid_idx ON u_tokens_consumed (sessions_uuid);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS oidc_codes;
+DROP TABLE IF EXISTS oidc_clients;
@@ -0,0 +1,26 @@
+-- Клиенты OpenID Connect (приложения, которым разрешена авторизация через данный сервер)
+CREATE TABLE oidc_clients
+(
+    id            SERIAL PRIMARY KEY,
+    client_id     VARCHAR(36)  NOT NULL UNIQUE,
+    secret_hash   VARCHAR(64),
+    name          VARCHAR(255) NOT NULL,
+    redirect_uris TEXT[]       NOT NULL DEFAULT '{}',
+    created_at    TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+-- Одноразовые коды авторизации (authorization code + PKCE)
+CREATE TABLE oidc_codes
+(
+    id                    SERIAL PRIMARY KEY,
+    code_hash             VARCHAR(64)   NOT NULL UNIQUE,
+    clients_id            INT           NOT NULL REFERENCES oidc_clients (id) ON DELETE CASCADE,
+    users_id              INT           NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    redirect_uri          TEXT          NOT NULL,
+    scope                 VARCHAR(1024) NOT NULL,
+    nonce                 VARCHAR(255),
+    code_challenge        VARCHAR(128)  NOT NULL,
+    code_challenge_method VARCHAR(16)   NOT NULL,
+    auth_time             TIMESTAMP     NOT NULL,
+    expires_at            TIMESTAMP     NOT NULL
+);
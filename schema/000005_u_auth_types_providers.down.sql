@@ -0,0 +1,3 @@
+DELETE FROM u_auth_types at
+WHERE at.value IN ('vk', 'github')
+  AND NOT EXISTS (SELECT 1 FROM u_users_auth_types uat WHERE uat.auth_types_id = at.id);
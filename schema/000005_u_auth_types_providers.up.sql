@@ -0,0 +1,5 @@
+-- Типы авторизации для внешних провайдеров OAuth2 (значение совпадает с названием провайдера в конфигурации)
+INSERT INTO u_auth_types (uuid, value)
+SELECT md5(random()::text || clock_timestamp()::text)::uuid, t.value
+FROM (VALUES ('vk'), ('github')) AS t (value)
+WHERE NOT EXISTS (SELECT 1 FROM u_auth_types at WHERE at.value = t.value);
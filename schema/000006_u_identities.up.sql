@@ -0,0 +1,14 @@
+-- Внешние учётные записи пользователя (по одной записи на каждую привязанную учётную запись провайдера)
+CREATE TABLE u_identities
+(
+    id            SERIAL PRIMARY KEY,
+    uuid          VARCHAR(36)  NOT NULL UNIQUE,
+    users_id      INT          NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    auth_types_id INT          NOT NULL REFERENCES u_auth_types (id) ON DELETE CASCADE,
+    subject       VARCHAR(255) NOT NULL,
+    email         VARCHAR(255),
+    created_at    TIMESTAMP    NOT NULL DEFAULT NOW(),
+    UNIQUE (auth_types_id, subject)
+);
+
+CREATE INDEX u_identities_users_id_idx ON u_identities (users_id);
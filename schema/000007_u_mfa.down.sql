@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS u_mfa_recovery_codes;
+DROP TABLE IF EXISTS u_mfa_totp;
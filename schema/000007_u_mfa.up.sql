@@ -0,0 +1,22 @@
+-- Секреты TOTP пользователей (не более одного на пользователя)
+CREATE TABLE u_mfa_totp
+(
+    id             SERIAL PRIMARY KEY,
+    users_id       INT         NOT NULL UNIQUE REFERENCES u_users (id) ON DELETE CASCADE,
+    secret         VARCHAR(64) NOT NULL,
+    is_enabled     BOOLEAN     NOT NULL DEFAULT FALSE,
+    last_used_step BIGINT      NOT NULL DEFAULT 0,
+    created_at     TIMESTAMP   NOT NULL DEFAULT NOW(),
+    enabled_at     TIMESTAMP
+);
+
+-- Одноразовые коды восстановления (хранятся только хэши)
+CREATE TABLE u_mfa_recovery_codes
+(
+    id         SERIAL PRIMARY KEY,
+    users_id   INT         NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    code_hash  VARCHAR(64) NOT NULL UNIQUE,
+    used_at    TIMESTAMP
+);
+
+CREATE INDEX u_mfa_recovery_codes_users_id_idx ON u_mfa_recovery_codes (users_id);
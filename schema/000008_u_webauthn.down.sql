@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS u_webauthn_sessions;
+DROP TABLE IF EXISTS u_webauthn_credentials;
+
+DELETE FROM u_auth_types WHERE value = 'webauthn';
@@ -0,0 +1,32 @@
+-- Тип авторизации по ключам доступа (WebAuthn / passkeys)
+INSERT INTO u_auth_types (uuid, value)
+SELECT md5(random()::text || clock_timestamp()::text)::uuid, 'webauthn'
+WHERE NOT EXISTS (SELECT 1 FROM u_auth_types WHERE value = 'webauthn');
+
+-- Зарегистрированные ключи доступа пользователей
+CREATE TABLE u_webauthn_credentials
+(
+    id               SERIAL PRIMARY KEY,
+    uuid             VARCHAR(36)  NOT NULL UNIQUE,
+    users_id         INT          NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    credential_id    BYTEA        NOT NULL UNIQUE,
+    public_key       BYTEA        NOT NULL,
+    attestation_type VARCHAR(64)  NOT NULL DEFAULT '',
+    aaguid           BYTEA,
+    sign_count       BIGINT       NOT NULL DEFAULT 0,
+    name             VARCHAR(255) NOT NULL DEFAULT '',
+    created_at       TIMESTAMP    NOT NULL DEFAULT NOW(),
+    last_used_at     TIMESTAMP
+);
+
+CREATE INDEX u_webauthn_credentials_users_id_idx ON u_webauthn_credentials (users_id);
+
+-- Данные незавершённых церемоний регистрации и входа (одноразовые)
+CREATE TABLE u_webauthn_sessions
+(
+    uuid       VARCHAR(36) PRIMARY KEY,
+    users_id   INT         NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    purpose    VARCHAR(16) NOT NULL,
+    data       JSONB       NOT NULL,
+    expires_at TIMESTAMP   NOT NULL
+);
@@ -0,0 +1,11 @@
+-- Одноразовые ссылки и коды для входа без пароля (не более одной активной записи на пользователя)
+CREATE TABLE u_login_codes
+(
+    id         SERIAL PRIMARY KEY,
+    users_id   INT         NOT NULL UNIQUE REFERENCES u_users (id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    code_hash  VARCHAR(64) NOT NULL,
+    attempts   INT         NOT NULL DEFAULT 0,
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP   NOT NULL
+);
@@ -0,0 +1,4 @@
+ALTER TABLE u_activations
+    DROP COLUMN IF EXISTS activated_at,
+    DROP COLUMN IF EXISTS expires_at,
+    DROP COLUMN IF EXISTS created_at;
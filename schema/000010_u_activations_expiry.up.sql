@@ -0,0 +1,8 @@
+-- Срок действия ссылок подтверждения аккаунта (NULL - ссылка не ограничена по времени)
+ALTER TABLE u_activations
+    ADD COLUMN created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    ADD COLUMN expires_at   TIMESTAMP,
+    ADD COLUMN activated_at TIMESTAMP;
+
+-- Уже отправленные ссылки продолжают действовать ещё сутки
+UPDATE u_activations SET expires_at = NOW() + INTERVAL '24 hours' WHERE is_activated = false;
@@ -0,0 +1,16 @@
+-- Блокировки пользователей (история сохраняется, действующей считается не снятая и не истёкшая запись)
+CREATE TABLE u_bans
+(
+    id         SERIAL PRIMARY KEY,
+    uuid       VARCHAR(36) NOT NULL UNIQUE,
+    users_id   INT         NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    kind       VARCHAR(16) NOT NULL,
+    reason     TEXT        NOT NULL,
+    banned_by  INT         REFERENCES u_users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP,
+    lifted_at  TIMESTAMP,
+    lifted_by  INT         REFERENCES u_users (id) ON DELETE SET NULL
+);
+
+CREATE INDEX u_bans_users_id_idx ON u_bans (users_id);
@@ -0,0 +1,10 @@
+-- Счётчики неудачных попыток входа и восстановления пароля (по аккаунту и по IP-адресу)
+CREATE TABLE u_login_attempts
+(
+    attempt_key  VARCHAR(320) PRIMARY KEY,
+    failures     INT          NOT NULL DEFAULT 0,
+    last_failure TIMESTAMP    NOT NULL,
+    locked_until TIMESTAMP
+);
+
+CREATE INDEX u_login_attempts_last_failure_idx ON u_login_attempts (last_failure);
@@ -0,0 +1,10 @@
+-- История паролей пользователя (для запрета повторного использования последних паролей)
+CREATE TABLE u_password_history
+(
+    id            SERIAL PRIMARY KEY,
+    users_id      INT          NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at    TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX u_password_history_users_id_idx ON u_password_history (users_id, created_at DESC);
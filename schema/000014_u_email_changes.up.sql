@@ -0,0 +1,18 @@
+-- Запросы на смену email-адреса пользователя (адрес меняется только после подтверждения по ссылке на новый адрес)
+CREATE TABLE u_email_changes
+(
+    id           SERIAL PRIMARY KEY,
+    uuid         VARCHAR(36)  NOT NULL UNIQUE,
+    users_id     INT          NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    old_email    VARCHAR(255) NOT NULL,
+    new_email    VARCHAR(255) NOT NULL,
+    confirm_link VARCHAR(36)  NOT NULL UNIQUE,
+    cancel_link  VARCHAR(36)  NOT NULL UNIQUE,
+    session_uuid VARCHAR(36),
+    created_at   TIMESTAMP    NOT NULL DEFAULT NOW(),
+    expires_at   TIMESTAMP    NOT NULL,
+    confirmed_at TIMESTAMP,
+    cancelled_at TIMESTAMP
+);
+
+CREATE INDEX u_email_changes_users_id_idx ON u_email_changes (users_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS u_api_keys;
+DROP TABLE IF EXISTS u_service_accounts;
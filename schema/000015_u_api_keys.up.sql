@@ -0,0 +1,31 @@
+-- Сервисные аккаунты (пользователи без пароля для межсервисного взаимодействия)
+CREATE TABLE u_service_accounts
+(
+    id          SERIAL PRIMARY KEY,
+    uuid        VARCHAR(36)  NOT NULL UNIQUE,
+    users_id    INT          NOT NULL UNIQUE REFERENCES u_users (id) ON DELETE CASCADE,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT         NOT NULL DEFAULT '',
+    created_by  INT          REFERENCES u_users (id) ON DELETE SET NULL,
+    created_at  TIMESTAMP    NOT NULL DEFAULT NOW(),
+    deleted_at  TIMESTAMP
+);
+
+-- API-ключи пользователей и сервисных аккаунтов (хранится только SHA-256 хэш ключа)
+CREATE TABLE u_api_keys
+(
+    id           SERIAL PRIMARY KEY,
+    uuid         VARCHAR(36)  NOT NULL UNIQUE,
+    users_id     INT          NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    name         VARCHAR(255) NOT NULL,
+    prefix       VARCHAR(16)  NOT NULL,
+    key_hash     VARCHAR(64)  NOT NULL UNIQUE,
+    scopes       TEXT[]       NOT NULL DEFAULT '{}',
+    created_by   INT          REFERENCES u_users (id) ON DELETE SET NULL,
+    created_at   TIMESTAMP    NOT NULL DEFAULT NOW(),
+    expires_at   TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at   TIMESTAMP
+);
+
+CREATE INDEX u_api_keys_users_id_idx ON u_api_keys (users_id);
@@ -0,0 +1,7 @@
+DELETE FROM u_auth_types at
+WHERE at.value = 'client_credentials'
+  AND NOT EXISTS (SELECT 1 FROM u_users_auth_types uat WHERE uat.auth_types_id = at.id);
+
+ALTER TABLE oidc_clients
+    DROP COLUMN IF EXISTS service_accounts_id,
+    DROP COLUMN IF EXISTS scopes;
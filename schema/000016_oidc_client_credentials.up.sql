@@ -0,0 +1,9 @@
+-- Клиенты с сервисным аккаунтом получают токены по client_credentials в пределах разрешённых областей доступа
+ALTER TABLE oidc_clients
+    ADD COLUMN scopes              TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN service_accounts_id INT REFERENCES u_service_accounts (id) ON DELETE CASCADE;
+
+-- Тип авторизации токенов, выданных клиентам по client_credentials
+INSERT INTO u_auth_types (uuid, value)
+SELECT md5(random()::text || clock_timestamp()::text)::uuid, 'client_credentials'
+WHERE NOT EXISTS (SELECT 1 FROM u_auth_types at WHERE at.value = 'client_credentials');
@@ -0,0 +1,8 @@
+-- Отозванные токены доступа (запись хранится до истечения срока действия токена)
+CREATE TABLE u_tokens_denylist
+(
+    token_id   VARCHAR(255) PRIMARY KEY,
+    expires_at TIMESTAMP    NOT NULL
+);
+
+CREATE INDEX u_tokens_denylist_expires_at_idx ON u_tokens_denylist (expires_at);
@@ -0,0 +1,12 @@
+-- Незавершённые входы, ожидающие второго фактора (запись удаляется при проверке токена ожидания)
+CREATE TABLE u_mfa_pending
+(
+    token_id    VARCHAR(255) PRIMARY KEY,
+    users_id    INT          NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    auth_type   VARCHAR(255) NOT NULL,
+    access_api  TEXT,
+    refresh_api TEXT,
+    expires_at  TIMESTAMP    NOT NULL
+);
+
+CREATE INDEX u_mfa_pending_expires_at_idx ON u_mfa_pending (expires_at);